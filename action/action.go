@@ -0,0 +1,115 @@
+// Package action provides the registry of named, documented actions that
+// keymaps, menus, external commands and the help popup resolve through.
+// [IMPL:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [REQ:ACTION_REGISTRY]
+package action
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/fareedst/goful/message"
+	"github.com/fareedst/goful/widget"
+)
+
+// Action is a named callback with a one-line description.
+type Action struct {
+	Name     string
+	Doc      string
+	callback func()
+}
+
+// Run calls the action.
+func (a *Action) Run() { a.callback() }
+
+var registry = map[string]*Action{}
+
+// Add actions as name, description and callback function and the number of
+// arguments `a' must be a multiple of three. A name added again replaces the
+// previous action.
+// [IMPL:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [REQ:ACTION_REGISTRY]
+func Add(a ...interface{}) {
+	if len(a)%3 != 0 {
+		panic("items must be a multiple of three")
+	}
+	for i := 0; i < len(a); i += 3 {
+		name := a[i].(string)
+		doc := a[i+1].(string)
+		callback := a[i+2].(func())
+		registry[name] = &Action{name, doc, callback}
+	}
+}
+
+// Lookup returns the action registered under name.
+func Lookup(name string) (*Action, bool) {
+	a, ok := registry[name]
+	return a, ok
+}
+
+// Exists reports whether an action is registered under name.
+func Exists(name string) bool {
+	_, ok := registry[name]
+	return ok
+}
+
+// Run runs the named action.
+// [IMPL:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [REQ:ACTION_REGISTRY]
+func Run(name string) error {
+	a, ok := registry[name]
+	if !ok {
+		return fmt.Errorf("unknown action %q", name)
+	}
+	a.Run()
+	return nil
+}
+
+// Func returns a callback running the named action. The name is resolved
+// when the callback is called, so bindings may be made before the action is
+// added; an unknown name is reported as an error message.
+// [IMPL:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [REQ:ACTION_REGISTRY]
+func Func(name string) func() {
+	return func() {
+		if err := Run(name); err != nil {
+			message.Errorf("[REQ:ACTION_REGISTRY] %v", err)
+		}
+	}
+}
+
+// Callback resolves a keymap or menu callback given either as a function or
+// as an action name.
+// [IMPL:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [REQ:ACTION_REGISTRY]
+func Callback(v interface{}) func() {
+	if name, ok := v.(string); ok {
+		return Func(name)
+	}
+	return v.(func())
+}
+
+// Keymap binds keys to the named actions of bindings (key to action name).
+// [IMPL:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [REQ:ACTION_REGISTRY]
+func Keymap(bindings map[string]string) widget.Keymap {
+	km := make(widget.Keymap, len(bindings))
+	for key, name := range bindings {
+		km[key] = Func(name)
+	}
+	return km
+}
+
+// Funcs returns a callback for every registered action by name.
+func Funcs() map[string]func() {
+	funcs := make(map[string]func(), len(registry))
+	for name := range registry {
+		funcs[name] = Func(name)
+	}
+	return funcs
+}
+
+// List returns the registered actions sorted by name.
+// [IMPL:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [REQ:ACTION_REGISTRY]
+func List() []*Action {
+	list := make([]*Action, 0, len(registry))
+	for _, a := range registry {
+		list = append(list, a)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
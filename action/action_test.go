@@ -0,0 +1,58 @@
+package action
+
+import "testing"
+
+// TestRegistry_REQ_ACTION_REGISTRY tests adding, replacing, running and listing actions.
+// [REQ:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [IMPL:ACTION_REGISTRY]
+func TestRegistry_REQ_ACTION_REGISTRY(t *testing.T) {
+	registry = map[string]*Action{}
+	calls := []string{}
+	Add(
+		"workspace.create", "Create workspace", func() { calls = append(calls, "create") },
+		"file.copy", "Copy", func() { calls = append(calls, "copy") },
+	)
+	Add("file.copy", "Copy files", func() { calls = append(calls, "copy2") })
+
+	if err := Run("file.copy"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Run("file.nope"); err == nil {
+		t.Error("unknown action should fail")
+	}
+	if a, ok := Lookup("file.copy"); !ok || a.Doc != "Copy files" {
+		t.Errorf("Lookup = %+v, %v", a, ok)
+	}
+
+	list := List()
+	if len(list) != 2 || list[0].Name != "file.copy" || list[1].Name != "workspace.create" {
+		t.Errorf("List = %v", list)
+	}
+
+	Keymap(map[string]string{"M-C-o": "workspace.create"})["M-C-o"]()
+	Callback("file.copy")()
+	Callback(func() { calls = append(calls, "func") })()
+	Funcs()["workspace.create"]()
+	want := []string{"copy2", "create", "copy2", "func", "create"}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Fatalf("calls = %v, want %v", calls, want)
+		}
+	}
+}
+
+// TestFuncResolvesLate_REQ_ACTION_REGISTRY tests that bindings made before an action is
+// added run the action added later.
+// [REQ:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [IMPL:ACTION_REGISTRY]
+func TestFuncResolvesLate_REQ_ACTION_REGISTRY(t *testing.T) {
+	registry = map[string]*Action{}
+	called := false
+	fn := Func("filer.cursor-down")
+	Add("filer.cursor-down", "Move cursor down", func() { called = true })
+	fn()
+	if !called {
+		t.Error("callback bound before Add should run the added action")
+	}
+}
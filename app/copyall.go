@@ -0,0 +1,84 @@
+// Package app Copy All / Move All prompts shared by every platform.
+// [IMPL:NSYNC_COPY_MOVE] [ARCH:NSYNC_INTEGRATION] [REQ:NSYNC_MULTI_TARGET]
+package app
+
+import (
+	"fmt"
+
+	"github.com/fareedst/goful/cmdline"
+	"github.com/fareedst/goful/message"
+)
+
+// CopyAll prompts for confirmation then copies selected files to all other visible workspace directories.
+// [IMPL:NSYNC_COPY_MOVE] [ARCH:NSYNC_INTEGRATION] [REQ:NSYNC_MULTI_TARGET]
+// [IMPL:NSYNC_CONFIRMATION] [ARCH:NSYNC_CONFIRMATION] [REQ:NSYNC_CONFIRMATION]
+func (g *Goful) CopyAll() {
+	destinations := otherWindowDirPaths(g.Workspace())
+
+	if len(destinations) == 0 {
+		// Fall back to regular copy when only one pane
+		message.Info("Only one pane visible - use regular copy (c)")
+		g.Copy()
+		return
+	}
+
+	// Collect sources from marks or cursor
+	var sources []string
+	if g.Dir().IsMark() {
+		sources = g.Dir().MarkfilePaths()
+		g.Dir().MarkClear()
+	} else {
+		file := g.File()
+		if file == nil {
+			message.Error(fmt.Errorf("no file selected"))
+			return
+		}
+		sources = []string{file.Path()}
+	}
+
+	if len(sources) == 0 {
+		message.Error(fmt.Errorf("no files to copy"))
+		return
+	}
+
+	// Start confirmation mode instead of executing immediately
+	// [IMPL:NSYNC_CONFIRMATION] [ARCH:NSYNC_CONFIRMATION] [REQ:NSYNC_CONFIRMATION]
+	g.next = cmdline.New(&copyAllMode{g, sources, destinations}, g)
+}
+
+// MoveAll prompts for confirmation then moves selected files to all other visible workspace directories.
+// [IMPL:NSYNC_COPY_MOVE] [ARCH:NSYNC_INTEGRATION] [REQ:NSYNC_MULTI_TARGET]
+// [IMPL:NSYNC_CONFIRMATION] [ARCH:NSYNC_CONFIRMATION] [REQ:NSYNC_CONFIRMATION]
+func (g *Goful) MoveAll() {
+	destinations := otherWindowDirPaths(g.Workspace())
+
+	if len(destinations) == 0 {
+		// Fall back to regular move when only one pane
+		message.Info("Only one pane visible - use regular move (m)")
+		g.Move()
+		return
+	}
+
+	// Collect sources from marks or cursor
+	var sources []string
+	if g.Dir().IsMark() {
+		sources = g.Dir().MarkfilePaths()
+		g.Dir().MarkClear()
+	} else {
+		file := g.File()
+		if file == nil {
+			message.Error(fmt.Errorf("no file selected"))
+			return
+		}
+		sources = []string{file.Path()}
+	}
+
+	if len(sources) == 0 {
+		message.Error(fmt.Errorf("no files to move"))
+		return
+	}
+
+	// Start confirmation mode instead of executing immediately
+	// [IMPL:NSYNC_CONFIRMATION] [ARCH:NSYNC_CONFIRMATION] [REQ:NSYNC_CONFIRMATION]
+	g.next = cmdline.New(&moveAllMode{g, sources, destinations}, g)
+}
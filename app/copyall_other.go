@@ -0,0 +1,21 @@
+//go:build !darwin
+// +build !darwin
+
+// Package app runs Copy All / Move All with the portable fanout engine where
+// the nsync SDK is not available.
+// [IMPL:FANOUT_COPY] [ARCH:FANOUT_COPY] [REQ:FANOUT_COPY]
+package app
+
+// doCopyAll executes the multi-target copy after confirmation.
+// [IMPL:NSYNC_CONFIRMATION] [ARCH:NSYNC_CONFIRMATION] [REQ:NSYNC_CONFIRMATION]
+// [IMPL:FANOUT_COPY] [ARCH:FANOUT_COPY] [REQ:FANOUT_COPY]
+func (g *Goful) doCopyAll(sources, destinations []string) {
+	g.fanoutTransfer(sources, destinations, false)
+}
+
+// doMoveAll executes the multi-target move after confirmation.
+// [IMPL:NSYNC_CONFIRMATION] [ARCH:NSYNC_CONFIRMATION] [REQ:NSYNC_CONFIRMATION]
+// [IMPL:FANOUT_COPY] [ARCH:FANOUT_COPY] [REQ:FANOUT_COPY]
+func (g *Goful) doMoveAll(sources, destinations []string) {
+	g.fanoutTransfer(sources, destinations, true)
+}
@@ -0,0 +1,448 @@
+// Package app portable multi-destination copy and move for Copy All / Move All.
+// [IMPL:FANOUT_COPY] [ARCH:FANOUT_COPY] [REQ:FANOUT_COPY]
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fareedst/goful/filer"
+	"github.com/fareedst/goful/message"
+	"github.com/fareedst/goful/progress"
+	"github.com/fareedst/goful/util"
+	"github.com/fareedst/goful/widget"
+)
+
+// fanoutChunk is the size of the buffer read from a source and written to
+// every destination at once.
+const fanoutChunk = 32 * 1024
+
+// fanout copies sources into several destination directories. Each source
+// file is read once and every chunk is written to all destinations
+// concurrently. A destination that fails is dropped for the rest of that
+// subtree; the others carry on.
+// [IMPL:FANOUT_COPY] [ARCH:FANOUT_COPY] [REQ:FANOUT_COPY]
+type fanout struct {
+	dsts     []string // destination directories
+	panes    []int    // window index of each destination, for reporting
+	move     bool     // remove sources that reached every destination
+	errs     []error  // first failure per destination
+	items    int      // files and symlinks transferred
+	upToDate int      // per-destination copies skipped because size and mtime match
+	bytes    int64    // source bytes read
+}
+
+// newFanout creates a fanout to dsts; panes[i] is the window showing dsts[i].
+func newFanout(dsts []string, panes []int, move bool) *fanout {
+	return &fanout{dsts: dsts, panes: panes, move: move, errs: make([]error, len(dsts))}
+}
+
+// run transfers every source into each destination directory.
+// [IMPL:FANOUT_COPY] [ARCH:FANOUT_COPY] [REQ:FANOUT_COPY]
+func (f *fanout) run(sources []string) {
+	size, count := util.CalcSizeCount(sources...)
+	progress.Start(float64(size))
+	progress.StartTaskCount(count)
+	defer progress.Finish()
+
+	quit := make(chan struct{})
+	defer close(quit)
+	go func() { // drawing progress
+		ticker := time.NewTicker(50 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				progress.Draw()
+				widget.Show()
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	live := make([]int, len(f.dsts))
+	for i := range live {
+		live[i] = i
+	}
+	for _, src := range sources {
+		targets := make([]string, len(f.dsts))
+		for i, dst := range f.dsts {
+			targets[i] = filepath.Join(dst, filepath.Base(src))
+		}
+		f.transfer(src, targets, live)
+	}
+}
+
+// fail records err as the first failure of destination i.
+func (f *fanout) fail(i int, err error) {
+	if f.errs[i] == nil {
+		f.errs[i] = err
+	}
+}
+
+// removable reports whether a moved source may be removed: every destination
+// received it, including those dropped higher up the tree.
+func (f *fanout) removable(live []int, ok bool) bool {
+	return f.move && ok && len(live) == len(f.dsts)
+}
+
+// failAll records err for every destination in live.
+func (f *fanout) failAll(live []int, err error) {
+	for _, i := range live {
+		f.fail(i, err)
+	}
+}
+
+// transfer copies src to targets[i] for each destination i in live and
+// reports whether all of them succeeded.
+func (f *fanout) transfer(src string, targets []string, live []int) bool {
+	stat, err := os.Lstat(src)
+	if err != nil {
+		f.failAll(live, err)
+		return false
+	}
+
+	ok := true
+	var next []int
+	for _, i := range live {
+		if targets[i] == src || (stat.IsDir() && strings.HasPrefix(targets[i], src+string(filepath.Separator))) {
+			f.fail(i, fmt.Errorf("cannot copy/move %s into itself %s", src, targets[i]))
+			ok = false
+			continue
+		}
+		next = append(next, i)
+	}
+	if len(next) == 0 {
+		return false // nothing left to receive src; never remove it on move
+	}
+
+	switch {
+	case stat.IsDir():
+		return f.transferDir(src, stat, targets, next) && ok
+	case stat.Mode()&os.ModeSymlink != 0:
+		return f.transferSymlink(src, targets, next) && ok
+	default:
+		return f.transferFile(src, stat, targets, next) && ok
+	}
+}
+
+// transferDir creates or merges the directory in each destination, then
+// transfers its entries and copies its times. A symlink in a destination is
+// replaced by the directory rather than followed. Created directories stay
+// owner-writable until their entries are in place, then get the source mode.
+func (f *fanout) transferDir(src string, stat os.FileInfo, targets []string, live []int) bool {
+	ok := true
+	var next []int
+	created := make(map[int]bool)
+	for _, i := range live {
+		if dst, err := os.Lstat(targets[i]); err == nil {
+			if dst.Mode()&os.ModeSymlink != 0 {
+				if err := os.Remove(targets[i]); err != nil {
+					f.fail(i, err)
+					ok = false
+					continue
+				}
+			} else if !dst.IsDir() {
+				f.fail(i, fmt.Errorf("cannot overwrite non-directory %s with directory %s", targets[i], src))
+				ok = false
+				continue
+			} else {
+				next = append(next, i)
+				continue
+			}
+		}
+		if err := os.Mkdir(targets[i], stat.Mode().Perm()|0700); err != nil {
+			f.fail(i, err)
+			ok = false
+			continue
+		}
+		created[i] = true
+		next = append(next, i)
+	}
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		f.failAll(next, err)
+		return false
+	}
+	for _, e := range entries {
+		children := make([]string, len(targets))
+		for _, i := range next {
+			children[i] = filepath.Join(targets[i], e.Name())
+		}
+		if !f.transfer(filepath.Join(src, e.Name()), children, next) {
+			ok = false
+		}
+	}
+
+	for _, i := range next {
+		if created[i] {
+			if err := os.Chmod(targets[i], stat.Mode().Perm()); err != nil {
+				f.fail(i, err)
+				ok = false
+				continue
+			}
+		}
+		if err := copyTimes(src, targets[i]); err != nil {
+			f.fail(i, err)
+			ok = false
+		}
+	}
+	if f.removable(live, ok) {
+		if err := removeEmptyDir(src); err != nil {
+			f.failAll(next, err)
+			return false
+		}
+	}
+	return ok
+}
+
+// transferSymlink recreates the link in each destination, replacing what is there.
+func (f *fanout) transferSymlink(src string, targets []string, live []int) bool {
+	ok := true
+	for _, i := range live {
+		if dst, err := os.Lstat(targets[i]); err == nil {
+			if dst.IsDir() {
+				f.fail(i, fmt.Errorf("cannot overwrite directory %s with symlink %s", targets[i], src))
+				ok = false
+				continue
+			}
+			if err := os.Remove(targets[i]); err != nil {
+				f.fail(i, err)
+				ok = false
+				continue
+			}
+		}
+		if err := copySymlink(src, targets[i]); err != nil {
+			f.fail(i, err)
+			ok = false
+		}
+	}
+	f.items++
+	if f.removable(live, ok) {
+		if err := os.Remove(src); err != nil {
+			f.failAll(live, err)
+			return false
+		}
+	}
+	return ok
+}
+
+// fanoutOutput is one open destination of a file being transferred.
+type fanoutOutput struct {
+	index int
+	file  *os.File
+	err   error
+}
+
+// transferFile reads src once and writes each chunk to every destination
+// that is not already up to date. A symlink in a destination is replaced by
+// the file rather than written through.
+func (f *fanout) transferFile(src string, stat os.FileInfo, targets []string, live []int) bool {
+	progress.StartTask(stat)
+	defer progress.FinishTask()
+
+	ok := true
+	var outs []*fanoutOutput
+	for _, i := range live {
+		if dst, err := os.Lstat(targets[i]); err == nil {
+			if dst.IsDir() {
+				f.fail(i, fmt.Errorf("cannot overwrite directory %s with file %s", targets[i], src))
+				ok = false
+				continue
+			}
+			if dst.Mode()&os.ModeSymlink != 0 {
+				if err := os.Remove(targets[i]); err != nil {
+					f.fail(i, err)
+					ok = false
+					continue
+				}
+			} else if dst.Size() == stat.Size() && dst.ModTime().Equal(stat.ModTime()) {
+				f.upToDate++
+				continue
+			}
+		}
+		file, err := os.OpenFile(targets[i], os.O_WRONLY|os.O_CREATE|os.O_TRUNC, stat.Mode().Perm())
+		if err != nil {
+			f.fail(i, err)
+			ok = false
+			continue
+		}
+		outs = append(outs, &fanoutOutput{index: i, file: file})
+	}
+
+	if len(outs) > 0 {
+		if err := f.fanoutCopy(src, outs); err != nil {
+			for _, out := range outs {
+				if out.err == nil {
+					out.err = err
+				}
+			}
+		}
+		for _, out := range outs {
+			if err := out.file.Close(); err != nil && out.err == nil {
+				out.err = err
+			}
+			if out.err == nil {
+				out.err = copyTimes(src, targets[out.index])
+			}
+			if out.err != nil {
+				f.fail(out.index, out.err)
+				ok = false
+			}
+		}
+		f.items++
+	} else {
+		progress.Update(float64(stat.Size()))
+	}
+
+	if f.removable(live, ok) {
+		if err := os.Remove(src); err != nil {
+			f.failAll(live, err)
+			return false
+		}
+	}
+	return ok
+}
+
+// fanoutCopy streams src into every output that has not failed. An error
+// reading the source is returned; write errors are recorded on the outputs.
+func (f *fanout) fanoutCopy(src string, outs []*fanoutOutput) error {
+	srcfile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer srcfile.Close()
+
+	buf := make([]byte, fanoutChunk)
+	for {
+		n, err := srcfile.Read(buf)
+		if n > 0 {
+			var wg sync.WaitGroup
+			for _, out := range outs {
+				if out.err != nil {
+					continue
+				}
+				wg.Add(1)
+				go func(out *fanoutOutput) {
+					defer wg.Done()
+					_, out.err = out.file.Write(buf[:n])
+				}(out)
+			}
+			wg.Wait()
+			f.bytes += int64(n)
+			progress.Update(float64(n))
+		}
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// result summarizes the transfer per destination window.
+// [IMPL:FANOUT_COPY] [ARCH:FANOUT_COPY] [REQ:FANOUT_COPY]
+func (f *fanout) result() SyncResult {
+	var result SyncResult
+	for i, err := range f.errs {
+		if err != nil {
+			result.Failures = append(result.Failures, SyncFailure{f.panes[i], err})
+		} else {
+			result.Succeeded++
+		}
+	}
+	return result
+}
+
+// otherWindowIndexes returns the window indexes matching otherWindowDirPaths.
+// [IMPL:FANOUT_COPY] [ARCH:FANOUT_COPY] [REQ:FANOUT_COPY]
+func otherWindowIndexes(ws *filer.Workspace) []int {
+	if ws == nil || len(ws.Dirs) <= 1 {
+		return nil
+	}
+
+	indexes := make([]int, 0, len(ws.Dirs)-1)
+	for offset := 1; offset < len(ws.Dirs); offset++ {
+		indexes = append(indexes, (ws.Focus+offset)%len(ws.Dirs))
+	}
+	return indexes
+}
+
+// fanoutTransfer copies or moves sources to every destination directory in
+// the background and reports a per-destination summary.
+// [IMPL:FANOUT_COPY] [ARCH:FANOUT_COPY] [REQ:FANOUT_COPY]
+func (g *Goful) fanoutTransfer(sources, destinations []string, move bool) {
+	srcAbs := make([]string, len(sources))
+	for i, src := range sources {
+		srcAbs[i], _ = filepath.Abs(src)
+	}
+	destAbs := make([]string, len(destinations))
+	for i, dst := range destinations {
+		destAbs[i], _ = filepath.Abs(dst)
+	}
+	panes := otherWindowIndexes(g.Workspace())
+	if len(panes) != len(destAbs) {
+		panes = make([]int, len(destAbs))
+		for i := range panes {
+			panes[i] = i
+		}
+	}
+
+	g.asyncFilectrl(func() {
+		start := time.Now()
+		f := newFanout(destAbs, panes, move)
+		f.run(srcAbs)
+		reportFanout(f, time.Since(start))
+	})
+}
+
+// reportFanout displays the outcome of a fanout.
+func reportFanout(f *fanout, elapsed time.Duration) {
+	op := "Copied"
+	if f.move {
+		op = "Moved"
+	}
+	result := f.result()
+	if len(result.Failures) == 0 {
+		msg := fmt.Sprintf("%s %d items (%s) to %d destinations in %s",
+			op, f.items, formatBytes(f.bytes), result.Succeeded, elapsed.Round(time.Millisecond))
+		if f.upToDate > 0 {
+			msg += fmt.Sprintf(", %d already up to date", f.upToDate)
+		}
+		message.Info(msg)
+		return
+	}
+
+	failed := make([]string, len(result.Failures))
+	for i, failure := range result.Failures {
+		failed[i] = fmt.Sprintf("window %d: %v", failure.PaneIndex+1, failure.Error)
+	}
+	kept := ""
+	if f.move {
+		kept = "; sources kept where a destination failed"
+	}
+	message.Errorf("%s %d items: %d destinations succeeded, %d failed (%s)%s",
+		op, f.items, result.Succeeded, len(result.Failures), strings.Join(failed, "; "), kept)
+}
+
+// formatBytes formats bytes as a human-readable string.
+// [IMPL:NSYNC_OBSERVER] [ARCH:NSYNC_INTEGRATION] [REQ:NSYNC_MULTI_TARGET]
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%dB", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
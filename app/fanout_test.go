@@ -0,0 +1,220 @@
+// Package app portable Copy All / Move All tests.
+// [IMPL:FANOUT_COPY] [ARCH:FANOUT_COPY] [REQ:FANOUT_COPY]
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fareedst/goful/progress"
+)
+
+// newFanoutFixture creates a source directory and n empty destinations.
+func newFanoutFixture(t *testing.T, n int) (string, []string, []int) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	src := filepath.Join(tmpDir, "src")
+	os.MkdirAll(filepath.Join(src, "dir", "sub"), 0755)
+	os.WriteFile(filepath.Join(src, "big.bin"), []byte(strings.Repeat("x", 3*fanoutChunk+17)), 0640)
+	os.WriteFile(filepath.Join(src, "dir", "sub", "a.txt"), []byte("a"), 0644)
+	os.Symlink("sub/a.txt", filepath.Join(src, "dir", "link"))
+
+	dsts := make([]string, n)
+	panes := make([]int, n)
+	for i := range dsts {
+		dsts[i] = filepath.Join(tmpDir, "dst"+string(rune('1'+i)))
+		os.MkdirAll(dsts[i], 0755)
+		panes[i] = i + 1
+	}
+	return src, dsts, panes
+}
+
+// TestFanoutCopy_REQ_FANOUT_COPY verifies files, directories and symlinks reach every destination.
+func TestFanoutCopy_REQ_FANOUT_COPY(t *testing.T) {
+	progress.Init()
+	src, dsts, panes := newFanoutFixture(t, 3)
+	sources := []string{filepath.Join(src, "big.bin"), filepath.Join(src, "dir")}
+
+	f := newFanout(dsts, panes, false)
+	f.run(sources)
+	if result := f.result(); result.Succeeded != 3 || len(result.Failures) != 0 {
+		t.Fatalf("result = %+v", result)
+	}
+	if f.items != 3 || f.bytes != int64(3*fanoutChunk+18) {
+		t.Errorf("items = %d, bytes = %d", f.items, f.bytes)
+	}
+
+	srcInfo, _ := os.Stat(filepath.Join(src, "big.bin"))
+	for _, dst := range dsts {
+		info, err := os.Stat(filepath.Join(dst, "big.bin"))
+		if err != nil || info.Size() != srcInfo.Size() || !info.ModTime().Equal(srcInfo.ModTime()) || info.Mode().Perm() != 0640 {
+			t.Errorf("%s/big.bin = %v, %v", dst, info, err)
+		}
+		if b, _ := os.ReadFile(filepath.Join(dst, "dir", "sub", "a.txt")); string(b) != "a" {
+			t.Errorf("%s/dir/sub/a.txt = %q", dst, b)
+		}
+		if link, _ := os.Readlink(filepath.Join(dst, "dir", "link")); link != "sub/a.txt" {
+			t.Errorf("%s/dir/link -> %q", dst, link)
+		}
+	}
+
+	// A second run finds everything up to date and writes nothing.
+	f = newFanout(dsts, panes, false)
+	f.run(sources)
+	if f.upToDate != 6 || f.bytes != 0 {
+		t.Errorf("second run: upToDate = %d, bytes = %d", f.upToDate, f.bytes)
+	}
+	for _, src := range sources {
+		if _, err := os.Stat(src); err != nil {
+			t.Errorf("copy removed source %s: %v", src, err)
+		}
+	}
+}
+
+// TestFanoutFailure_REQ_FANOUT_COPY verifies one failing destination does not stop the others
+// and keeps the source on move.
+func TestFanoutFailure_REQ_FANOUT_COPY(t *testing.T) {
+	progress.Init()
+	src, dsts, panes := newFanoutFixture(t, 3)
+	os.MkdirAll(filepath.Join(dsts[1], "big.bin"), 0755) // a directory blocks the file in window 3
+
+	f := newFanout(dsts, panes, true)
+	f.run([]string{filepath.Join(src, "big.bin")})
+	result := f.result()
+	if result.Succeeded != 2 || len(result.Failures) != 1 || result.Failures[0].PaneIndex != 2 {
+		t.Fatalf("result = %+v", result)
+	}
+	if !strings.Contains(result.Failures[0].Error.Error(), "cannot overwrite directory") {
+		t.Errorf("unexpected error %v", result.Failures[0].Error)
+	}
+	for _, i := range []int{0, 2} {
+		if info, err := os.Stat(filepath.Join(dsts[i], "big.bin")); err != nil || info.IsDir() {
+			t.Errorf("destination %d not written: %v", i, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(src, "big.bin")); err != nil {
+		t.Errorf("source removed although a destination failed: %v", err)
+	}
+}
+
+// TestFanoutReplacesSymlink_REQ_FANOUT_COPY verifies a symlink in a destination is
+// replaced by the file instead of truncating its target.
+func TestFanoutReplacesSymlink_REQ_FANOUT_COPY(t *testing.T) {
+	progress.Init()
+	src, dsts, panes := newFanoutFixture(t, 1)
+	victim := filepath.Join(filepath.Dir(dsts[0]), "victim.txt")
+	os.WriteFile(victim, []byte("keep"), 0644)
+	os.Symlink(victim, filepath.Join(dsts[0], "big.bin"))
+
+	f := newFanout(dsts, panes, false)
+	f.run([]string{filepath.Join(src, "big.bin")})
+	if result := f.result(); result.Succeeded != 1 {
+		t.Fatalf("result = %+v", result)
+	}
+	if b, _ := os.ReadFile(victim); string(b) != "keep" {
+		t.Errorf("symlink target overwritten: %q", b)
+	}
+	if info, err := os.Lstat(filepath.Join(dsts[0], "big.bin")); err != nil || !info.Mode().IsRegular() {
+		t.Errorf("destination = %v, %v, want a regular file", info, err)
+	}
+}
+
+// TestFanoutReplacesDirSymlink_REQ_FANOUT_COPY verifies a symlink to a directory in a
+// destination is replaced by the directory instead of written through.
+func TestFanoutReplacesDirSymlink_REQ_FANOUT_COPY(t *testing.T) {
+	progress.Init()
+	src, dsts, panes := newFanoutFixture(t, 1)
+	victim := filepath.Join(filepath.Dir(dsts[0]), "victim")
+	os.Mkdir(victim, 0755)
+	os.Symlink(victim, filepath.Join(dsts[0], "dir"))
+
+	f := newFanout(dsts, panes, false)
+	f.run([]string{filepath.Join(src, "dir")})
+	if result := f.result(); result.Succeeded != 1 {
+		t.Fatalf("result = %+v", result)
+	}
+	if entries, _ := os.ReadDir(victim); len(entries) != 0 {
+		t.Errorf("copied through the symlink into %s: %v", victim, entries)
+	}
+	if info, err := os.Lstat(filepath.Join(dsts[0], "dir")); err != nil || !info.IsDir() {
+		t.Errorf("destination = %v, %v, want a directory", info, err)
+	}
+}
+
+// TestFanoutReadOnlyDir_REQ_FANOUT_COPY verifies a read-only source directory is
+// filled before its mode is applied.
+func TestFanoutReadOnlyDir_REQ_FANOUT_COPY(t *testing.T) {
+	progress.Init()
+	src, dsts, panes := newFanoutFixture(t, 2)
+	ro := filepath.Join(src, "dir", "sub")
+	os.Chmod(ro, 0555)
+	t.Cleanup(func() {
+		os.Chmod(ro, 0755)
+		for _, dst := range dsts {
+			os.Chmod(filepath.Join(dst, "dir", "sub"), 0755)
+		}
+	})
+
+	f := newFanout(dsts, panes, false)
+	f.run([]string{filepath.Join(src, "dir")})
+	if result := f.result(); result.Succeeded != 2 {
+		t.Fatalf("result = %+v", result)
+	}
+	for _, dst := range dsts {
+		if b, _ := os.ReadFile(filepath.Join(dst, "dir", "sub", "a.txt")); string(b) != "a" {
+			t.Errorf("%s/dir/sub/a.txt = %q", dst, b)
+		}
+		if info, err := os.Stat(filepath.Join(dst, "dir", "sub")); err != nil || info.Mode().Perm() != 0555 {
+			t.Errorf("%s/dir/sub = %v, %v, want mode 0555", dst, info, err)
+		}
+	}
+}
+
+// TestFanoutMove_REQ_FANOUT_COPY verifies move removes sources after every destination has them.
+func TestFanoutMove_REQ_FANOUT_COPY(t *testing.T) {
+	progress.Init()
+	src, dsts, panes := newFanoutFixture(t, 2)
+	// One destination already holds an identical copy of a.txt.
+	os.MkdirAll(filepath.Join(dsts[0], "dir", "sub"), 0755)
+	os.WriteFile(filepath.Join(dsts[0], "dir", "sub", "a.txt"), []byte("a"), 0644)
+	mtime := time.Now().Add(-time.Hour)
+	os.Chtimes(filepath.Join(src, "dir", "sub", "a.txt"), mtime, mtime)
+	os.Chtimes(filepath.Join(dsts[0], "dir", "sub", "a.txt"), mtime, mtime)
+
+	f := newFanout(dsts, panes, true)
+	f.run([]string{filepath.Join(src, "dir")})
+	if result := f.result(); result.Succeeded != 2 {
+		t.Fatalf("result = %+v", result)
+	}
+	if f.upToDate != 1 {
+		t.Errorf("upToDate = %d, want 1", f.upToDate)
+	}
+	if _, err := os.Lstat(filepath.Join(src, "dir")); !os.IsNotExist(err) {
+		t.Errorf("source directory not removed: %v", err)
+	}
+	for _, dst := range dsts {
+		if b, _ := os.ReadFile(filepath.Join(dst, "dir", "sub", "a.txt")); string(b) != "a" {
+			t.Errorf("%s/dir/sub/a.txt = %q", dst, b)
+		}
+	}
+}
+
+// TestFanoutIntoItself_REQ_FANOUT_COPY verifies a destination inside the source is refused.
+func TestFanoutIntoItself_REQ_FANOUT_COPY(t *testing.T) {
+	progress.Init()
+	src, dsts, panes := newFanoutFixture(t, 1)
+	inside := filepath.Join(src, "dir", "sub")
+
+	f := newFanout([]string{inside, dsts[0]}, []int{0, panes[0]}, true)
+	f.run([]string{filepath.Join(src, "dir")})
+	result := f.result()
+	if result.Succeeded != 1 || len(result.Failures) != 1 || result.Failures[0].PaneIndex != 0 {
+		t.Fatalf("result = %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(src, "dir", "sub", "a.txt")); err != nil {
+		t.Errorf("source damaged: %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+//go:build !windows
+// +build !windows
+
+package app
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// freeSpace returns the bytes available to the user on the filesystem holding path.
+// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+func freeSpace(path string) (uint64, error) {
+	var statfs syscall.Statfs_t
+	if err := syscall.Statfs(path, &statfs); err != nil {
+		return 0, err
+	}
+	return statfs.Bavail * uint64(statfs.Bsize), nil
+}
+
+// filesystemID identifies the filesystem holding path by its device number.
+// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+func filesystemID(path string) (string, error) {
+	var stat syscall.Stat_t
+	if err := syscall.Stat(path, &stat); err != nil {
+		return "", err
+	}
+	return fmt.Sprint(stat.Dev), nil
+}
@@ -0,0 +1,37 @@
+//go:build windows
+// +build windows
+
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// freeSpace returns the bytes available to the user on the volume holding path.
+// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+func freeSpace(path string) (uint64, error) {
+	h := syscall.MustLoadDLL("kernel32.dll")
+	c := h.MustFindProc("GetDiskFreeSpaceExW")
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	var free uint64
+	if r, _, err := c.Call(uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(&free)), 0, 0); r == 0 {
+		return 0, err
+	}
+	return free, nil
+}
+
+// filesystemID identifies the volume holding path by its volume name.
+// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+func filesystemID(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(filepath.VolumeName(abs)), nil
+}
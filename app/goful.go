@@ -9,10 +9,12 @@ import (
 	"sync"
 	"time"
 
+	"github.com/fareedst/goful/diffresults"
 	"github.com/fareedst/goful/diffstatus"
 	"github.com/fareedst/goful/filer"
 	"github.com/fareedst/goful/help"
 	"github.com/fareedst/goful/info"
+	"github.com/fareedst/goful/jobs"
 	"github.com/fareedst/goful/menu"
 	"github.com/fareedst/goful/message"
 	"github.com/fareedst/goful/progress"
@@ -40,8 +42,13 @@ type Goful struct {
 	callback           chan func()
 	task               chan int
 	exit               bool
-	linkedNav          bool // [IMPL:LINKED_NAVIGATION] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_NAVIGATION] Linked navigation mode state
-	syncIgnoreFailures bool // [IMPL:TOOLBAR_IGNORE_FAILURES] [ARCH:TOOLBAR_LAYOUT] [REQ:TOOLBAR_SYNC_BUTTONS] Persistent ignore-failures mode for sync operations
+	linkedNav          bool          // [IMPL:LINKED_NAVIGATION] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_NAVIGATION] Linked navigation mode state
+	syncIgnoreFailures bool          // [IMPL:TOOLBAR_IGNORE_FAILURES] [ARCH:TOOLBAR_LAYOUT] [REQ:TOOLBAR_SYNC_BUTTONS] Persistent ignore-failures mode for sync operations
+	syncAtomic         bool          // [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION] Persistent all-or-nothing mode for sync operations
+	diffCollecting     bool          // [IMPL:DIFF_RESULTS_PANEL] [ARCH:DIFF_RESULTS_PANEL] [REQ:DIFF_RESULTS_PANEL] Background difference collection in progress
+	lastDiff           *reportedDiff // [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE] Difference last shown to the user, the target of Reconcile
+	// Processes started by %& [IMPL:BACKGROUND_JOBS] [ARCH:BACKGROUND_JOBS] [REQ:BACKGROUND_JOBS]
+	jobs *jobs.List
 	// Double-click state tracking [IMPL:MOUSE_DOUBLE_CLICK] [ARCH:MOUSE_DOUBLE_CLICK] [REQ:MOUSE_DOUBLE_CLICK]
 	lastClickTime time.Time
 	lastClickX    int
@@ -73,6 +80,7 @@ func NewGoful(path string) *Goful {
 		task:      make(chan int, 1),
 		exit:      false,
 		linkedNav: true, // [IMPL:LINKED_NAVIGATION] Enabled by default
+		jobs:      &jobs.List{},
 		pollStop:  make(chan struct{}),
 	}
 	return goful
@@ -419,7 +427,7 @@ func (g *Goful) handleLeftClick(x, y int) {
 		// Sync cursor to same filename in all other windows when linked mode is ON
 		if g.IsLinkedNav() {
 			filename := dir.File().Name()
-			ws.SetCursorByNameAll(filename)
+			ws.SetCursorByNameLinked(filename) // [IMPL:LINK_GROUPS] [ARCH:LINKED_NAVIGATION] [REQ:LINK_GROUPS]
 		}
 	}
 
@@ -443,10 +451,9 @@ func (g *Goful) handleDoubleClickDir(dir *filer.Directory) {
 		name := dir.File().Name()
 		// Navigate other directories but DON'T rebuild index yet
 		navigated, skipped := g.Workspace().ChdirAllToSubdirNoRebuild(name)
-		// [IMPL:LINKED_NAVIGATION_AUTO_DISABLE] Auto-disable if any window couldn't navigate
+		// [IMPL:LINKED_NAVIGATION_AUTO_DISABLE] Auto-disable the group if any window couldn't navigate
 		if skipped > 0 {
-			g.SetLinkedNav(false)
-			message.Infof("linked navigation disabled: %d window(s) missing '%s'", skipped, name)
+			g.UnlinkDiverged(skipped, name)
 		}
 		_ = navigated
 	}
@@ -466,7 +473,11 @@ func (g *Goful) handleDoubleClickFile(dir *filer.Directory) {
 	if g.IsLinkedNav() {
 		// Collect all file paths from windows that have the same-named file
 		var filePaths []string
-		for _, d := range g.Workspace().Dirs {
+		ws := g.Workspace()
+		for i, d := range ws.Dirs {
+			if !ws.IsLinkedTo(i) { // [IMPL:LINK_GROUPS] [ARCH:LINKED_NAVIGATION] [REQ:LINK_GROUPS]
+				continue
+			}
 			if found := d.FindFileByName(filename); found != nil {
 				d.SetCursorByName(filename)
 				filePaths = append(filePaths, found.Path())
@@ -519,7 +530,7 @@ func (g *Goful) handleWheelDown(x, y int) {
 // ToggleLinkedNav toggles the linked navigation mode and returns the new state.
 // [IMPL:LINKED_NAVIGATION] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_NAVIGATION]
 func (g *Goful) ToggleLinkedNav() bool {
-	g.linkedNav = !g.linkedNav
+	g.SetLinkedNav(!g.linkedNav)
 	return g.linkedNav
 }
 
@@ -529,12 +540,37 @@ func (g *Goful) IsLinkedNav() bool {
 	return g.linkedNav
 }
 
-// SetLinkedNav sets the linked navigation mode state.
+// SetLinkedNav sets the linked navigation mode state. Enabling it makes the
+// current directories of the panes their link roots.
 // [IMPL:LINKED_NAVIGATION_AUTO_DISABLE] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_NAVIGATION]
 func (g *Goful) SetLinkedNav(enabled bool) {
+	if enabled && !g.linkedNav {
+		for _, ws := range g.Workspaces {
+			ws.SetLinkRoots() // [IMPL:LINKED_CHDIR]
+		}
+	}
 	g.linkedNav = enabled
 }
 
+// UnlinkDiverged stops linked navigation for the focused link group after
+// skipped of its panes could not follow a chdir into name. Without link
+// groups linked mode is turned off; otherwise only the diverged group's
+// panes become independent and the other groups stay linked.
+// [IMPL:LINKED_NAVIGATION_AUTO_DISABLE] [IMPL:LINK_GROUPS] [ARCH:LINKED_NAVIGATION] [REQ:LINK_GROUPS]
+func (g *Goful) UnlinkDiverged(skipped int, name string) {
+	ws := g.Workspace()
+	if !ws.HasLinkGroups() {
+		g.SetLinkedNav(false)
+		message.Infof("linked navigation disabled: %d window(s) missing '%s'", skipped, name)
+		return
+	}
+	group := ws.UnlinkGroup()
+	if group == "" {
+		group = "default"
+	}
+	message.Infof("[REQ:LINK_GROUPS] link group %s unlinked: %d window(s) missing '%s'", group, skipped, name)
+}
+
 // IsSyncIgnoreFailures returns true if ignore-failures mode is enabled for sync operations.
 // [IMPL:TOOLBAR_IGNORE_FAILURES] [ARCH:TOOLBAR_LAYOUT] [REQ:TOOLBAR_SYNC_BUTTONS]
 func (g *Goful) IsSyncIgnoreFailures() bool {
@@ -548,48 +584,61 @@ func (g *Goful) ToggleSyncIgnoreFailures() bool {
 	return g.syncIgnoreFailures
 }
 
-// MoveCursorLinked moves cursor and syncs to other windows if linked mode is ON.
+// IsSyncAtomic returns true if sync operations run all-or-nothing.
+// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+func (g *Goful) IsSyncAtomic() bool {
+	return g.syncAtomic
+}
+
+// ToggleSyncAtomic toggles the all-or-nothing mode and returns the new state.
+// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+func (g *Goful) ToggleSyncAtomic() bool {
+	g.syncAtomic = !g.syncAtomic
+	return g.syncAtomic
+}
+
+// MoveCursorLinked moves cursor and syncs to the other windows of its link group if linked mode is ON.
 // [IMPL:LINKED_CURSOR_SYNC] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_NAVIGATION]
 func (g *Goful) MoveCursorLinked(amount int) {
 	g.Dir().MoveCursor(amount)
 	if g.IsLinkedNav() {
-		g.Workspace().SetCursorByNameAll(g.File().Name())
+		g.Workspace().SetCursorByNameLinked(g.File().Name())
 	}
 }
 
-// MoveTopLinked moves cursor to top and syncs to other windows if linked mode is ON.
+// MoveTopLinked moves cursor to top and syncs to the other windows of its link group if linked mode is ON.
 // [IMPL:LINKED_CURSOR_SYNC] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_NAVIGATION]
 func (g *Goful) MoveTopLinked() {
 	g.Dir().MoveTop()
 	if g.IsLinkedNav() {
-		g.Workspace().SetCursorByNameAll(g.File().Name())
+		g.Workspace().SetCursorByNameLinked(g.File().Name())
 	}
 }
 
-// MoveBottomLinked moves cursor to bottom and syncs to other windows if linked mode is ON.
+// MoveBottomLinked moves cursor to bottom and syncs to the other windows of its link group if linked mode is ON.
 // [IMPL:LINKED_CURSOR_SYNC] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_NAVIGATION]
 func (g *Goful) MoveBottomLinked() {
 	g.Dir().MoveBottom()
 	if g.IsLinkedNav() {
-		g.Workspace().SetCursorByNameAll(g.File().Name())
+		g.Workspace().SetCursorByNameLinked(g.File().Name())
 	}
 }
 
-// PageUpLinked moves cursor up a page and syncs to other windows if linked mode is ON.
+// PageUpLinked moves cursor up a page and syncs to the other windows of its link group if linked mode is ON.
 // [IMPL:LINKED_CURSOR_SYNC] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_NAVIGATION]
 func (g *Goful) PageUpLinked() {
 	g.Dir().PageUp()
 	if g.IsLinkedNav() {
-		g.Workspace().SetCursorByNameAll(g.File().Name())
+		g.Workspace().SetCursorByNameLinked(g.File().Name())
 	}
 }
 
-// PageDownLinked moves cursor down a page and syncs to other windows if linked mode is ON.
+// PageDownLinked moves cursor down a page and syncs to the other windows of its link group if linked mode is ON.
 // [IMPL:LINKED_CURSOR_SYNC] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_NAVIGATION]
 func (g *Goful) PageDownLinked() {
 	g.Dir().PageDown()
 	if g.IsLinkedNav() {
-		g.Workspace().SetCursorByNameAll(g.File().Name())
+		g.Workspace().SetCursorByNameLinked(g.File().Name())
 	}
 }
 
@@ -668,6 +717,59 @@ func (g *Goful) ContinueDiffSearch() {
 	g.findNextDiff(startAfter)
 }
 
+// PreviousDiffSearch steps the difference search backward from the cursor position
+// to the preceding difference, walking the tree in reverse depth-first order.
+// When no earlier difference exists the windows are returned to where they were.
+// [IMPL:DIFF_SEARCH_REVERSE] [ARCH:DIFF_SEARCH_REVERSE] [REQ:DIFF_SEARCH_REVERSE]
+func (g *Goful) PreviousDiffSearch() {
+	ws := g.Workspace()
+	if !ws.IsDiffSearchActive() {
+		message.Errorf("No active difference search. Use start diff search first.")
+		return
+	}
+
+	state := ws.DiffSearchState()
+	state.SetSearching(true)
+	state.SetCurrentPath(ws.Dir().Path)
+
+	// Same position rules as ContinueDiffSearch: prefer the last reported difference,
+	// which may be missing from the focused window, and fall back to the cursor.
+	var startBefore string
+	if state.LastDiffName != "" {
+		startBefore = strings.TrimSuffix(state.LastDiffName, "/")
+	} else {
+		startBefore = g.File().Name()
+		if startBefore == ".." {
+			startBefore = ""
+		}
+	}
+
+	// Remember where the windows are so a fruitless walk does not leave them at the root.
+	paths := make([]string, len(ws.Dirs))
+	for i, d := range ws.Dirs {
+		paths[i] = d.Path
+	}
+
+	nav := filer.NewWorkspaceNavigator(ws)
+	walker := filer.NewReverseTreeWalker(nav, state, startBefore)
+	step := g.runDiffWalk(state, nav, walker.Run)
+
+	switch step.Type {
+	case filer.StepFoundDiff:
+		g.showDiffStep(step)
+	case filer.StepComplete:
+		state.SetSearching(false)
+		for i, d := range ws.Dirs {
+			d.Chdir(paths[i])
+		}
+		ws.RebuildComparisonIndex()
+		if startBefore != "" {
+			ws.SetCursorByNameAll(startBefore)
+		}
+		message.Info("No earlier difference - reached the start of the search")
+	}
+}
+
 // findNextDiff is the core search loop that finds the next difference.
 // Uses the TreeWalker to handle traversal logic, keeping TUI concerns separate.
 // [IMPL:DIFF_SEARCH] [ARCH:DIFF_SEARCH] [REQ:DIFF_SEARCH]
@@ -676,6 +778,31 @@ func (g *Goful) findNextDiff(startAfter string) {
 	state := ws.DiffSearchState()
 	nav := filer.NewWorkspaceNavigator(ws)
 
+	// Create and run the tree walker
+	walker := filer.NewTreeWalker(nav, state, startAfter)
+	step := g.runDiffWalk(state, nav, walker.Run)
+
+	// Handle the result
+	switch step.Type {
+	case filer.StepFoundDiff:
+		g.showDiffStep(step)
+	case filer.StepComplete:
+		// Search complete - all differences have been found
+		// [IMPL:DIFF_SEARCH] Use ephemeral message for completion since diffstatus row disappears
+		ws.ClearDiffSearch()
+		g.lastDiff = nil
+		diffstatus.ClearMessage()
+		message.Info("Difference search complete - all differences found")
+		// Resize to reclaim space from diff status line
+		width, height := widget.Size()
+		g.Resize(0, 0, width, height)
+	}
+}
+
+// runDiffWalk runs a tree walk with periodic UI refresh and progress accounting.
+// [IMPL:DIFF_SEARCH] [ARCH:DIFF_SEARCH] [REQ:DIFF_SEARCH]
+// [IMPL:DIFF_SEARCH_REVERSE] [ARCH:DIFF_SEARCH_REVERSE] [REQ:DIFF_SEARCH_REVERSE]
+func (g *Goful) runDiffWalk(state *filer.DiffSearchState, nav filer.Navigator, run func(progressFn func()) filer.Step) filer.Step {
 	// Start periodic UI refresh goroutine (updates once per second)
 	// [IMPL:DIFF_SEARCH] Periodic refresh for progress display
 	quit := make(chan struct{})
@@ -694,31 +821,91 @@ func (g *Goful) findNextDiff(startAfter string) {
 		}
 	}()
 
-	// Create and run the tree walker
-	walker := filer.NewTreeWalker(nav, state, startAfter)
-	step := walker.Run(func() {
+	return run(func() {
 		state.IncrementFilesChecked()
 		state.SetCurrentPath(nav.CurrentPath())
 	})
+}
 
-	// Handle the result
-	switch step.Type {
-	case filer.StepFoundDiff:
-		// Found a difference - pause search and record result
-		// [IMPL:DIFF_SEARCH] Route status to dedicated diffstatus row, not ephemeral message
-		state.SetLastDiff(step.Name, step.Reason)
-		ws.SetCursorByNameAll(strings.TrimSuffix(step.Name, "/"))
-		diffstatus.SetMessage(fmt.Sprintf("Different: %s - %s", step.Name, step.Reason))
-	case filer.StepComplete:
-		// Search complete - all differences have been found
-		// [IMPL:DIFF_SEARCH] Use ephemeral message for completion since diffstatus row disappears
-		ws.ClearDiffSearch()
-		diffstatus.ClearMessage()
-		message.Info("Difference search complete - all differences found")
-		// Resize to reclaim space from diff status line
-		width, height := widget.Size()
-		g.Resize(0, 0, width, height)
+// showDiffStep pauses the search at a found difference and records it.
+// [IMPL:DIFF_SEARCH] [ARCH:DIFF_SEARCH] [REQ:DIFF_SEARCH]
+func (g *Goful) showDiffStep(step filer.Step) {
+	// [IMPL:DIFF_SEARCH] Route status to dedicated diffstatus row, not ephemeral message
+	state := g.Workspace().DiffSearchState()
+	state.SetLastDiff(step.Name, step.Reason)
+	g.setLastDiff(step.Name)
+	g.Workspace().SetCursorByNameAll(strings.TrimSuffix(step.Name, "/"))
+	diffstatus.SetMessage(fmt.Sprintf("Different: %s - %s", step.Name, step.Reason))
+}
+
+// DiffResults collects every difference across the workspace windows in the
+// background and opens a results panel when the traversal completes.
+// Selecting an entry navigates all windows to it.
+// [IMPL:DIFF_RESULTS_PANEL] [ARCH:DIFF_RESULTS_PANEL] [REQ:DIFF_RESULTS_PANEL]
+func (g *Goful) DiffResults() {
+	ws := g.Workspace()
+	if len(ws.Dirs) < 2 {
+		message.Errorf("Difference search requires at least 2 windows")
+		return
+	}
+	if g.diffCollecting {
+		message.Errorf("Difference collection is already running")
+		return
+	}
+	g.diffCollecting = true
+	message.Info("Collecting differences...")
+
+	// Snapshot the window paths here: the panes may change while the walk runs.
+	paths := make([]string, len(ws.Dirs))
+	for i, d := range ws.Dirs {
+		paths[i] = d.Path
 	}
+
+	go func() {
+		last := time.Now()
+		report, err := filer.RunBatchDiffSearch(paths, func(filesChecked, dirsTraversed, diffsFound int, currentPath string) {
+			if time.Since(last) < time.Second {
+				return
+			}
+			last = time.Now()
+			g.syncCallback(func() {
+				message.Infof("Collecting differences: %d files checked, %d found (%s)",
+					filesChecked, diffsFound, util.AbbrPath(currentPath))
+			})
+		})
+		g.syncCallback(func() {
+			g.diffCollecting = false
+			g.showDiffResults(ws, report, err)
+		})
+	}()
+}
+
+// showDiffResults opens the results panel for a finished collection.
+// [IMPL:DIFF_RESULTS_PANEL] [ARCH:DIFF_RESULTS_PANEL] [REQ:DIFF_RESULTS_PANEL]
+func (g *Goful) showDiffResults(ws *filer.Workspace, report *filer.DiffReport, err error) {
+	switch {
+	case err != nil:
+		message.Error(err)
+		return
+	case len(report.Differences) == 0:
+		message.Infof("No differences found (%d files checked)", report.TotalFilesChecked)
+		return
+	case !widget.IsNil(g.Next()):
+		// Do not replace a dialog the user opened while the search was running.
+		message.Infof("Found %d differences; close the current dialog and collect again to view them", len(report.Differences))
+		return
+	}
+
+	message.Infof("Found %d differences", len(report.Differences))
+	nav := filer.NewWorkspaceNavigator(ws)
+	g.next = diffresults.New(g, report, func(e filer.DiffEntry) {
+		if err := nav.NavigateToDiff(report.Directories, e); err != nil {
+			message.Error(err)
+			return
+		}
+		g.setLastDiff(e.Name)
+		message.Infof("Different: %s - %s", e.Path, e.Reason)
+	})
 }
 
 // SetBorderStyle sets the filer border style.
@@ -731,6 +918,32 @@ func (g *Goful) SetBorderStyle(style widget.BorderStyle) {
 	}
 }
 
+// ChdirLinked changes the focused directory to path. When linked mode is ON,
+// the other panes of the link group make the same relative move, and panes
+// that cannot follow are reported.
+// [IMPL:LINKED_CHDIR] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_CHDIR]
+func (g *Goful) ChdirLinked(path string) {
+	if !g.IsLinkedNav() {
+		g.Dir().Chdir(path)
+		return
+	}
+	ws := g.Workspace()
+	rel, missed, err := ws.ChdirAllRelativeNoRebuild(path)
+	if err != nil {
+		message.Error(err)
+		return
+	}
+	g.Dir().Chdir(path)
+	ws.RebuildComparisonIndex()
+	if len(missed) > 0 {
+		panes := make([]int, len(missed))
+		for i, idx := range missed {
+			panes[i] = idx + 1
+		}
+		message.Infof("[REQ:LINKED_CHDIR] linked chdir %s: panes %v could not follow", rel, panes)
+	}
+}
+
 // HandleParentButtonPress navigates to the parent directory, respecting Linked mode.
 // When Linked mode is ON, navigates all windows to their respective parent directories.
 // [IMPL:TOOLBAR_PARENT_BUTTON] [ARCH:TOOLBAR_LAYOUT] [REQ:TOOLBAR_PARENT_BUTTON]
@@ -9,6 +9,8 @@ import (
 	"strings"
 
 	"github.com/fareedst/goful/cmdline"
+	"github.com/fareedst/goful/externalcmd"
+	"github.com/fareedst/goful/filer"
 	"github.com/fareedst/goful/look"
 	"github.com/fareedst/goful/message"
 	"github.com/fareedst/goful/util"
@@ -21,26 +23,30 @@ var re = regexp.MustCompile(`([;|>&])|(%~?(?:[&mMfFxX]|[dD]2?))|([[:space:]]-[[:
 // Shell starts the shell mode.
 // The head of variadic arguments is used for cursor positioning.
 func (g *Goful) Shell(cmd string, offset ...int) {
-	commands, err := util.SearchCommands()
-	if err != nil {
-		message.Error(err)
-	}
-	c := cmdline.New(&shellMode{g, commands, false}, g)
-	c.SetText(cmd)
-	if len(offset) > 0 {
-		c.MoveCursor(offset[0])
-	}
-	g.next = c
+	g.startShell(&shellMode{Goful: g}, cmd, offset)
 }
 
 // ShellSuspend starts the shell mode and suspends screen after running.
 // The head of variadic arguments is used for cursor positioning.
 func (g *Goful) ShellSuspend(cmd string, offset ...int) {
+	g.startShell(&shellMode{Goful: g, suspend: true}, cmd, offset)
+}
+
+// ShellCapture starts the shell mode and shows the output of the command
+// in a popup, reloading the panes when it finishes if reload is set.
+// The head of variadic arguments is used for cursor positioning.
+// [IMPL:COMMAND_OUTPUT_CAPTURE] [ARCH:COMMAND_OUTPUT_CAPTURE] [REQ:COMMAND_OUTPUT_CAPTURE]
+func (g *Goful) ShellCapture(cmd string, reload bool, offset ...int) {
+	g.startShell(&shellMode{Goful: g, capture: true, reload: reload}, cmd, offset)
+}
+
+func (g *Goful) startShell(m *shellMode, cmd string, offset []int) {
 	commands, err := util.SearchCommands()
 	if err != nil {
 		message.Error(err)
 	}
-	c := cmdline.New(&shellMode{g, commands, true}, g)
+	m.commands = commands
+	c := cmdline.New(m, g)
 	c.SetText(cmd)
 	if len(offset) > 0 {
 		c.MoveCursor(offset[0])
@@ -52,12 +58,16 @@ type shellMode struct {
 	*Goful
 	commands map[string]bool
 	suspend  bool
+	capture  bool // [IMPL:COMMAND_OUTPUT_CAPTURE]
+	reload   bool
 }
 
 func (m *shellMode) String() string { return "shell" }
 func (m *shellMode) Prompt() string {
 	if m.suspend {
 		return "Suspend $ "
+	} else if m.capture {
+		return "Capture $ "
 	}
 	return "$ "
 }
@@ -102,6 +112,14 @@ func (m *shellMode) drawCommand(x, y int, cmd string) {
 }
 
 func (m *shellMode) Run(c *cmdline.Cmdline) {
+	if m.capture {
+		// Exit first: the output viewer replaces the cmdline.
+		cmd := c.String()
+		m.commands = nil
+		c.Exit()
+		m.Capture(cmd, m.reload)
+		return
+	}
 	if m.suspend {
 		m.SpawnSuspend(c.String())
 	} else {
@@ -576,7 +594,7 @@ func (m *chdirMode) Prompt() string          { return "Chdir to " }
 func (m *chdirMode) Draw(c *cmdline.Cmdline) { c.DrawLine() }
 func (m *chdirMode) Run(c *cmdline.Cmdline) {
 	if path := c.String(); path != "" {
-		m.Dir().Chdir(path)
+		m.ChdirLinked(path) // [IMPL:LINKED_CHDIR] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_CHDIR]
 		c.Exit()
 	}
 }
@@ -697,3 +715,343 @@ func (m *syncRenameMode) Run(c *cmdline.Cmdline) {
 	c.Exit()
 	m.executeSyncRename(m.filename, newName, m.ignoreFailures)
 }
+
+// syncMkdirMode prompts for a directory name to make in every pane.
+// [IMPL:SYNC_CREATE_OPS] [ARCH:SYNC_MODE] [REQ:SYNC_CREATE_OPS]
+type syncMkdirMode struct {
+	*Goful
+	ignoreFailures bool
+}
+
+func (m *syncMkdirMode) String() string { return "syncmkdir" }
+func (m *syncMkdirMode) Prompt() string {
+	return fmt.Sprintf("Make directory in %d panes: ", m.getPaneCount())
+}
+func (m *syncMkdirMode) Draw(c *cmdline.Cmdline) { c.DrawLine() }
+func (m *syncMkdirMode) Run(c *cmdline.Cmdline) {
+	name := c.String()
+	if name == "" {
+		return
+	}
+	c.Exit()
+	m.executeSyncMkdir(name, m.ignoreFailures)
+}
+
+// syncTouchMode prompts for a file name to create in every pane.
+// [IMPL:SYNC_CREATE_OPS] [ARCH:SYNC_MODE] [REQ:SYNC_CREATE_OPS]
+type syncTouchMode struct {
+	*Goful
+	ignoreFailures bool
+}
+
+func (m *syncTouchMode) String() string { return "synctouch" }
+func (m *syncTouchMode) Prompt() string {
+	return fmt.Sprintf("New file in %d panes: ", m.getPaneCount())
+}
+func (m *syncTouchMode) Draw(c *cmdline.Cmdline) { c.DrawLine() }
+func (m *syncTouchMode) Run(c *cmdline.Cmdline) {
+	name := c.String()
+	if name == "" {
+		return
+	}
+	c.Exit()
+	m.executeSyncTouch(name, m.ignoreFailures)
+}
+
+// syncChmodMode prompts for an octal or symbolic mode before sync chmod.
+// [IMPL:SYNC_CREATE_OPS] [ARCH:SYNC_MODE] [REQ:SYNC_CREATE_OPS]
+type syncChmodMode struct {
+	*Goful
+	filename       string
+	ignoreFailures bool
+}
+
+func (m *syncChmodMode) String() string { return "syncchmod" }
+func (m *syncChmodMode) Prompt() string {
+	return fmt.Sprintf("Chmod '%s' in %s to (e.g. 0644, u+x,go-w): ", m.filename, m.panesWithFile(m.filename))
+}
+func (m *syncChmodMode) Draw(c *cmdline.Cmdline) { c.DrawLine() }
+func (m *syncChmodMode) Run(c *cmdline.Cmdline) {
+	spec := c.String()
+	if spec == "" {
+		return
+	}
+	if _, err := parseFileMode(spec, 0); err != nil {
+		message.Error(err)
+		c.SetText("")
+		return
+	}
+	c.Exit()
+	m.executeSyncChmod(m.filename, spec, m.ignoreFailures)
+}
+
+// syncSymlinkMode prompts for the link name before sync symlink.
+// [IMPL:SYNC_CREATE_OPS] [ARCH:SYNC_MODE] [REQ:SYNC_CREATE_OPS]
+type syncSymlinkMode struct {
+	*Goful
+	filename       string
+	ignoreFailures bool
+}
+
+func (m *syncSymlinkMode) String() string { return "syncsymlink" }
+func (m *syncSymlinkMode) Prompt() string {
+	return fmt.Sprintf("Symlink to '%s' in %s named: ", m.filename, m.panesWithFile(m.filename))
+}
+func (m *syncSymlinkMode) Draw(c *cmdline.Cmdline) { c.DrawLine() }
+func (m *syncSymlinkMode) Run(c *cmdline.Cmdline) {
+	linkName := c.String()
+	if linkName == "" || linkName == m.filename {
+		return
+	}
+	// The link target is relative to the link's directory, so a link in a
+	// subdirectory would dangle.
+	if strings.ContainsRune(linkName, filepath.Separator) {
+		message.Errorf("invalid link name %q: must not contain %c", linkName, filepath.Separator)
+		c.SetText("")
+		return
+	}
+	c.Exit()
+	m.executeSyncSymlink(m.filename, linkName, m.ignoreFailures)
+}
+
+// syncMarkedMapMode prompts for a `regexp/replacement' that maps the marked
+// names to new names before a marked sync copy or rename.
+// [IMPL:SYNC_MARKED] [ARCH:SYNC_MODE] [REQ:SYNC_MARKED]
+type syncMarkedMapMode struct {
+	*Goful
+	op             string // "copy" or "rename"
+	names          []string
+	ignoreFailures bool
+}
+
+func (m *syncMarkedMapMode) String() string { return "syncmarked" + m.op }
+func (m *syncMarkedMapMode) Prompt() string {
+	verb := "Copy"
+	if m.op == "rename" {
+		verb = "Rename"
+	}
+	return fmt.Sprintf("%s %s in %d panes by regexp %%s/", verb, syncMarkedLabel(m.names), m.getPaneCount())
+}
+func (m *syncMarkedMapMode) Draw(c *cmdline.Cmdline) { c.DrawLine() }
+func (m *syncMarkedMapMode) Run(c *cmdline.Cmdline) {
+	patterns := strings.SplitN(c.String(), "/", 2)
+	if len(patterns) < 2 {
+		message.Errorf("Input must be like `regexp/replaced'")
+		return
+	}
+	pairs, err := syncMarkedPlan(m.names, patterns[0], patterns[1])
+	if err != nil {
+		message.Error(err)
+		return
+	}
+	c.Exit()
+	example := fmt.Sprintf("%s -> %s", pairs[0].from, pairs[0].to)
+	if len(pairs) > 1 {
+		example += ", ..."
+	}
+	switch m.dialog(fmt.Sprintf("Sync %s(%d)? %s", m.op, len(pairs), example), "y", "n") {
+	case "y", "Y":
+		if m.op == "rename" {
+			m.executeSyncRenameMarked(pairs, m.ignoreFailures)
+		} else {
+			m.executeSyncCopyMarked(pairs, m.ignoreFailures)
+		}
+	}
+}
+
+// syncDeleteMarkedMode prompts for confirmation before deleting the marked names in every pane.
+// [IMPL:SYNC_MARKED] [ARCH:SYNC_MODE] [REQ:SYNC_MARKED]
+type syncDeleteMarkedMode struct {
+	*Goful
+	names          []string
+	ignoreFailures bool
+}
+
+func (m *syncDeleteMarkedMode) String() string { return "syncdeletemarked" }
+func (m *syncDeleteMarkedMode) Prompt() string {
+	suffix := ""
+	if m.ignoreFailures {
+		suffix = " (ignore failures)"
+	}
+	return fmt.Sprintf("Delete %s in %d panes?%s [y/n] ", syncMarkedLabel(m.names), m.getPaneCount(), suffix)
+}
+func (m *syncDeleteMarkedMode) Draw(c *cmdline.Cmdline) { c.DrawLine() }
+func (m *syncDeleteMarkedMode) Run(c *cmdline.Cmdline) {
+	switch c.String() {
+	case "y", "Y":
+		c.Exit()
+		m.executeSyncDeleteMarked(m.names, m.ignoreFailures)
+	case "n", "N":
+		c.Exit()
+	default:
+		c.SetText("")
+	}
+}
+
+// EditPathMap starts the mode that edits the linked navigation path mapping
+// rules of the current workspace.
+// [IMPL:LINKED_PATH_MAP] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_PATH_MAP]
+func (g *Goful) EditPathMap() {
+	g.next = cmdline.New(&pathMapMode{g}, g)
+}
+
+type pathMapMode struct {
+	*Goful
+}
+
+func (m *pathMapMode) String() string { return "pathmap" }
+func (m *pathMapMode) Prompt() string {
+	return fmt.Sprintf("Linked path map (%d rules; from=to, /regexp/=repl, . sets roots, - clears): ", len(m.Workspace().PathMapRules()))
+}
+func (m *pathMapMode) Draw(c *cmdline.Cmdline) { c.DrawLine() }
+func (m *pathMapMode) Run(c *cmdline.Cmdline) {
+	ws := m.Workspace()
+	switch input := c.String(); input {
+	case "":
+		rules := ws.PathMapRules()
+		if len(rules) == 0 {
+			message.Info("[REQ:LINKED_PATH_MAP] no path mapping rules")
+		} else {
+			list := make([]string, len(rules))
+			for i, r := range rules {
+				list[i] = r.String()
+			}
+			message.Infof("[REQ:LINKED_PATH_MAP] path mapping: %s", strings.Join(list, "; "))
+		}
+	case ".":
+		ws.SetPathMapRoots()
+		message.Info("[REQ:LINKED_PATH_MAP] path mapping rules are relative to the current directories")
+	case "-":
+		ws.ClearPathMap()
+		message.Info("[REQ:LINKED_PATH_MAP] path mapping rules cleared")
+	default:
+		rule, err := filer.ParsePathMapRule(input)
+		if err != nil {
+			message.Error(err)
+			return
+		}
+		ws.AddPathMapRule(rule)
+		message.Infof("[REQ:LINKED_PATH_MAP] added path mapping %s (%d rules)", rule, len(ws.PathMapRules()))
+	}
+	c.Exit()
+}
+
+// SetLinkGroup starts the mode that assigns the focused pane to a link group.
+// [IMPL:LINK_GROUPS] [ARCH:LINKED_NAVIGATION] [REQ:LINK_GROUPS]
+func (g *Goful) SetLinkGroup() {
+	c := cmdline.New(&linkGroupMode{g}, g)
+	c.SetText(g.Dir().LinkGroup)
+	g.next = c
+}
+
+type linkGroupMode struct {
+	*Goful
+}
+
+func (m *linkGroupMode) String() string { return "linkgroup" }
+func (m *linkGroupMode) Prompt() string {
+	return fmt.Sprintf("Link group for pane %d (a-z, - independent, empty default): ", m.Workspace().Focus+1)
+}
+func (m *linkGroupMode) Draw(c *cmdline.Cmdline) { c.DrawLine() }
+func (m *linkGroupMode) Run(c *cmdline.Cmdline) {
+	group, err := filer.ParseLinkGroup(c.String())
+	if err != nil {
+		message.Error(err)
+		c.SetText("")
+		return
+	}
+	c.Exit()
+	m.Dir().LinkGroup = group
+	m.Dir().LinkRoot = m.Dir().Path // [IMPL:LINKED_CHDIR]
+	switch group {
+	case "":
+		message.Infof("[REQ:LINK_GROUPS] pane %d in default link group (%d linked)", m.Workspace().Focus+1, m.Workspace().LinkedCount())
+	case filer.LinkIndependent:
+		message.Infof("[REQ:LINK_GROUPS] pane %d is independent", m.Workspace().Focus+1)
+	default:
+		message.Infof("[REQ:LINK_GROUPS] pane %d in link group %s (%d linked)", m.Workspace().Focus+1, group, m.Workspace().LinkedCount())
+	}
+}
+
+// PromptParams prompts for each param in turn and then calls run with the
+// values by name. Each param has its own cmdline history. Answering no to a
+// confirmation or leaving a prompt (C-g) cancels without calling run.
+// [IMPL:COMMAND_PARAMS] [ARCH:COMMAND_PARAMS] [REQ:COMMAND_PARAMS]
+func (g *Goful) PromptParams(params []externalcmd.Param, run func(values map[string]string)) {
+	m := &paramMode{g, params, 0, make(map[string]string, len(params)), run}
+	m.prompt()
+}
+
+type paramMode struct {
+	*Goful
+	params []externalcmd.Param
+	index  int
+	values map[string]string
+	run    func(values map[string]string)
+}
+
+func (m *paramMode) prompt() {
+	if m.index >= len(m.params) {
+		m.run(m.values)
+		return
+	}
+	param := m.params[m.index]
+	c := cmdline.New(m, m.Goful)
+	switch {
+	case param.Default != "":
+		c.SetText(param.Default)
+	case param.Type == externalcmd.ParamFile:
+		c.SetText(m.Workspace().NextDir().File().Path())
+	}
+	m.next = c
+}
+
+func (m *paramMode) param() externalcmd.Param { return m.params[m.index] }
+
+func (m *paramMode) String() string { return "param:" + m.param().Name }
+func (m *paramMode) Prompt() string {
+	param := m.param()
+	label := param.Prompt
+	if label == "" {
+		label = param.Name
+	}
+	switch param.Type {
+	case externalcmd.ParamChoice:
+		return fmt.Sprintf("%s [%s]: ", label, strings.Join(param.Choices, "/"))
+	case externalcmd.ParamConfirm:
+		return label + "? [Y/n] "
+	}
+	return label + ": "
+}
+func (m *paramMode) Draw(c *cmdline.Cmdline) { c.DrawLine() }
+func (m *paramMode) Run(c *cmdline.Cmdline) {
+	param := m.param()
+	value := c.String()
+	switch param.Type {
+	case externalcmd.ParamChoice:
+		if !param.IsChoice(value) {
+			c.SetText("")
+			return
+		}
+	case externalcmd.ParamConfirm:
+		switch value {
+		case "Y", "y", "":
+			value = "yes"
+		case "n", "N":
+			c.Exit()
+			return
+		default:
+			c.SetText("")
+			return
+		}
+	case externalcmd.ParamFile:
+		if value == "" {
+			message.Errorf("Must specify a path for %s", param.Name)
+			return
+		}
+	}
+	m.values[param.Name] = value
+	c.Exit()
+	m.index++
+	m.prompt()
+}
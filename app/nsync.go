@@ -7,13 +7,11 @@ package app
 
 import (
 	"context"
-	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
 	"time"
 
-	"github.com/fareedst/goful/cmdline"
 	"github.com/fareedst/goful/message"
 	"github.com/fareedst/goful/progress"
 	"github.com/fareedst/goful/widget"
@@ -134,21 +132,6 @@ func (f *fakeFileInfo) ModTime() time.Time { return time.Time{} }
 func (f *fakeFileInfo) IsDir() bool        { return false }
 func (f *fakeFileInfo) Sys() interface{}   { return nil }
 
-// formatBytes formats bytes as a human-readable string.
-// [IMPL:NSYNC_OBSERVER] [ARCH:NSYNC_INTEGRATION] [REQ:NSYNC_MULTI_TARGET]
-func formatBytes(bytes int64) string {
-	const unit = 1024
-	if bytes < unit {
-		return fmt.Sprintf("%dB", bytes)
-	}
-	div, exp := int64(unit), 0
-	for n := bytes / unit; n >= unit; n /= unit {
-		div *= unit
-		exp++
-	}
-	return fmt.Sprintf("%.1f%cB", float64(bytes)/float64(div), "KMGTPE"[exp])
-}
-
 // syncCopy performs a multi-destination copy using nsync.
 // [IMPL:NSYNC_COPY_MOVE] [ARCH:NSYNC_INTEGRATION] [REQ:NSYNC_MULTI_TARGET]
 func (g *Goful) syncCopy(sources []string, destinations []string) {
@@ -249,86 +232,12 @@ func (g *Goful) syncMove(sources []string, destinations []string) {
 	})
 }
 
-// CopyAll prompts for confirmation then copies selected files to all other visible workspace directories.
-// [IMPL:NSYNC_COPY_MOVE] [ARCH:NSYNC_INTEGRATION] [REQ:NSYNC_MULTI_TARGET]
-// [IMPL:NSYNC_CONFIRMATION] [ARCH:NSYNC_CONFIRMATION] [REQ:NSYNC_CONFIRMATION]
-func (g *Goful) CopyAll() {
-	destinations := otherWindowDirPaths(g.Workspace())
-
-	if len(destinations) == 0 {
-		// Fall back to regular copy when only one pane
-		message.Info("Only one pane visible - use regular copy (c)")
-		g.Copy()
-		return
-	}
-
-	// Collect sources from marks or cursor
-	var sources []string
-	if g.Dir().IsMark() {
-		sources = g.Dir().MarkfilePaths()
-		g.Dir().MarkClear()
-	} else {
-		file := g.File()
-		if file == nil {
-			message.Error(fmt.Errorf("no file selected"))
-			return
-		}
-		sources = []string{file.Path()}
-	}
-
-	if len(sources) == 0 {
-		message.Error(fmt.Errorf("no files to copy"))
-		return
-	}
-
-	// Start confirmation mode instead of executing immediately
-	// [IMPL:NSYNC_CONFIRMATION] [ARCH:NSYNC_CONFIRMATION] [REQ:NSYNC_CONFIRMATION]
-	g.next = cmdline.New(&copyAllMode{g, sources, destinations}, g)
-}
-
 // doCopyAll executes the multi-target copy after confirmation.
 // [IMPL:NSYNC_CONFIRMATION] [ARCH:NSYNC_CONFIRMATION] [REQ:NSYNC_CONFIRMATION]
 func (g *Goful) doCopyAll(sources, destinations []string) {
 	g.syncCopy(sources, destinations)
 }
 
-// MoveAll prompts for confirmation then moves selected files to all other visible workspace directories.
-// [IMPL:NSYNC_COPY_MOVE] [ARCH:NSYNC_INTEGRATION] [REQ:NSYNC_MULTI_TARGET]
-// [IMPL:NSYNC_CONFIRMATION] [ARCH:NSYNC_CONFIRMATION] [REQ:NSYNC_CONFIRMATION]
-func (g *Goful) MoveAll() {
-	destinations := otherWindowDirPaths(g.Workspace())
-
-	if len(destinations) == 0 {
-		// Fall back to regular move when only one pane
-		message.Info("Only one pane visible - use regular move (m)")
-		g.Move()
-		return
-	}
-
-	// Collect sources from marks or cursor
-	var sources []string
-	if g.Dir().IsMark() {
-		sources = g.Dir().MarkfilePaths()
-		g.Dir().MarkClear()
-	} else {
-		file := g.File()
-		if file == nil {
-			message.Error(fmt.Errorf("no file selected"))
-			return
-		}
-		sources = []string{file.Path()}
-	}
-
-	if len(sources) == 0 {
-		message.Error(fmt.Errorf("no files to move"))
-		return
-	}
-
-	// Start confirmation mode instead of executing immediately
-	// [IMPL:NSYNC_CONFIRMATION] [ARCH:NSYNC_CONFIRMATION] [REQ:NSYNC_CONFIRMATION]
-	g.next = cmdline.New(&moveAllMode{g, sources, destinations}, g)
-}
-
 // doMoveAll executes the multi-target move after confirmation.
 // [IMPL:NSYNC_CONFIRMATION] [ARCH:NSYNC_CONFIRMATION] [REQ:NSYNC_CONFIRMATION]
 func (g *Goful) doMoveAll(sources, destinations []string) {
@@ -0,0 +1,127 @@
+// Package app reconciles a difference across all panes.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fareedst/goful/filer"
+	"github.com/fareedst/goful/message"
+	"github.com/fareedst/goful/progress"
+	"github.com/fareedst/goful/reconcile"
+	"github.com/fareedst/goful/util"
+)
+
+// reportedDiff is a difference shown to the user with the window paths it was found in.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+type reportedDiff struct {
+	name  string
+	paths []string
+}
+
+// setLastDiff records name as the current difference in the workspace windows.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+func (g *Goful) setLastDiff(name string) {
+	dirs := g.Workspace().Dirs
+	paths := make([]string, len(dirs))
+	for i, d := range dirs {
+		paths[i] = d.Path
+	}
+	g.lastDiff = &reportedDiff{name: strings.TrimSuffix(name, "/"), paths: paths}
+}
+
+// currentDiff returns the name of the last reported difference, or "" when
+// none was reported or the windows have moved since.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+func (g *Goful) currentDiff() string {
+	dirs := g.Workspace().Dirs
+	if g.lastDiff == nil || len(g.lastDiff.paths) != len(dirs) {
+		return ""
+	}
+	for i, d := range dirs {
+		if d.Path != g.lastDiff.paths[i] {
+			return ""
+		}
+	}
+	return g.lastDiff.name
+}
+
+// Reconcile plans how to make every pane match one copy of the last reported
+// difference and opens a preview. The difference comes from the search or the
+// results panel rather than the cursor, which stays put in a pane lacking the
+// entry. Confirming the preview runs the plan as a
+// file job: missing or older copies are overwritten from the source pane and,
+// when enabled in the preview, extras the source lacks are removed from the
+// other panes.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+func (g *Goful) Reconcile() {
+	build, err := g.reconcileBuilder()
+	if err != nil {
+		message.Error(err)
+		return
+	}
+	plan, err := build(filer.ReconcileOptions{Source: filer.ReconcileNewest, Focus: g.Workspace().Focus})
+	if err != nil {
+		message.Error(err)
+		return
+	}
+	g.next = reconcile.New(g, plan, build, g.runReconcile)
+}
+
+// reconcileBuilder returns a plan builder for the current difference in the
+// workspace windows, or an error when there is none to reconcile.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+func (g *Goful) reconcileBuilder() (reconcile.BuildFunc, error) {
+	dirs := g.Workspace().Dirs
+	if len(dirs) < 2 {
+		return nil, fmt.Errorf("reconcile requires at least 2 windows")
+	}
+	name := g.currentDiff()
+	if name == "" {
+		return nil, fmt.Errorf("no current difference: find one with the difference search first")
+	}
+	return func(opts filer.ReconcileOptions) (*filer.ReconcilePlan, error) {
+		return filer.BuildReconcilePlan(dirs, name, opts)
+	}, nil
+}
+
+// runReconcile executes a confirmed plan in the background.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+func (g *Goful) runReconcile(plan *filer.ReconcilePlan) {
+	g.asyncFilectrl(func() {
+		walker := g.newWalker(overwriteYesAll, overwriteYesAll, copyJob{})
+		done, err := applyReconcilePlan(walker, plan)
+		if err != nil {
+			message.Errorf("Reconciled %d of %d actions for %s: %v", done, len(plan.Actions), plan.Name, err)
+		} else {
+			message.Infof("Reconciled %s from window %d: %d actions", plan.Name, plan.Source+1, done)
+		}
+	})
+}
+
+// applyReconcilePlan performs the plan actions in order, stopping at the
+// first failure, and returns how many actions completed.
+// Copies go through the walker so times, modes and symlinks are preserved.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+func applyReconcilePlan(w *walker, plan *filer.ReconcilePlan) (int, error) {
+	size, count := util.CalcSizeCount(plan.Sources()...)
+	progress.Start(float64(size))
+	progress.StartTaskCount(count)
+	defer progress.Finish()
+
+	for i, a := range plan.Actions {
+		var err error
+		switch a.Kind {
+		case filer.ReconcileCopy, filer.ReconcileUpdate:
+			err = w.walk(a.Src, a.Dst)
+		case filer.ReconcileDelete:
+			err = os.RemoveAll(a.Dst)
+		}
+		if err != nil {
+			return i, fmt.Errorf("%s %s: %v", a.Kind, a.Dst, err)
+		}
+	}
+	return len(plan.Actions), nil
+}
@@ -0,0 +1,95 @@
+// Package app reconcile execution tests.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fareedst/goful/filer"
+	"github.com/fareedst/goful/progress"
+)
+
+// TestApplyReconcilePlan_REQ_DIFF_RECONCILE verifies a plan makes every window match the source.
+func TestApplyReconcilePlan_REQ_DIFF_RECONCILE(t *testing.T) {
+	progress.Init()
+	tmpDir := t.TempDir()
+	src := filepath.Join(tmpDir, "a", "d")
+	dst := filepath.Join(tmpDir, "b", "d")
+	os.MkdirAll(filepath.Join(src, "sub"), 0755)
+	os.MkdirAll(dst, 0755)
+	os.WriteFile(filepath.Join(src, "sub", "new.txt"), []byte("new"), 0644)
+	os.WriteFile(filepath.Join(src, "f.txt"), []byte("source"), 0644)
+	os.WriteFile(filepath.Join(dst, "f.txt"), []byte("old"), 0644)
+	os.WriteFile(filepath.Join(dst, "extra.txt"), []byte("x"), 0644)
+
+	plan := &filer.ReconcilePlan{
+		Name: "d",
+		Actions: []filer.ReconcileAction{
+			{Kind: filer.ReconcileUpdate, Window: 1, Src: filepath.Join(src, "f.txt"), Dst: filepath.Join(dst, "f.txt")},
+			{Kind: filer.ReconcileCopy, Window: 1, Src: filepath.Join(src, "sub"), Dst: filepath.Join(dst, "sub"), IsDir: true},
+			{Kind: filer.ReconcileDelete, Window: 1, Dst: filepath.Join(dst, "extra.txt")},
+		},
+	}
+	w := (*Goful)(nil).newWalker(overwriteYesAll, overwriteYesAll, copyJob{})
+	done, err := applyReconcilePlan(w, plan)
+	if err != nil || done != 3 {
+		t.Fatalf("applyReconcilePlan = %d, %v", done, err)
+	}
+
+	if b, _ := os.ReadFile(filepath.Join(dst, "f.txt")); string(b) != "source" {
+		t.Errorf("f.txt not updated: %q", b)
+	}
+	if b, _ := os.ReadFile(filepath.Join(dst, "sub", "new.txt")); string(b) != "new" {
+		t.Errorf("sub/new.txt not copied: %q", b)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "extra.txt")); !os.IsNotExist(err) {
+		t.Errorf("extra.txt not deleted: %v", err)
+	}
+	srcInfo, _ := os.Stat(filepath.Join(src, "f.txt"))
+	dstInfo, _ := os.Stat(filepath.Join(dst, "f.txt"))
+	if !srcInfo.ModTime().Equal(dstInfo.ModTime()) {
+		t.Errorf("modification time not preserved: %v vs %v", srcInfo.ModTime(), dstInfo.ModTime())
+	}
+}
+
+// TestReconcileBuilder_FocusedMissing_REQ_DIFF_RECONCILE verifies the plan targets the
+// reported difference, not the focused window's cursor, when the focused window lacks it.
+func TestReconcileBuilder_FocusedMissing_REQ_DIFF_RECONCILE(t *testing.T) {
+	tmp1 := t.TempDir()
+	tmp2 := t.TempDir()
+	os.WriteFile(filepath.Join(tmp1, "a.txt"), []byte("a"), 0644)
+	os.WriteFile(filepath.Join(tmp2, "a.txt"), []byte("aa"), 0644)
+	os.WriteFile(filepath.Join(tmp2, "only.txt"), []byte("only"), 0644)
+
+	g := newTestGoful(t, tmp1, tmp2)
+	if _, err := g.reconcileBuilder(); err == nil {
+		t.Fatal("expected an error without a current difference")
+	}
+
+	g.showDiffStep(filer.Step{Type: filer.StepFoundDiff, Name: "only.txt", Reason: "missing in window 1"})
+	if name := g.File().Name(); name == "only.txt" {
+		t.Fatalf("focused cursor unexpectedly on %s", name)
+	}
+	build, err := g.reconcileBuilder()
+	if err != nil {
+		t.Fatalf("reconcileBuilder: %v", err)
+	}
+	plan, err := build(filer.ReconcileOptions{Source: filer.ReconcileNewest, Focus: g.Workspace().Focus})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	if plan.Name != "only.txt" || plan.Source != 1 {
+		t.Fatalf("plan for %s from window %d, want only.txt from window 2", plan.Name, plan.Source+1)
+	}
+	if len(plan.Actions) != 1 || plan.Actions[0].Dst != filepath.Join(tmp1, "only.txt") {
+		t.Errorf("unexpected actions %+v", plan.Actions)
+	}
+
+	// A difference found elsewhere is not current once the windows move.
+	g.Workspace().Dirs[0].Chdir(t.TempDir())
+	if _, err := g.reconcileBuilder(); err == nil {
+		t.Error("expected an error after the windows moved")
+	}
+}
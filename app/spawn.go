@@ -3,12 +3,16 @@ package app
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/fareedst/goful/filer"
+	"github.com/fareedst/goful/jobs"
 	"github.com/fareedst/goful/message"
+	"github.com/fareedst/goful/outputview"
 	"github.com/fareedst/goful/util"
 	"github.com/fareedst/goful/widget"
 )
@@ -23,12 +27,72 @@ func (g *Goful) Spawn(cmd string) {
 		args = g.terminal(cmd)
 	}
 	execCmd := exec.Command(args[0], args[1:]...)
+	if background {
+		g.startJob(execCmd, cmd)
+		return
+	}
 	message.Info(strings.Join(execCmd.Args, " "))
 	if err := spawn(execCmd); err != nil {
 		message.Error(err)
 	}
 }
 
+// jobTailLines is the number of output lines shown in job messages.
+const jobTailLines = 3
+
+// startJob starts a background command as a tracked job and posts a message
+// with its exit status and the tail of its output when it finishes.
+// [IMPL:BACKGROUND_JOBS] [ARCH:BACKGROUND_JOBS] [REQ:BACKGROUND_JOBS]
+func (g *Goful) startJob(execCmd *exec.Cmd, cmd string) {
+	job, err := g.jobs.Start(execCmd, cmd)
+	if err != nil {
+		message.Error(err)
+		return
+	}
+	message.Infof("[job %d] %s", job.ID, strings.Join(execCmd.Args, " "))
+	go func() {
+		errWait := execCmd.Wait()
+		g.syncCallback(func() {
+			job.Finish(exitStatus(errWait), errWait != nil)
+			if job.Failed {
+				message.Errorf("%s", jobSummary(job))
+			} else {
+				message.Info(jobSummary(job))
+			}
+		})
+	}()
+}
+
+// jobSummary describes a job as its number, command, status and the last
+// lines of its output: standard error for failed jobs, otherwise standard
+// output.
+// [IMPL:BACKGROUND_JOBS] [ARCH:BACKGROUND_JOBS] [REQ:BACKGROUND_JOBS]
+func jobSummary(job *jobs.Job) string {
+	s := fmt.Sprintf("[job %d] %s: %s", job.ID, job.Command, job.Status)
+	tail := job.Stdout(jobTailLines)
+	if job.Failed || job.Running() {
+		if stderr := job.Stderr(jobTailLines); len(stderr) > 0 {
+			tail = stderr
+		}
+	}
+	if len(tail) > 0 {
+		s += ": " + strings.Join(tail, " | ")
+	}
+	return s
+}
+
+// Jobs opens a panel listing the background jobs of the session.
+// [IMPL:BACKGROUND_JOBS] [ARCH:BACKGROUND_JOBS] [REQ:BACKGROUND_JOBS]
+func (g *Goful) Jobs() {
+	if len(g.jobs.Jobs()) == 0 {
+		message.Info("No background jobs")
+		return
+	}
+	g.next = jobs.NewPanel(g, g.jobs, func(job *jobs.Job) {
+		message.Info(jobSummary(job))
+	})
+}
+
 func spawn(cmd *exec.Cmd) error {
 	var bufout bytes.Buffer
 	cmd.Stdout = &bufout
@@ -75,6 +139,72 @@ func (g *Goful) SpawnSuspend(cmd string) {
 	_ = shell.Run()
 }
 
+// captureWaitDelay bounds how long Capture waits for the output pipes after
+// the command exits, in case a process it started keeps them open.
+const captureWaitDelay = 2 * time.Second
+
+// Capture runs a command by the shell and shows its output in a popup while
+// it runs. %& is ignored. When the command finishes its exit status is shown
+// and posted as a message, and the panes are reloaded if reload is set.
+// Cancelling or closing the viewer kills the whole process group of the command.
+// [IMPL:COMMAND_OUTPUT_CAPTURE] [ARCH:COMMAND_OUTPUT_CAPTURE] [REQ:COMMAND_OUTPUT_CAPTURE]
+func (g *Goful) Capture(cmd string, reload bool) {
+	cmd, _ = g.expandMacro(cmd)
+	args := g.shell(cmd)
+	execCmd := exec.Command(args[0], args[1:]...)
+	util.SetProcessGroup(execCmd)
+	execCmd.WaitDelay = captureWaitDelay
+	viewer := outputview.New(g, cmd, func() {
+		_ = util.KillProcessGroup(execCmd)
+	})
+	out := &captureWriter{g, viewer}
+	execCmd.Stdout = out
+	execCmd.Stderr = out
+	if err := execCmd.Start(); err != nil {
+		message.Error(err)
+		return
+	}
+	g.next = viewer
+	go func() {
+		status := exitStatus(execCmd.Wait())
+		g.syncCallback(func() {
+			viewer.Finish(status)
+			if reload {
+				g.Workspace().ReloadAll()
+			}
+			if status == "exit 0" {
+				message.Infof("%s: %s", cmd, status)
+			} else {
+				message.Errorf("%s: %s", cmd, status)
+			}
+		})
+	}()
+}
+
+// captureWriter appends command output to a viewer on the event loop.
+type captureWriter struct {
+	g      *Goful
+	viewer *outputview.Viewer
+}
+
+func (w *captureWriter) Write(p []byte) (int, error) {
+	text := string(p)
+	w.g.syncCallback(func() { w.viewer.Append(text) })
+	return len(p), nil
+}
+
+// exitStatus describes the result of exec.Cmd.Wait as "exit N" or the error.
+func exitStatus(err error) string {
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return "exit 0"
+	case errors.As(err, &exitErr) && exitErr.ExitCode() >= 0:
+		return fmt.Sprintf("exit %d", exitErr.ExitCode())
+	}
+	return err.Error()
+}
+
 const (
 	macroPrefix             = '%'
 	macroEscape             = '\\' // \ is an escape sequence
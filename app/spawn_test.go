@@ -1,12 +1,16 @@
 package app
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
 
+	"github.com/fareedst/goful/externalcmd"
 	"github.com/fareedst/goful/filer"
+	"github.com/fareedst/goful/jobs"
 	"github.com/fareedst/goful/util"
 )
 
@@ -203,6 +207,75 @@ func TestExpandMacroWindowEnumeration_REQ_WINDOW_MACRO_ENUMERATION(t *testing.T)
 	}
 }
 
+// TestExpandMacroParams_REQ_COMMAND_PARAMS verifies that prompted values survive
+// macro expansion literally while the file macros around them still expand.
+// [REQ:COMMAND_PARAMS] [ARCH:COMMAND_PARAMS] [IMPL:COMMAND_PARAMS]
+func TestExpandMacroParams_REQ_COMMAND_PARAMS(t *testing.T) {
+	g := NewGoful("")
+	ws := g.Workspace()
+	ws.Dirs = []*filer.Directory{stubDirectory("/alpha"), stubDirectory("/beta")}
+	ws.Focus = 0
+
+	cmd := externalcmd.ExpandParams(`run %{msg} %~{msg} %D2`, map[string]string{"msg": `50%D \o`})
+	got, _ := g.expandMacro(cmd)
+	want := `run "50%D \o" 50%D \o "/beta"`
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
 func stubDirectory(path string) *filer.Directory {
 	return &filer.Directory{Path: path}
 }
+
+// TestExitStatus_REQ_COMMAND_OUTPUT_CAPTURE verifies the status shown for captured commands.
+// [REQ:COMMAND_OUTPUT_CAPTURE] [ARCH:COMMAND_OUTPUT_CAPTURE] [IMPL:COMMAND_OUTPUT_CAPTURE]
+func TestExitStatus_REQ_COMMAND_OUTPUT_CAPTURE(t *testing.T) {
+	if got := exitStatus(nil); got != "exit 0" {
+		t.Errorf("exitStatus(nil) = %q, want exit 0", got)
+	}
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	if got := exitStatus(exec.Command(sh, "-c", "exit 3").Run()); got != "exit 3" {
+		t.Errorf("exitStatus = %q, want exit 3", got)
+	}
+	if got := exitStatus(errors.New("boom")); got != "boom" {
+		t.Errorf("exitStatus = %q, want the error", got)
+	}
+}
+
+// TestJobSummary_REQ_BACKGROUND_JOBS verifies the message posted when a job finishes.
+// [REQ:BACKGROUND_JOBS] [ARCH:BACKGROUND_JOBS] [IMPL:BACKGROUND_JOBS]
+func TestJobSummary_REQ_BACKGROUND_JOBS(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	list := &jobs.List{}
+	run := func(script string) *jobs.Job {
+		cmd := exec.Command(sh, "-c", script)
+		cmd.Dir = t.TempDir()
+		job, err := list.Start(cmd, script)
+		if err != nil {
+			t.Fatal(err)
+		}
+		errWait := cmd.Wait()
+		job.Finish(exitStatus(errWait), errWait != nil)
+		return job
+	}
+
+	ok := run("echo warning >&2; echo done")
+	if got, want := jobSummary(ok), "[job 1] echo warning >&2; echo done: exit 0: done"; got != want {
+		t.Errorf("jobSummary = %q, want %q", got, want)
+	}
+	failed := run("echo progress; echo a >&2; echo b >&2; exit 4")
+	if got, want := jobSummary(failed), "[job 2] echo progress; echo a >&2; echo b >&2; exit 4: exit 4: a | b"; got != want {
+		t.Errorf("jobSummary = %q, want %q", got, want)
+	}
+	quiet := run("true")
+	if got, want := jobSummary(quiet), "[job 3] true: exit 0"; got != want {
+		t.Errorf("jobSummary = %q, want %q", got, want)
+	}
+}
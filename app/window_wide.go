@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/fareedst/goful/cmdline"
+	"github.com/fareedst/goful/filer"
 	"github.com/fareedst/goful/message"
 )
 
@@ -34,7 +37,8 @@ func (g *Goful) SyncMode(ignoreFailures bool) {
 }
 
 // syncMode is the prefix mode that waits for an operation key.
-// Press '!' to toggle ignore-failures mode, then c/d/r to execute.
+// Press '!' to toggle ignore-failures mode, 'a' to toggle all-or-nothing
+// mode, then c/d/r/k/n/h/l to execute.
 // [IMPL:SYNC_EXECUTE] [ARCH:SYNC_MODE] [REQ:SYNC_COMMANDS]
 type syncMode struct {
 	*Goful
@@ -45,9 +49,13 @@ func (m *syncMode) String() string { return "sync" }
 
 func (m *syncMode) Prompt() string {
 	if m.ignoreFailures {
-		return "Sync! [c]opy [d]elete [r]ename (ignore failures): "
+		return "Sync! [c]opy [d]elete [r]ename [k]mkdir [n]ewfile c[h]mod [l]ink (ignore failures): "
 	}
-	return "Sync [c]opy [d]elete [r]ename [!]ignore: "
+	// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+	if m.Goful != nil && m.IsSyncAtomic() {
+		return "Sync* [c]opy [d]elete [r]ename [k]mkdir [n]ewfile c[h]mod [l]ink [!]ignore [a]tomic off (all or nothing): "
+	}
+	return "Sync [c]opy [d]elete [r]ename [k]mkdir [n]ewfile c[h]mod [l]ink [!]ignore [a]tomic: "
 }
 
 func (m *syncMode) Draw(c *cmdline.Cmdline) { c.DrawLine() }
@@ -62,8 +70,43 @@ func (m *syncMode) Run(c *cmdline.Cmdline) {
 		return // Don't exit, wait for operation key
 	}
 
+	// Handle 'a' toggle for all-or-nothing mode, which persists across sync commands
+	// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+	if key == "a" {
+		m.ToggleSyncAtomic()
+		c.SetText("")
+		return
+	}
+
 	c.Exit()
 
+	// mkdir and newfile create names in every pane and need no cursor file
+	// [IMPL:SYNC_CREATE_OPS] [ARCH:SYNC_MODE] [REQ:SYNC_CREATE_OPS]
+	switch key {
+	case "k":
+		m.StartSyncMkdir(m.ignoreFailures)
+		return
+	case "n":
+		m.StartSyncTouch(m.ignoreFailures)
+		return
+	}
+
+	// copy, delete and rename act on the marked names when the focused pane has marks
+	// [IMPL:SYNC_MARKED] [ARCH:SYNC_MODE] [REQ:SYNC_MARKED]
+	if m.Dir().IsMark() {
+		switch key {
+		case "c":
+			m.StartSyncCopy("", m.ignoreFailures)
+			return
+		case "d":
+			m.StartSyncDelete("", m.ignoreFailures)
+			return
+		case "r":
+			m.StartSyncRename("", m.ignoreFailures)
+			return
+		}
+	}
+
 	// Get the filename from cursor in the focused pane
 	file := m.File()
 	if file == nil || file.Name() == ".." {
@@ -79,39 +122,90 @@ func (m *syncMode) Run(c *cmdline.Cmdline) {
 		m.StartSyncDelete(filename, m.ignoreFailures)
 	case "r":
 		m.StartSyncRename(filename, m.ignoreFailures)
+	case "h":
+		m.StartSyncChmod(filename, m.ignoreFailures)
+	case "l":
+		m.StartSyncSymlink(filename, m.ignoreFailures)
 	default:
 		// Any other key exits the mode silently
 	}
 }
 
-// StartSyncCopy initiates the sync copy operation.
+// StartSyncCopy initiates the sync copy operation. If the focused pane has
+// marked files, the marked names are copied instead of filename.
 // [IMPL:SYNC_EXECUTE] [ARCH:SYNC_MODE] [REQ:SYNC_COMMANDS] [IMPL:TOOLBAR_SYNC_COPY] [REQ:TOOLBAR_SYNC_BUTTONS]
 func (g *Goful) StartSyncCopy(filename string, ignoreFailures bool) {
+	if g.Dir().IsMark() { // [IMPL:SYNC_MARKED] [ARCH:SYNC_MODE] [REQ:SYNC_MARKED]
+		g.next = cmdline.New(&syncMarkedMapMode{g, "copy", g.Dir().MarkfileNames(), ignoreFailures}, g)
+		return
+	}
 	c := cmdline.New(&syncCopyMode{g, filename, ignoreFailures}, g)
 	// Default to the same filename - user must change it to a different name
 	c.SetText(filename)
 	g.next = c
 }
 
-// StartSyncDelete initiates the sync delete operation.
+// StartSyncDelete initiates the sync delete operation. If the focused pane
+// has marked files, the marked names are deleted instead of filename.
 // [IMPL:SYNC_EXECUTE] [ARCH:SYNC_MODE] [REQ:SYNC_COMMANDS] [IMPL:TOOLBAR_SYNC_DELETE] [REQ:TOOLBAR_SYNC_BUTTONS]
 func (g *Goful) StartSyncDelete(filename string, ignoreFailures bool) {
+	if g.Dir().IsMark() { // [IMPL:SYNC_MARKED] [ARCH:SYNC_MODE] [REQ:SYNC_MARKED]
+		g.next = cmdline.New(&syncDeleteMarkedMode{g, g.Dir().MarkfileNames(), ignoreFailures}, g)
+		return
+	}
 	g.next = cmdline.New(&syncDeleteMode{g, filename, ignoreFailures}, g)
 }
 
-// StartSyncRename initiates the sync rename operation.
+// StartSyncRename initiates the sync rename operation. If the focused pane
+// has marked files, the marked names are renamed instead of filename.
 // [IMPL:SYNC_EXECUTE] [ARCH:SYNC_MODE] [REQ:SYNC_COMMANDS] [IMPL:TOOLBAR_SYNC_RENAME] [REQ:TOOLBAR_SYNC_BUTTONS]
 func (g *Goful) StartSyncRename(filename string, ignoreFailures bool) {
+	if g.Dir().IsMark() { // [IMPL:SYNC_MARKED] [ARCH:SYNC_MODE] [REQ:SYNC_MARKED]
+		g.next = cmdline.New(&syncMarkedMapMode{g, "rename", g.Dir().MarkfileNames(), ignoreFailures}, g)
+		return
+	}
 	c := cmdline.New(&syncRenameMode{g, filename, ignoreFailures}, g)
 	c.SetText(filename)
 	c.MoveCursor(-len(filepath.Ext(filename)))
 	g.next = c
 }
 
+// StartSyncMkdir prompts for a directory to make in every pane.
+// [IMPL:SYNC_CREATE_OPS] [ARCH:SYNC_MODE] [REQ:SYNC_CREATE_OPS]
+func (g *Goful) StartSyncMkdir(ignoreFailures bool) {
+	g.next = cmdline.New(&syncMkdirMode{g, ignoreFailures}, g)
+}
+
+// StartSyncTouch prompts for a file to create in every pane.
+// [IMPL:SYNC_CREATE_OPS] [ARCH:SYNC_MODE] [REQ:SYNC_CREATE_OPS]
+func (g *Goful) StartSyncTouch(ignoreFailures bool) {
+	g.next = cmdline.New(&syncTouchMode{g, ignoreFailures}, g)
+}
+
+// StartSyncChmod prompts for the mode to apply to a file across all panes.
+// [IMPL:SYNC_CREATE_OPS] [ARCH:SYNC_MODE] [REQ:SYNC_CREATE_OPS]
+func (g *Goful) StartSyncChmod(filename string, ignoreFailures bool) {
+	g.next = cmdline.New(&syncChmodMode{g, filename, ignoreFailures}, g)
+}
+
+// StartSyncSymlink prompts for the name of a symlink to a file in every pane.
+// [IMPL:SYNC_CREATE_OPS] [ARCH:SYNC_MODE] [REQ:SYNC_CREATE_OPS]
+func (g *Goful) StartSyncSymlink(filename string, ignoreFailures bool) {
+	c := cmdline.New(&syncSymlinkMode{g, filename, ignoreFailures}, g)
+	c.SetText(filename + ".link")
+	c.MoveCursor(-len(".link"))
+	g.next = c
+}
+
 // executeSyncCopy executes copy for a file across all panes.
 // Copies the source file to a new filename in each pane's directory.
 // [IMPL:SYNC_EXECUTE] [ARCH:SYNC_MODE] [REQ:SYNC_COMMANDS]
 func (g *Goful) executeSyncCopy(filename, newName string, ignoreFailures bool) {
+	if g.syncTransactional(ignoreFailures) {
+		g.executeSyncTransaction("copy", filename, syncTxnCopy(filename, newName))
+		return
+	}
+
 	ws := g.Workspace()
 	result := SyncResult{}
 
@@ -148,6 +242,11 @@ func (g *Goful) executeSyncCopy(filename, newName string, ignoreFailures bool) {
 // executeSyncDelete executes delete for a file across all panes.
 // [IMPL:SYNC_EXECUTE] [ARCH:SYNC_MODE] [REQ:SYNC_COMMANDS]
 func (g *Goful) executeSyncDelete(filename string, ignoreFailures bool) {
+	if g.syncTransactional(ignoreFailures) {
+		g.executeSyncTransaction("delete", filename, syncTxnDelete(filename))
+		return
+	}
+
 	ws := g.Workspace()
 	result := SyncResult{}
 
@@ -182,6 +281,11 @@ func (g *Goful) executeSyncDelete(filename string, ignoreFailures bool) {
 // executeSyncRename executes rename for a file across all panes.
 // [IMPL:SYNC_EXECUTE] [ARCH:SYNC_MODE] [REQ:SYNC_COMMANDS]
 func (g *Goful) executeSyncRename(oldName, newName string, ignoreFailures bool) {
+	if g.syncTransactional(ignoreFailures) {
+		g.executeSyncTransaction("rename", oldName, syncTxnRename(oldName, newName))
+		return
+	}
+
 	ws := g.Workspace()
 	result := SyncResult{}
 
@@ -216,20 +320,237 @@ func (g *Goful) executeSyncRename(oldName, newName string, ignoreFailures bool)
 	g.Workspace().ReloadAll()
 }
 
+// syncPaneFunc performs a sync operation in one pane's directory. It returns
+// skip when the pane has nothing to act on.
+// [IMPL:SYNC_CREATE_OPS] [ARCH:SYNC_MODE] [REQ:SYNC_CREATE_OPS]
+type syncPaneFunc func(dir *filer.Directory) (skip bool, err error)
+
+// runSyncEach runs fn in every pane starting from the focused one. Without
+// ignoreFailures the first failure stops the operation.
+// [IMPL:SYNC_CREATE_OPS] [ARCH:SYNC_MODE] [REQ:SYNC_CREATE_OPS]
+func runSyncEach(ws *filer.Workspace, ignoreFailures bool, fn syncPaneFunc) SyncResult {
+	result := SyncResult{}
+	for i := 0; i < len(ws.Dirs); i++ {
+		idx := (ws.Focus + i) % len(ws.Dirs)
+		skip, err := fn(ws.Dirs[idx])
+		switch {
+		case skip:
+			result.Skipped++
+		case err != nil:
+			result.Failures = append(result.Failures, SyncFailure{idx, err})
+			if !ignoreFailures {
+				return result
+			}
+		default:
+			result.Succeeded++
+		}
+	}
+	return result
+}
+
+// executeSyncEach runs fn in every pane, reports the result and reloads.
+// [IMPL:SYNC_CREATE_OPS] [ARCH:SYNC_MODE] [REQ:SYNC_CREATE_OPS]
+func (g *Goful) executeSyncEach(op, name string, ignoreFailures bool, fn syncPaneFunc) {
+	result := runSyncEach(g.Workspace(), ignoreFailures, fn)
+	g.reportSyncResult(op, name, result)
+	g.Workspace().ReloadAll()
+}
+
+// executeSyncMkdir makes the directory name in every pane's current directory.
+// [IMPL:SYNC_CREATE_OPS] [ARCH:SYNC_MODE] [REQ:SYNC_CREATE_OPS]
+func (g *Goful) executeSyncMkdir(name string, ignoreFailures bool) {
+	if g.syncTransactional(ignoreFailures) {
+		g.executeSyncTransaction("mkdir", name, syncTxnMkdir(name))
+		return
+	}
+	g.executeSyncEach("mkdir", name, ignoreFailures, syncMkdir(name))
+}
+
+// executeSyncTouch creates the file name in every pane's current directory.
+// [IMPL:SYNC_CREATE_OPS] [ARCH:SYNC_MODE] [REQ:SYNC_CREATE_OPS]
+func (g *Goful) executeSyncTouch(name string, ignoreFailures bool) {
+	if g.syncTransactional(ignoreFailures) {
+		g.executeSyncTransaction("newfile", name, syncTxnTouch(name))
+		return
+	}
+	g.executeSyncEach("newfile", name, ignoreFailures, syncTouch(name))
+}
+
+// executeSyncChmod changes the mode of the named file in every pane that has it.
+// [IMPL:SYNC_CREATE_OPS] [ARCH:SYNC_MODE] [REQ:SYNC_CREATE_OPS]
+func (g *Goful) executeSyncChmod(filename, spec string, ignoreFailures bool) {
+	if g.syncTransactional(ignoreFailures) {
+		g.executeSyncTransaction("chmod", filename, syncTxnChmod(filename, spec))
+		return
+	}
+	g.executeSyncEach("chmod", filename, ignoreFailures, syncChmod(filename, spec))
+}
+
+// executeSyncSymlink creates linkName pointing at the named file in every pane that has it.
+// [IMPL:SYNC_CREATE_OPS] [ARCH:SYNC_MODE] [REQ:SYNC_CREATE_OPS]
+func (g *Goful) executeSyncSymlink(filename, linkName string, ignoreFailures bool) {
+	if g.syncTransactional(ignoreFailures) {
+		g.executeSyncTransaction("symlink", filename, syncTxnSymlink(filename, linkName))
+		return
+	}
+	g.executeSyncEach("symlink", filename, ignoreFailures, syncSymlink(filename, linkName))
+}
+
+// syncMkdir makes name, with parents, in a pane's directory.
+func syncMkdir(name string) syncPaneFunc {
+	return func(dir *filer.Directory) (bool, error) {
+		return false, os.MkdirAll(filepath.Join(dir.Path, name), 0755)
+	}
+}
+
+// syncTouch creates name in a pane's directory if it does not exist.
+func syncTouch(name string) syncPaneFunc {
+	return func(dir *filer.Directory) (bool, error) {
+		file, err := os.OpenFile(filepath.Join(dir.Path, name), os.O_CREATE, 0644)
+		if err != nil {
+			return false, err
+		}
+		return false, file.Close()
+	}
+}
+
+// syncChmod applies spec, octal ("0644") or symbolic ("u+x,go-w"), to the
+// pane's own current mode of filename.
+func syncChmod(filename, spec string) syncPaneFunc {
+	return func(dir *filer.Directory) (bool, error) {
+		file := dir.FindFileByName(filename)
+		if file == nil {
+			return true, nil
+		}
+		info, err := os.Stat(file.Path())
+		if err != nil {
+			return false, err
+		}
+		mode, err := parseFileMode(spec, info.Mode())
+		if err != nil {
+			return false, err
+		}
+		return false, os.Chmod(file.Path(), mode)
+	}
+}
+
+// syncSymlink links linkName to filename in a pane's directory. The target
+// is relative, so each link points at its own pane's copy.
+func syncSymlink(filename, linkName string) syncPaneFunc {
+	return func(dir *filer.Directory) (bool, error) {
+		if dir.FindFileByName(filename) == nil {
+			return true, nil
+		}
+		return false, os.Symlink(filename, filepath.Join(dir.Path, linkName))
+	}
+}
+
+// parseFileMode returns the mode that spec sets on a file whose mode is
+// current. spec is an octal number ("755", "0644", "4755") or comma-separated
+// symbolic clauses like chmod(1): [ugoa]*([-+=][rwxXst]*)+. An empty "who"
+// means all, without applying the umask.
+// [IMPL:SYNC_CREATE_OPS] [ARCH:SYNC_MODE] [REQ:SYNC_CREATE_OPS]
+func parseFileMode(spec string, current os.FileMode) (os.FileMode, error) {
+	if spec == "" {
+		return 0, fmt.Errorf("empty mode")
+	}
+	if strings.Trim(spec, "01234567") == "" {
+		n, err := strconv.ParseUint(spec, 8, 32)
+		if err != nil || n > 07777 {
+			return 0, fmt.Errorf("invalid octal mode %q", spec)
+		}
+		mode := os.FileMode(n & 0777)
+		if n&04000 != 0 {
+			mode |= os.ModeSetuid
+		}
+		if n&02000 != 0 {
+			mode |= os.ModeSetgid
+		}
+		if n&01000 != 0 {
+			mode |= os.ModeSticky
+		}
+		return mode, nil
+	}
+
+	mode := current & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)
+	for _, clause := range strings.Split(spec, ",") {
+		i := 0
+		var who os.FileMode
+		for ; i < len(clause) && strings.IndexByte("ugoa", clause[i]) >= 0; i++ {
+			switch clause[i] {
+			case 'u':
+				who |= 0700 | os.ModeSetuid
+			case 'g':
+				who |= 0070 | os.ModeSetgid
+			case 'o':
+				who |= 0007
+			case 'a':
+				who |= 0777 | os.ModeSetuid | os.ModeSetgid
+			}
+		}
+		if who == 0 {
+			who = 0777 | os.ModeSetuid | os.ModeSetgid
+		}
+		if i == len(clause) {
+			return 0, fmt.Errorf("invalid mode %q: missing operator", spec)
+		}
+		for i < len(clause) {
+			op := clause[i]
+			if op != '+' && op != '-' && op != '=' {
+				return 0, fmt.Errorf("invalid mode %q: unexpected %q", spec, op)
+			}
+			i++
+			var perm os.FileMode
+			for ; i < len(clause) && strings.IndexByte("rwxXst", clause[i]) >= 0; i++ {
+				switch clause[i] {
+				case 'r':
+					perm |= 0444
+				case 'w':
+					perm |= 0222
+				case 'x':
+					perm |= 0111
+				case 'X':
+					if current.IsDir() || current&0111 != 0 {
+						perm |= 0111
+					}
+				case 's':
+					perm |= os.ModeSetuid | os.ModeSetgid
+				case 't':
+					perm |= os.ModeSticky
+				}
+			}
+			bits := perm & who
+			if perm&os.ModeSticky != 0 {
+				bits |= os.ModeSticky
+			}
+			switch op {
+			case '+':
+				mode |= bits
+			case '-':
+				mode &^= bits
+			case '=':
+				mode = mode&^(who&^os.ModeSticky) | bits
+			}
+		}
+	}
+	return mode, nil
+}
+
 // copyFileForSync copies a single file for sync operations.
 // [IMPL:SYNC_EXECUTE] [ARCH:SYNC_MODE] [REQ:SYNC_COMMANDS]
 func (g *Goful) copyFileForSync(src, dst string) error {
+	// Destination exists - overwrite without asking in sync mode
+	// since user already confirmed the operation
+	return copySyncPath(src, dst)
+}
+
+// copySyncPath copies a file, symlink or directory tree from src to dst.
+// [IMPL:SYNC_EXECUTE] [ARCH:SYNC_MODE] [REQ:SYNC_COMMANDS]
+func copySyncPath(src, dst string) error {
 	srcInfo, err := os.Lstat(src)
 	if err != nil {
 		return err
 	}
-
-	// Check if destination exists
-	if _, err := os.Lstat(dst); err == nil {
-		// Destination exists - overwrite without asking in sync mode
-		// since user already confirmed the operation
-	}
-
 	if srcInfo.IsDir() {
 		return copyDirRecursive(src, dst)
 	}
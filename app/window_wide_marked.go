@@ -0,0 +1,251 @@
+// Package app sync operations over the marked names of the focused pane.
+// [IMPL:SYNC_MARKED] [ARCH:SYNC_MODE] [REQ:SYNC_MARKED]
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/fareedst/goful/filer"
+	"github.com/fareedst/goful/message"
+)
+
+// syncNamePair is one source name and the name it is copied or renamed to.
+type syncNamePair struct {
+	from, to string
+}
+
+// syncMarkedPlan maps each marked name through the pattern/replacement regexp
+// the way bulk rename does. Names the regexp does not change are dropped.
+// Targets must be distinct and must not be marked names themselves, since the
+// operations run one name at a time.
+// [IMPL:SYNC_MARKED] [ARCH:SYNC_MODE] [REQ:SYNC_MARKED]
+func syncMarkedPlan(names []string, pattern, repl string) ([]syncNamePair, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	sources := make(map[string]bool, len(names))
+	for _, name := range names {
+		sources[name] = true
+	}
+	targets := map[string]string{}
+	pairs := []syncNamePair{}
+	for _, name := range names {
+		to := re.ReplaceAllString(name, repl)
+		switch {
+		case to == name:
+			continue
+		case to == "" || strings.ContainsRune(to, filepath.Separator):
+			return nil, fmt.Errorf("invalid name %q for %s", to, name)
+		case sources[to]:
+			return nil, fmt.Errorf("%s -> %s: target is also marked", name, to)
+		case targets[to] != "":
+			return nil, fmt.Errorf("%s and %s both map to %s", targets[to], name, to)
+		}
+		targets[to] = name
+		pairs = append(pairs, syncNamePair{name, to})
+	}
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("no matches found for %s", pattern)
+	}
+	return pairs, nil
+}
+
+// runSyncMarked runs fn for every name in every pane and aggregates the
+// results. Each failure is prefixed with its name. Without ignoreFailures the
+// first failure stops the whole operation.
+// [IMPL:SYNC_MARKED] [ARCH:SYNC_MODE] [REQ:SYNC_MARKED]
+func runSyncMarked(ws *filer.Workspace, names []string, ignoreFailures bool, fn func(name string) syncPaneFunc) SyncResult {
+	result := SyncResult{}
+	for _, name := range names {
+		r := runSyncEach(ws, ignoreFailures, fn(name))
+		result.Succeeded += r.Succeeded
+		result.Skipped += r.Skipped
+		for _, f := range r.Failures {
+			result.Failures = append(result.Failures, SyncFailure{f.PaneIndex, fmt.Errorf("%s: %w", name, f.Error)})
+		}
+		if len(r.Failures) > 0 && !ignoreFailures {
+			break
+		}
+	}
+	return result
+}
+
+// syncTxnBatch combines per-name operations into one all-or-nothing
+// operation: a pane is checked and applied for every name, and a failure on
+// any name undoes the names already applied in that pane.
+// [IMPL:SYNC_MARKED] [ARCH:SYNC_MODE] [REQ:SYNC_MARKED]
+func syncTxnBatch(ops []syncTxnOp) syncTxnOp {
+	return syncTxnOp{
+		check: func(dir *filer.Directory) (bool, int64, error) {
+			skip, need := true, int64(0)
+			for _, op := range ops {
+				s, n, err := op.check(dir)
+				if err != nil {
+					return false, 0, err
+				}
+				skip = skip && s
+				need += n
+			}
+			return skip, need, nil
+		},
+		apply: func(dir *filer.Directory) (func() error, func() error, error) {
+			var undos, commits []func() error
+			undo := func() error {
+				var errs []string
+				for i := len(undos) - 1; i >= 0; i-- {
+					if err := undos[i](); err != nil {
+						errs = append(errs, err.Error())
+					}
+				}
+				if len(errs) > 0 {
+					return fmt.Errorf("%s", strings.Join(errs, "; "))
+				}
+				return nil
+			}
+			for _, op := range ops {
+				if s, _, _ := op.check(dir); s {
+					continue
+				}
+				u, c, err := op.apply(dir)
+				if err != nil {
+					if uerr := undo(); uerr != nil {
+						err = fmt.Errorf("%v (partial undo failed: %v)", err, uerr)
+					}
+					return nil, nil, err
+				}
+				undos = append(undos, u)
+				if c != nil {
+					commits = append(commits, c)
+				}
+			}
+			commit := func() error {
+				for _, c := range commits {
+					if err := c(); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+			return undo, commit, nil
+		},
+	}
+}
+
+// syncMarkedLabel names a marked set in prompts and reports.
+func syncMarkedLabel(names []string) string {
+	return fmt.Sprintf("%d marked", len(names))
+}
+
+// executeSyncMarked runs a marked-name sync operation, all-or-nothing when
+// enabled, and reports the aggregated result.
+// [IMPL:SYNC_MARKED] [ARCH:SYNC_MODE] [REQ:SYNC_MARKED]
+func (g *Goful) executeSyncMarked(op string, names []string, ignoreFailures bool, fn func(name string) syncPaneFunc, txn func(name string) syncTxnOp) {
+	label := syncMarkedLabel(names)
+	if g.syncTransactional(ignoreFailures) {
+		ops := make([]syncTxnOp, len(names))
+		for i, name := range names {
+			ops[i] = txn(name)
+		}
+		g.executeSyncTransaction(op, label, syncTxnBatch(ops))
+		return
+	}
+	result := runSyncMarked(g.Workspace(), names, ignoreFailures, fn)
+	if len(result.Failures) > 0 {
+		message.Errorf("[REQ:SYNC_MARKED] %s", syncMarkedSummary(op, label, result))
+	} else {
+		message.Infof("[REQ:SYNC_MARKED] %s", syncMarkedSummary(op, label, result))
+	}
+	g.Workspace().ReloadAll()
+}
+
+// syncMarkedSummary describes the result of a marked-name operation in one
+// line: the counts followed by each failure with its pane and name.
+// [IMPL:SYNC_MARKED] [ARCH:SYNC_MODE] [REQ:SYNC_MARKED]
+func syncMarkedSummary(op, label string, result SyncResult) string {
+	summary := fmt.Sprintf("%s %s: %d succeeded, %d failed, %d skipped",
+		op, label, result.Succeeded, len(result.Failures), result.Skipped)
+	if len(result.Failures) == 0 {
+		return summary
+	}
+	details := make([]string, len(result.Failures))
+	for i, f := range result.Failures {
+		details[i] = fmt.Sprintf("pane %d: %v", f.PaneIndex+1, f.Error)
+	}
+	return summary + ": " + strings.Join(details, "; ")
+}
+
+// executeSyncCopyMarked copies each marked name to its mapped name in every pane.
+// [IMPL:SYNC_MARKED] [ARCH:SYNC_MODE] [REQ:SYNC_MARKED]
+func (g *Goful) executeSyncCopyMarked(pairs []syncNamePair, ignoreFailures bool) {
+	names, to := syncPairNames(pairs)
+	g.executeSyncMarked("copy", names, ignoreFailures,
+		func(name string) syncPaneFunc { return syncCopy(name, to[name]) },
+		func(name string) syncTxnOp { return syncTxnCopy(name, to[name]) })
+}
+
+// executeSyncRenameMarked renames each marked name to its mapped name in every pane.
+// [IMPL:SYNC_MARKED] [ARCH:SYNC_MODE] [REQ:SYNC_MARKED]
+func (g *Goful) executeSyncRenameMarked(pairs []syncNamePair, ignoreFailures bool) {
+	names, to := syncPairNames(pairs)
+	g.executeSyncMarked("rename", names, ignoreFailures,
+		func(name string) syncPaneFunc { return syncRename(name, to[name]) },
+		func(name string) syncTxnOp { return syncTxnRename(name, to[name]) })
+}
+
+// executeSyncDeleteMarked deletes every marked name in every pane.
+// [IMPL:SYNC_MARKED] [ARCH:SYNC_MODE] [REQ:SYNC_MARKED]
+func (g *Goful) executeSyncDeleteMarked(names []string, ignoreFailures bool) {
+	g.executeSyncMarked("delete", names, ignoreFailures, syncDelete, syncTxnDelete)
+}
+
+// syncPairNames returns the source names of pairs and their targets by name.
+func syncPairNames(pairs []syncNamePair) ([]string, map[string]string) {
+	names := make([]string, len(pairs))
+	to := make(map[string]string, len(pairs))
+	for i, p := range pairs {
+		names[i] = p.from
+		to[p.from] = p.to
+	}
+	return names, to
+}
+
+// syncCopy copies filename to newName in a pane that has it.
+// [IMPL:SYNC_MARKED] [ARCH:SYNC_MODE] [REQ:SYNC_MARKED]
+func syncCopy(filename, newName string) syncPaneFunc {
+	return func(dir *filer.Directory) (bool, error) {
+		file := dir.FindFileByName(filename)
+		if file == nil {
+			return true, nil
+		}
+		return false, copySyncPath(file.Path(), filepath.Join(dir.Path, newName))
+	}
+}
+
+// syncRename renames filename to newName in a pane that has it.
+// [IMPL:SYNC_MARKED] [ARCH:SYNC_MODE] [REQ:SYNC_MARKED]
+func syncRename(filename, newName string) syncPaneFunc {
+	return func(dir *filer.Directory) (bool, error) {
+		file := dir.FindFileByName(filename)
+		if file == nil {
+			return true, nil
+		}
+		return false, os.Rename(file.Path(), filepath.Join(dir.Path, newName))
+	}
+}
+
+// syncDelete removes filename in a pane that has it.
+// [IMPL:SYNC_MARKED] [ARCH:SYNC_MODE] [REQ:SYNC_MARKED]
+func syncDelete(filename string) syncPaneFunc {
+	return func(dir *filer.Directory) (bool, error) {
+		file := dir.FindFileByName(filename)
+		if file == nil {
+			return true, nil
+		}
+		return false, os.RemoveAll(file.Path())
+	}
+}
@@ -0,0 +1,139 @@
+// Package app tests for sync operations over marked names.
+// [IMPL:SYNC_MARKED] [ARCH:SYNC_MODE] [REQ:SYNC_MARKED]
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fareedst/goful/filer"
+)
+
+// TestSyncMarkedPlan_REQ_SYNC_MARKED tests mapping marked names through a regexp.
+func TestSyncMarkedPlan_REQ_SYNC_MARKED(t *testing.T) {
+	pairs, err := syncMarkedPlan([]string{"a.o", "b.o", "c.h"}, `\.o$`, ".o.bak")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 2 || pairs[0] != (syncNamePair{"a.o", "a.o.bak"}) || pairs[1] != (syncNamePair{"b.o", "b.o.bak"}) {
+		t.Errorf("pairs = %v", pairs)
+	}
+
+	for _, tc := range []struct{ pattern, repl, want string }{
+		{`x`, "y", "no matches"},
+		{`^a`, "b", "also marked"},
+		{`^[ab]`, "z", "both map"},
+		{`^a\.o$`, "d/e", "invalid name"},
+		{`(`, "", "missing closing"},
+	} {
+		if _, err := syncMarkedPlan([]string{"a.o", "b.o"}, tc.pattern, tc.repl); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s/%s: err = %v, want %q", tc.pattern, tc.repl, err, tc.want)
+		}
+	}
+}
+
+// TestRunSyncMarked_REQ_SYNC_MARKED tests applying several names in every pane with an aggregated result.
+func TestRunSyncMarked_REQ_SYNC_MARKED(t *testing.T) {
+	tmp1, tmp2 := t.TempDir(), t.TempDir()
+	for _, name := range []string{"a.o", "b.o", "keep"} {
+		os.WriteFile(filepath.Join(tmp1, name), nil, 0644)
+	}
+	os.WriteFile(filepath.Join(tmp2, "a.o"), nil, 0644)
+	ws := newTestGoful(t, tmp1, tmp2).Workspace()
+
+	names := []string{"a.o", "b.o"}
+	result := runSyncMarked(ws, names, false, func(name string) syncPaneFunc { return syncCopy(name, name+".bak") })
+	if result.Succeeded != 3 || result.Skipped != 1 || len(result.Failures) != 0 {
+		t.Fatalf("copy result = %+v", result)
+	}
+	ws.ReloadAll()
+
+	result = runSyncMarked(ws, names, false, syncDelete)
+	if result.Succeeded != 3 || result.Skipped != 1 {
+		t.Fatalf("delete result = %+v", result)
+	}
+	for dir, want := range map[string]string{tmp1: "a.o.bak b.o.bak keep", tmp2: "a.o.bak"} {
+		entries, _ := os.ReadDir(dir)
+		got := make([]string, len(entries))
+		for i, e := range entries {
+			got[i] = e.Name()
+		}
+		if strings.Join(got, " ") != want {
+			t.Errorf("%s = %v, want %s", dir, got, want)
+		}
+	}
+}
+
+// TestRunSyncMarkedFailures_REQ_SYNC_MARKED tests stopping on the first failure, ignore-failures and failure naming.
+func TestRunSyncMarkedFailures_REQ_SYNC_MARKED(t *testing.T) {
+	tmp1, tmp2 := t.TempDir(), t.TempDir()
+	ws := newTestGoful(t, tmp1, tmp2).Workspace()
+	fail := func(name string) syncPaneFunc {
+		return func(dir *filer.Directory) (bool, error) {
+			if name == "bad" && dir == ws.Dirs[1] {
+				return false, errors.New("denied")
+			}
+			return false, nil
+		}
+	}
+
+	result := runSyncMarked(ws, []string{"bad", "later"}, false, fail)
+	if result.Succeeded != 1 || len(result.Failures) != 1 || result.Failures[0].PaneIndex != 1 {
+		t.Fatalf("result = %+v", result)
+	}
+	if got := result.Failures[0].Error.Error(); got != "bad: denied" {
+		t.Errorf("failure = %q", got)
+	}
+
+	result = runSyncMarked(ws, []string{"bad", "later"}, true, fail)
+	if result.Succeeded != 3 || len(result.Failures) != 1 {
+		t.Errorf("ignore-failures result = %+v", result)
+	}
+	want := "delete 2 marked: 3 succeeded, 1 failed, 0 skipped: pane 2: bad: denied"
+	if got := syncMarkedSummary("delete", syncMarkedLabel([]string{"bad", "later"}), result); got != want {
+		t.Errorf("summary = %q, want %q", got, want)
+	}
+}
+
+// TestSyncTxnBatch_REQ_SYNC_MARKED tests that all-or-nothing mode reverts every marked name in every pane.
+func TestSyncTxnBatch_REQ_SYNC_MARKED(t *testing.T) {
+	tmp := []string{t.TempDir(), t.TempDir(), t.TempDir()}
+	for _, dir := range tmp {
+		os.WriteFile(filepath.Join(dir, "a"), nil, 0644)
+		os.WriteFile(filepath.Join(dir, "b"), nil, 0644)
+	}
+	ws := newTxnWorkspace(t, tmp...)
+
+	// The rename of "b" fails in pane 3 after "a" was renamed there.
+	rb := syncTxnRename("b", "b2")
+	apply := rb.apply
+	rb.apply = func(dir *filer.Directory) (func() error, func() error, error) {
+		if dir == ws.Dirs[2] {
+			return nil, nil, errors.New("denied")
+		}
+		return apply(dir)
+	}
+	result := runSyncTransaction(ws, syncTxnBatch([]syncTxnOp{syncTxnRename("a", "a2"), rb}))
+	if len(result.Failures) != 1 || result.Failures[0].PaneIndex != 2 || len(result.Reverted) != 2 {
+		t.Fatalf("result = %+v", result)
+	}
+	for i, dir := range tmp {
+		entries, _ := os.ReadDir(dir)
+		if len(entries) != 2 || entries[0].Name() != "a" || entries[1].Name() != "b" {
+			t.Errorf("pane %d = %v", i+1, entries)
+		}
+	}
+
+	result = runSyncTransaction(ws, syncTxnBatch([]syncTxnOp{syncTxnDelete("a"), syncTxnDelete("b"), syncTxnDelete("missing")}))
+	if result.Succeeded != 3 || len(result.Failures) != 0 {
+		t.Fatalf("delete result = %+v", result)
+	}
+	for i, dir := range tmp {
+		if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+			t.Errorf("pane %d left %v", i+1, entries)
+		}
+	}
+}
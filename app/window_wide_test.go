@@ -207,8 +207,121 @@ func TestSyncMode_REQ_SYNC_COMMANDS(t *testing.T) {
 			t.Errorf("prompt should indicate ignore mode: %s", prompt)
 		}
 	})
+
+	// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+	t.Run("prompt atomic mode", func(t *testing.T) {
+		mode := &syncMode{Goful: &Goful{syncAtomic: true}}
+		prompt := mode.Prompt()
+		if !containsSubstring(prompt, "[a]tomic off") || !containsSubstring(prompt, "all or nothing") {
+			t.Errorf("prompt should show how to leave all-or-nothing mode: %s", prompt)
+		}
+	})
 }
 
 func containsSubstring(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > 0 && containsSubstring(s[1:], substr) || (len(s) >= len(substr) && s[:len(substr)] == substr))
 }
+
+// TestParseFileMode_REQ_SYNC_CREATE_OPS tests octal and symbolic mode specs.
+// [IMPL:SYNC_CREATE_OPS] [ARCH:SYNC_MODE] [REQ:SYNC_CREATE_OPS]
+func TestParseFileMode_REQ_SYNC_CREATE_OPS(t *testing.T) {
+	tests := []struct {
+		spec    string
+		current os.FileMode
+		want    os.FileMode
+	}{
+		{"0644", 0755, 0644},
+		{"755", 0600, 0755},
+		{"4755", 0644, 0755 | os.ModeSetuid},
+		{"u+x", 0644, 0744},
+		{"go-w", 0666, 0644},
+		{"a=r", 0755, 0444},
+		{"+x", 0644, 0755},
+		{"u=rwx,g=rx,o=", 0600, 0750},
+		{"u+x-w", 0644, 0544},
+		{"a+X", 0644, 0644},
+		{"a+X", 0744, 0755},
+		{"a+X", os.ModeDir | 0644, 0755},
+		{"+t", 0755, 0755 | os.ModeSticky},
+		{"g+s", 0755, 0755 | os.ModeSetgid},
+	}
+	for _, tt := range tests {
+		got, err := parseFileMode(tt.spec, tt.current)
+		if err != nil || got != tt.want {
+			t.Errorf("parseFileMode(%q, %v) = %v, %v; want %v", tt.spec, tt.current, got, err, tt.want)
+		}
+	}
+
+	for _, spec := range []string{"", "888", "77777", "u", "u+q", "z+x", "u+x,"} {
+		if _, err := parseFileMode(spec, 0644); err == nil {
+			t.Errorf("parseFileMode(%q) should fail", spec)
+		}
+	}
+}
+
+// TestRunSyncEachCreateOps_REQ_SYNC_CREATE_OPS tests mkdir, newfile, chmod and symlink in every pane.
+// [IMPL:SYNC_CREATE_OPS] [ARCH:SYNC_MODE] [REQ:SYNC_CREATE_OPS]
+func TestRunSyncEachCreateOps_REQ_SYNC_CREATE_OPS(t *testing.T) {
+	tmp1, tmp2 := t.TempDir(), t.TempDir()
+	for _, tmp := range []string{tmp1, tmp2} {
+		os.WriteFile(filepath.Join(tmp, "run.sh"), []byte("#!/bin/sh\n"), 0644)
+	}
+	ws := newTestGoful(t, tmp1, tmp2).Workspace()
+
+	for _, fn := range []syncPaneFunc{
+		syncMkdir("conf/d"),
+		syncTouch("new.txt"),
+		syncChmod("run.sh", "u+x,go-r"),
+		syncSymlink("run.sh", "run"),
+	} {
+		if result := runSyncEach(ws, false, fn); result.Succeeded != 2 || len(result.Failures) != 0 {
+			t.Fatalf("result = %+v", result)
+		}
+	}
+
+	for _, tmp := range []string{tmp1, tmp2} {
+		if info, err := os.Stat(filepath.Join(tmp, "conf", "d")); err != nil || !info.IsDir() {
+			t.Errorf("%s: conf/d not made: %v", tmp, err)
+		}
+		if _, err := os.Stat(filepath.Join(tmp, "new.txt")); err != nil {
+			t.Errorf("%s: new.txt not created: %v", tmp, err)
+		}
+		if info, _ := os.Stat(filepath.Join(tmp, "run.sh")); info == nil || info.Mode().Perm() != 0700 {
+			t.Errorf("%s: run.sh mode = %v, want 0700", tmp, info)
+		}
+		if link, err := os.Readlink(filepath.Join(tmp, "run")); err != nil || link != "run.sh" {
+			t.Errorf("%s: run -> %q, %v", tmp, link, err)
+		}
+	}
+}
+
+// TestRunSyncEachFailures_REQ_SYNC_CREATE_OPS tests skipping, stopping on failure and ignore-failures.
+// [IMPL:SYNC_CREATE_OPS] [ARCH:SYNC_MODE] [REQ:SYNC_CREATE_OPS]
+func TestRunSyncEachFailures_REQ_SYNC_CREATE_OPS(t *testing.T) {
+	tmp1, tmp2 := t.TempDir(), t.TempDir()
+	os.WriteFile(filepath.Join(tmp2, "only2.txt"), nil, 0644)
+	ws := newTestGoful(t, tmp1, tmp2).Workspace()
+
+	// The file exists only in pane 2: pane 1 is skipped, not failed.
+	if result := runSyncEach(ws, false, syncSymlink("only2.txt", "l")); result.Skipped != 1 || result.Succeeded != 1 {
+		t.Errorf("symlink result = %+v", result)
+	}
+
+	// A file named "blocked" in pane 1 makes mkdir fail there first.
+	os.WriteFile(filepath.Join(tmp1, "blocked"), nil, 0644)
+	result := runSyncEach(ws, false, syncMkdir("blocked/sub"))
+	if len(result.Failures) != 1 || result.Failures[0].PaneIndex != 0 || result.Succeeded != 0 {
+		t.Errorf("mkdir result = %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(tmp2, "blocked")); !os.IsNotExist(err) {
+		t.Errorf("pane 2 should not run after pane 1 failed: %v", err)
+	}
+
+	result = runSyncEach(ws, true, syncMkdir("blocked/sub"))
+	if len(result.Failures) != 1 || result.Succeeded != 1 {
+		t.Errorf("ignore-failures mkdir result = %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(tmp2, "blocked", "sub")); err != nil {
+		t.Errorf("ignore failures should continue to pane 2: %v", err)
+	}
+}
@@ -0,0 +1,461 @@
+// Package app all-or-nothing sync operations with pre-checks and rollback.
+// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fareedst/goful/filer"
+	"github.com/fareedst/goful/message"
+	"github.com/fareedst/goful/util"
+)
+
+// SyncTxnResult holds the result of an all-or-nothing sync operation.
+// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+type SyncTxnResult struct {
+	SyncResult
+	Aborted        bool          // A pre-check failed; no pane was changed
+	Reverted       []int         // Panes rolled back after a failure, in rollback order
+	RevertFailures []SyncFailure // Panes whose rollback failed and need attention
+	CommitFailures []SyncFailure // Panes whose staged leftovers could not be cleaned up
+}
+
+// syncTxnOp describes one sync operation for runSyncTransaction.
+// check inspects a pane without changing it and returns the bytes the
+// operation will write there. apply changes the pane and returns how to undo
+// it and, optionally, how to finalize it once every pane has succeeded.
+// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+type syncTxnOp struct {
+	check func(dir *filer.Directory) (skip bool, need int64, err error)
+	apply func(dir *filer.Directory) (undo, commit func() error, err error)
+}
+
+// syncTxnStep is an applied pane waiting for commit or rollback.
+type syncTxnStep struct {
+	pane         int
+	undo, commit func() error
+}
+
+// runSyncTransaction pre-checks every pane, then applies op pane by pane
+// from the focused one. If a pane fails, the panes already applied are
+// reverted in reverse order.
+// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+func runSyncTransaction(ws *filer.Workspace, op syncTxnOp) SyncTxnResult {
+	result := SyncTxnResult{}
+	var panes []int
+	var needs []int64
+	for i := 0; i < len(ws.Dirs); i++ {
+		idx := (ws.Focus + i) % len(ws.Dirs)
+		dir := ws.Dirs[idx]
+		skip, need, err := op.check(dir)
+		if err == nil && !skip {
+			err = checkSyncTarget(dir.Path)
+		}
+		switch {
+		case err != nil:
+			result.Failures = append(result.Failures, SyncFailure{idx, err})
+		case skip:
+			result.Skipped++
+		default:
+			panes = append(panes, idx)
+			needs = append(needs, need)
+		}
+	}
+	if len(result.Failures) == 0 {
+		result.Failures = checkSyncSpace(ws.Dirs, panes, needs)
+	}
+	if len(result.Failures) > 0 {
+		result.Aborted = true
+		return result
+	}
+
+	var applied []syncTxnStep
+	for _, idx := range panes {
+		undo, commit, err := op.apply(ws.Dirs[idx])
+		if err != nil {
+			result.Failures = append(result.Failures, SyncFailure{idx, err})
+			for i := len(applied) - 1; i >= 0; i-- {
+				step := applied[i]
+				if err := step.undo(); err != nil {
+					result.RevertFailures = append(result.RevertFailures, SyncFailure{step.pane, err})
+				} else {
+					result.Reverted = append(result.Reverted, step.pane)
+				}
+			}
+			return result
+		}
+		applied = append(applied, syncTxnStep{idx, undo, commit})
+	}
+
+	for _, step := range applied {
+		if step.commit != nil {
+			if err := step.commit(); err != nil {
+				result.CommitFailures = append(result.CommitFailures, SyncFailure{step.pane, err})
+			}
+		}
+		result.Succeeded++
+	}
+	return result
+}
+
+// checkSyncTarget verifies that dir accepts new entries.
+func checkSyncTarget(dir string) error {
+	probe, err := os.CreateTemp(dir, ".goful-check-*")
+	if err != nil {
+		return fmt.Errorf("not writable: %w", err)
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+	return nil
+}
+
+// checkSyncSpace verifies that every filesystem has room for the bytes the
+// panes on it need together, since panes sharing a filesystem draw on the
+// same free space. needs[i] is the need of pane panes[i]; a shortage is
+// reported for every pane on the filesystem.
+// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+func checkSyncSpace(dirs []*filer.Directory, panes []int, needs []int64) []SyncFailure {
+	type filesystem struct {
+		need  int64
+		panes []int
+	}
+	var failures []SyncFailure
+	var order []string
+	byID := map[string]*filesystem{}
+	for i, idx := range panes {
+		if needs[i] <= 0 {
+			continue
+		}
+		id, err := filesystemID(dirs[idx].Path)
+		if err != nil {
+			failures = append(failures, SyncFailure{idx, err})
+			continue
+		}
+		fs := byID[id]
+		if fs == nil {
+			fs = &filesystem{}
+			byID[id] = fs
+			order = append(order, id)
+		}
+		fs.need += needs[i]
+		fs.panes = append(fs.panes, idx)
+	}
+	for _, id := range order {
+		fs := byID[id]
+		free, err := freeSpace(dirs[fs.panes[0]].Path)
+		if err == nil && uint64(fs.need) > free {
+			err = fmt.Errorf("insufficient space: need %s, %s free", util.FormatSize(fs.need), util.FormatSize(int64(free)))
+			if len(fs.panes) > 1 {
+				names := make([]string, len(fs.panes))
+				for i, idx := range fs.panes {
+					names[i] = fmt.Sprint(idx + 1)
+				}
+				err = fmt.Errorf("insufficient space: panes %s need %s together, %s free",
+					strings.Join(names, ", "), util.FormatSize(fs.need), util.FormatSize(int64(free)))
+			}
+		}
+		if err != nil {
+			for _, idx := range fs.panes {
+				failures = append(failures, SyncFailure{idx, err})
+			}
+		}
+	}
+	return failures
+}
+
+// syncStagingAttempts bounds the search for an unused staging name.
+const syncStagingAttempts = 100
+
+// syncStagingName returns an unused hidden name next to path for staging.
+// It fails when the directory cannot be searched or no name is free.
+func syncStagingName(path string) (string, error) {
+	dir, base := filepath.Split(path)
+	for n := 0; n < syncStagingAttempts; n++ {
+		name := filepath.Join(dir, fmt.Sprintf(".%s.goful-txn-%d-%d", base, os.Getpid(), time.Now().UnixNano()+int64(n)))
+		if _, err := os.Lstat(name); os.IsNotExist(err) {
+			return name, nil
+		} else if err != nil {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("no free staging name for %s", path)
+}
+
+// syncConflict returns an error if path already exists.
+func syncConflict(path string) error {
+	if _, err := os.Lstat(path); err == nil {
+		return fmt.Errorf("conflict: %s exists", filepath.Base(path))
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// syncTxnCopy copies filename to newName in every pane that has it. The copy
+// is written under a staging name and renamed into place.
+// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+func syncTxnCopy(filename, newName string) syncTxnOp {
+	return syncTxnOp{
+		check: func(dir *filer.Directory) (bool, int64, error) {
+			file := dir.FindFileByName(filename)
+			if file == nil {
+				return true, 0, nil
+			}
+			size, _ := util.CalcSizeCount(file.Path())
+			dst := filepath.Join(dir.Path, newName)
+			if err := syncConflict(dst); err != nil {
+				return false, 0, err
+			}
+			_, err := syncStagingName(dst)
+			return false, size, err
+		},
+		apply: func(dir *filer.Directory) (func() error, func() error, error) {
+			src := filepath.Join(dir.Path, filename)
+			dst := filepath.Join(dir.Path, newName)
+			staged, err := syncStagingName(dst)
+			if err != nil {
+				return nil, nil, err
+			}
+			if err := copySyncPath(src, staged); err != nil {
+				os.RemoveAll(staged)
+				return nil, nil, err
+			}
+			if err := syncConflict(dst); err != nil {
+				os.RemoveAll(staged)
+				return nil, nil, err
+			}
+			if err := os.Rename(staged, dst); err != nil {
+				os.RemoveAll(staged)
+				return nil, nil, err
+			}
+			return func() error { return os.RemoveAll(dst) }, nil, nil
+		},
+	}
+}
+
+// syncTxnRename renames oldName to newName in every pane that has it.
+// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+func syncTxnRename(oldName, newName string) syncTxnOp {
+	return syncTxnOp{
+		check: func(dir *filer.Directory) (bool, int64, error) {
+			if dir.FindFileByName(oldName) == nil {
+				return true, 0, nil
+			}
+			return false, 0, syncConflict(filepath.Join(dir.Path, newName))
+		},
+		apply: func(dir *filer.Directory) (func() error, func() error, error) {
+			oldPath := filepath.Join(dir.Path, oldName)
+			newPath := filepath.Join(dir.Path, newName)
+			if err := syncConflict(newPath); err != nil {
+				return nil, nil, err
+			}
+			if err := os.Rename(oldPath, newPath); err != nil {
+				return nil, nil, err
+			}
+			return func() error { return os.Rename(newPath, oldPath) }, nil, nil
+		},
+	}
+}
+
+// syncTxnDelete deletes filename in every pane that has it. Each file is
+// first moved to a staging name; it is removed only when every pane succeeded.
+// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+func syncTxnDelete(filename string) syncTxnOp {
+	return syncTxnOp{
+		check: func(dir *filer.Directory) (bool, int64, error) {
+			if dir.FindFileByName(filename) == nil {
+				return true, 0, nil
+			}
+			_, err := syncStagingName(filepath.Join(dir.Path, filename))
+			return false, 0, err
+		},
+		apply: func(dir *filer.Directory) (func() error, func() error, error) {
+			path := filepath.Join(dir.Path, filename)
+			staged, err := syncStagingName(path)
+			if err != nil {
+				return nil, nil, err
+			}
+			if err := os.Rename(path, staged); err != nil {
+				return nil, nil, err
+			}
+			undo := func() error { return os.Rename(staged, path) }
+			commit := func() error { return os.RemoveAll(staged) }
+			return undo, commit, nil
+		},
+	}
+}
+
+// syncTxnMkdir makes name in every pane; panes where it is already a
+// directory are skipped. Rollback removes only the directories it created.
+// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+func syncTxnMkdir(name string) syncTxnOp {
+	return syncTxnOp{
+		check: func(dir *filer.Directory) (bool, int64, error) {
+			info, err := os.Stat(filepath.Join(dir.Path, name))
+			switch {
+			case err == nil && info.IsDir():
+				return true, 0, nil
+			case err == nil:
+				return false, 0, fmt.Errorf("conflict: %s exists", name)
+			case !os.IsNotExist(err):
+				return false, 0, err
+			}
+			return false, 0, nil
+		},
+		apply: func(dir *filer.Directory) (func() error, func() error, error) {
+			path := filepath.Join(dir.Path, name)
+			top := path // the outermost directory this call creates
+			for p := path; strings.HasPrefix(p, dir.Path+string(filepath.Separator)); p = filepath.Dir(p) {
+				if _, err := os.Stat(p); err == nil {
+					break
+				}
+				top = p
+			}
+			if err := os.MkdirAll(path, 0755); err != nil {
+				os.RemoveAll(top)
+				return nil, nil, err
+			}
+			return func() error { return os.RemoveAll(top) }, nil, nil
+		},
+	}
+}
+
+// syncTxnTouch creates name in every pane where it does not exist.
+// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+func syncTxnTouch(name string) syncTxnOp {
+	return syncTxnOp{
+		check: func(dir *filer.Directory) (bool, int64, error) {
+			_, err := os.Lstat(filepath.Join(dir.Path, name))
+			return err == nil, 0, nil
+		},
+		apply: func(dir *filer.Directory) (func() error, func() error, error) {
+			path := filepath.Join(dir.Path, name)
+			file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL, 0644)
+			if err != nil {
+				return nil, nil, err
+			}
+			if err := file.Close(); err != nil {
+				os.Remove(path)
+				return nil, nil, err
+			}
+			return func() error { return os.Remove(path) }, nil, nil
+		},
+	}
+}
+
+// syncTxnChmod applies spec to filename in every pane that has it; rollback
+// restores each pane's previous mode.
+// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+func syncTxnChmod(filename, spec string) syncTxnOp {
+	return syncTxnOp{
+		check: func(dir *filer.Directory) (bool, int64, error) {
+			file := dir.FindFileByName(filename)
+			if file == nil {
+				return true, 0, nil
+			}
+			info, err := os.Stat(file.Path())
+			if err != nil {
+				return false, 0, err
+			}
+			_, err = parseFileMode(spec, info.Mode())
+			return false, 0, err
+		},
+		apply: func(dir *filer.Directory) (func() error, func() error, error) {
+			path := filepath.Join(dir.Path, filename)
+			info, err := os.Stat(path)
+			if err != nil {
+				return nil, nil, err
+			}
+			mode, err := parseFileMode(spec, info.Mode())
+			if err != nil {
+				return nil, nil, err
+			}
+			if err := os.Chmod(path, mode); err != nil {
+				return nil, nil, err
+			}
+			old := info.Mode() & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)
+			return func() error { return os.Chmod(path, old) }, nil, nil
+		},
+	}
+}
+
+// syncTxnSymlink creates linkName pointing at filename in every pane that has it.
+// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+func syncTxnSymlink(filename, linkName string) syncTxnOp {
+	return syncTxnOp{
+		check: func(dir *filer.Directory) (bool, int64, error) {
+			if dir.FindFileByName(filename) == nil {
+				return true, 0, nil
+			}
+			return false, 0, syncConflict(filepath.Join(dir.Path, linkName))
+		},
+		apply: func(dir *filer.Directory) (func() error, func() error, error) {
+			link := filepath.Join(dir.Path, linkName)
+			if err := os.Symlink(filename, link); err != nil {
+				return nil, nil, err
+			}
+			return func() error { return os.Remove(link) }, nil, nil
+		},
+	}
+}
+
+// syncTransactional reports whether a sync operation should run all-or-nothing.
+// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+func (g *Goful) syncTransactional(ignoreFailures bool) bool {
+	return g.syncAtomic && !ignoreFailures
+}
+
+// executeSyncTransaction runs op all-or-nothing, reports the result and reloads.
+// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+func (g *Goful) executeSyncTransaction(op, name string, txn syncTxnOp) {
+	result := runSyncTransaction(g.Workspace(), txn)
+	reportSyncTxnResult(op, name, result)
+	g.Workspace().ReloadAll()
+}
+
+// reportSyncTxnResult displays the result of an all-or-nothing sync operation.
+// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+func reportSyncTxnResult(op, name string, result SyncTxnResult) {
+	if msg, failed := syncTxnSummary(op, name, result); failed {
+		message.Errorf("%s", msg)
+	} else {
+		message.Info(msg)
+	}
+}
+
+// syncTxnSummary describes result for the message line; failed is true
+// unless every pane was applied.
+func syncTxnSummary(op, name string, result SyncTxnResult) (string, bool) {
+	panes := func(failures []SyncFailure) string {
+		parts := make([]string, len(failures))
+		for i, f := range failures {
+			parts[i] = fmt.Sprintf("pane %d: %v", f.PaneIndex+1, f.Error)
+		}
+		return strings.Join(parts, "; ")
+	}
+
+	prefix := fmt.Sprintf("[REQ:SYNC_TRANSACTION] %s '%s'", op, name)
+	switch {
+	case result.Aborted:
+		return fmt.Sprintf("%s: pre-check failed, nothing changed (%s)", prefix, panes(result.Failures)), true
+	case len(result.Failures) > 0:
+		reverted := make([]int, len(result.Reverted))
+		for i, p := range result.Reverted {
+			reverted[i] = p + 1
+		}
+		msg := fmt.Sprintf("%s: failed (%s); reverted panes %v", prefix, panes(result.Failures), reverted)
+		if len(result.RevertFailures) > 0 {
+			msg += fmt.Sprintf("; COULD NOT REVERT %s", panes(result.RevertFailures))
+		}
+		return msg, true
+	}
+	msg := fmt.Sprintf("%s: %d succeeded, %d skipped (all or nothing)", prefix, result.Succeeded, result.Skipped)
+	if len(result.CommitFailures) > 0 {
+		return fmt.Sprintf("%s; leftover staging files (%s)", msg, panes(result.CommitFailures)), true
+	}
+	return msg, false
+}
@@ -0,0 +1,184 @@
+// Package app all-or-nothing sync operation tests.
+// [IMPL:SYNC_TRANSACTION] [ARCH:SYNC_TRANSACTION] [REQ:SYNC_TRANSACTION]
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fareedst/goful/filer"
+)
+
+// newTxnWorkspace returns a workspace with one pane per directory, focused on the first.
+func newTxnWorkspace(t *testing.T, dirs ...string) *filer.Workspace {
+	t.Helper()
+	ws := filer.NewWorkspace(0, 0, 120, 20, "test")
+	for i, path := range dirs {
+		dir := filer.NewDirectory(i*40, 0, 40, 20)
+		dir.Chdir(path)
+		ws.Dirs = append(ws.Dirs, dir)
+	}
+	ws.SetFocus(0)
+	return ws
+}
+
+// TestSyncTransactionPreCheck_REQ_SYNC_TRANSACTION verifies a conflict in any pane aborts before anything changes.
+func TestSyncTransactionPreCheck_REQ_SYNC_TRANSACTION(t *testing.T) {
+	tmp := []string{t.TempDir(), t.TempDir(), t.TempDir()}
+	for _, dir := range tmp {
+		os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644)
+	}
+	os.WriteFile(filepath.Join(tmp[2], "b.txt"), []byte("b"), 0644)
+	ws := newTxnWorkspace(t, tmp...)
+
+	for name, op := range map[string]syncTxnOp{
+		"rename": syncTxnRename("a.txt", "b.txt"),
+		"copy":   syncTxnCopy("a.txt", "b.txt"),
+	} {
+		result := runSyncTransaction(ws, op)
+		if !result.Aborted || len(result.Failures) != 1 || result.Failures[0].PaneIndex != 2 || result.Succeeded != 0 {
+			t.Errorf("%s: result = %+v", name, result)
+		}
+		for i, dir := range tmp[:2] {
+			if _, err := os.Stat(filepath.Join(dir, "a.txt")); err != nil {
+				t.Errorf("%s: pane %d a.txt changed: %v", name, i+1, err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "b.txt")); !os.IsNotExist(err) {
+				t.Errorf("%s: pane %d b.txt created", name, i+1)
+			}
+		}
+	}
+
+	// A file in the way of a directory is reported by the pre-check as well.
+	os.WriteFile(filepath.Join(tmp[1], "blocked"), nil, 0644)
+	if result := runSyncTransaction(ws, syncTxnMkdir("blocked/sub")); !result.Aborted {
+		t.Errorf("mkdir result = %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(tmp[0], "blocked")); !os.IsNotExist(err) {
+		t.Errorf("mkdir changed pane 1 after a failed pre-check")
+	}
+}
+
+// TestSyncTransactionRollback_REQ_SYNC_TRANSACTION verifies a failure in pane 3 reverts panes 1 and 2.
+func TestSyncTransactionRollback_REQ_SYNC_TRANSACTION(t *testing.T) {
+	tmp := []string{t.TempDir(), t.TempDir(), t.TempDir(), t.TempDir()}
+	for _, dir := range tmp {
+		os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644)
+	}
+	ws := newTxnWorkspace(t, tmp...)
+
+	for name, op := range map[string]syncTxnOp{
+		"rename": syncTxnRename("a.txt", "b.txt"),
+		"copy":   syncTxnCopy("a.txt", "b.txt"),
+		"delete": syncTxnDelete("a.txt"),
+		"chmod":  syncTxnChmod("a.txt", "600"),
+	} {
+		// Fail the third pane after the pre-check has passed.
+		apply := op.apply
+		op.apply = func(dir *filer.Directory) (func() error, func() error, error) {
+			if dir == ws.Dirs[2] {
+				return nil, nil, errors.New("disk on fire")
+			}
+			return apply(dir)
+		}
+		result := runSyncTransaction(ws, op)
+		if result.Aborted || len(result.Failures) != 1 || result.Failures[0].PaneIndex != 2 {
+			t.Fatalf("%s: result = %+v", name, result)
+		}
+		if len(result.Reverted) != 2 || result.Reverted[0] != 1 || result.Reverted[1] != 0 || len(result.RevertFailures) != 0 {
+			t.Errorf("%s: reverted = %v, failures = %v", name, result.Reverted, result.RevertFailures)
+		}
+		for i, dir := range tmp {
+			entries, _ := os.ReadDir(dir)
+			if len(entries) != 1 || entries[0].Name() != "a.txt" {
+				t.Errorf("%s: pane %d left %v", name, i+1, entries)
+			}
+			if info, err := os.Stat(filepath.Join(dir, "a.txt")); err != nil || info.Mode().Perm() != 0644 {
+				t.Errorf("%s: pane %d a.txt = %v, %v", name, i+1, info, err)
+			}
+		}
+	}
+}
+
+// TestSyncStagingName_REQ_SYNC_TRANSACTION verifies staging names are unused and that a
+// directory that cannot be searched fails instead of retrying forever.
+func TestSyncStagingName_REQ_SYNC_TRANSACTION(t *testing.T) {
+	tmp := t.TempDir()
+	name, err := syncStagingName(filepath.Join(tmp, "a.txt"))
+	if err != nil || filepath.Dir(name) != tmp || !strings.HasPrefix(filepath.Base(name), ".a.txt.goful-txn-") {
+		t.Fatalf("syncStagingName = %q, %v", name, err)
+	}
+
+	notDir := filepath.Join(tmp, "file")
+	os.WriteFile(notDir, nil, 0644)
+	if name, err := syncStagingName(filepath.Join(notDir, "a.txt")); err == nil {
+		t.Errorf("syncStagingName under a file = %q, want an error", name)
+	}
+}
+
+// TestSyncTransactionCommit_REQ_SYNC_TRANSACTION verifies successful operations finish in every pane
+// and leave no staging files behind.
+func TestSyncTransactionCommit_REQ_SYNC_TRANSACTION(t *testing.T) {
+	tmp := []string{t.TempDir(), t.TempDir(), t.TempDir()}
+	for _, dir := range tmp[:2] {
+		os.MkdirAll(filepath.Join(dir, "a", "sub"), 0755)
+		os.WriteFile(filepath.Join(dir, "a", "sub", "f"), []byte("f"), 0644)
+	}
+	ws := newTxnWorkspace(t, tmp...)
+
+	result := runSyncTransaction(ws, syncTxnCopy("a", "b"))
+	if result.Succeeded != 2 || result.Skipped != 1 || len(result.Failures) != 0 {
+		t.Fatalf("copy result = %+v", result)
+	}
+	result = runSyncTransaction(ws, syncTxnDelete("a"))
+	if result.Succeeded != 2 || len(result.CommitFailures) != 0 {
+		t.Fatalf("delete result = %+v", result)
+	}
+	result = runSyncTransaction(ws, syncTxnMkdir("x/y"))
+	if result.Succeeded != 3 {
+		t.Fatalf("mkdir result = %+v", result)
+	}
+	for i, dir := range tmp[:2] {
+		entries, _ := os.ReadDir(dir)
+		if len(entries) != 2 || entries[0].Name() != "b" || entries[1].Name() != "x" {
+			t.Errorf("pane %d = %v", i+1, entries)
+		}
+		if b, _ := os.ReadFile(filepath.Join(dir, "b", "sub", "f")); string(b) != "f" {
+			t.Errorf("pane %d b/sub/f = %q", i+1, b)
+		}
+	}
+}
+
+// TestSyncTransactionSharedSpace_REQ_SYNC_TRANSACTION verifies panes on one filesystem
+// are checked against its free space together.
+func TestSyncTransactionSharedSpace_REQ_SYNC_TRANSACTION(t *testing.T) {
+	tmp := []string{t.TempDir(), t.TempDir()}
+	ws := newTxnWorkspace(t, tmp...)
+	free, err := freeSpace(tmp[0])
+	if err != nil {
+		t.Skipf("free space unavailable: %v", err)
+	}
+	need := int64(free/2) + 1
+	applied := false
+	op := syncTxnOp{
+		check: func(*filer.Directory) (bool, int64, error) { return false, need, nil },
+		apply: func(*filer.Directory) (func() error, func() error, error) {
+			applied = true
+			return func() error { return nil }, nil, nil
+		},
+	}
+
+	if failures := checkSyncSpace(ws.Dirs, []int{0}, []int64{need}); len(failures) != 0 {
+		t.Fatalf("one pane fits on its own, got %v", failures)
+	}
+	result := runSyncTransaction(ws, op)
+	if !result.Aborted || len(result.Failures) != 2 || applied {
+		t.Fatalf("result = %+v, applied = %v", result, applied)
+	}
+	if got := result.Failures[0].Error.Error(); !strings.Contains(got, "panes 1, 2 need") {
+		t.Errorf("failure = %q", got)
+	}
+}
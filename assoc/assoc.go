@@ -0,0 +1,229 @@
+// Package assoc loads file-type associations that map file extensions, name
+// globs or MIME types to commands, menus or actions for opening files.
+// [IMPL:FILE_ASSOCIATIONS] [ARCH:FILE_ASSOCIATIONS] [REQ:FILE_ASSOCIATIONS]
+package assoc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fareedst/goful/util"
+	"gopkg.in/yaml.v3"
+)
+
+// Rule associates files matching one of Ext, Glob or MIME with one of
+// Command, Spawn, Menu or Action.
+type Rule struct {
+	Ext       string   `json:"ext" yaml:"ext"`
+	Glob      string   `json:"glob" yaml:"glob"`
+	MIME      string   `json:"mime" yaml:"mime"`
+	Command   string   `json:"command" yaml:"command"`
+	Spawn     string   `json:"spawn" yaml:"spawn"`
+	Menu      string   `json:"menu" yaml:"menu"`
+	Action    string   `json:"action" yaml:"action"`
+	Priority  int      `json:"priority" yaml:"priority"`
+	Platforms []string `json:"platforms" yaml:"platforms"`
+}
+
+// kind orders rules of equal priority: globs, then extensions, then MIME types.
+func (r Rule) kind() int {
+	switch {
+	case r.Glob != "":
+		return 0
+	case r.Ext != "":
+		return 1
+	}
+	return 2
+}
+
+// Table is an ordered list of association rules.
+type Table struct {
+	rules []Rule
+}
+
+type fileConfig struct {
+	Associations []Rule `json:"associations" yaml:"associations"`
+}
+
+// Load reads the associations file at path for goos. A missing file yields
+// an empty table.
+// [IMPL:FILE_ASSOCIATIONS] [ARCH:FILE_ASSOCIATIONS] [REQ:FILE_ASSOCIATIONS]
+func Load(path, goos string) (*Table, error) {
+	path = util.ExpandPath(strings.TrimSpace(path))
+	if path == "" {
+		return &Table{}, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &Table{}, nil
+		}
+		return &Table{}, fmt.Errorf("read associations %s: %w", path, err)
+	}
+	t, err := Parse(data, goos)
+	if err != nil {
+		return &Table{}, fmt.Errorf("parse associations %s: %w", path, err)
+	}
+	return t, nil
+}
+
+// Parse decodes YAML or JSON associations, drops rules for other platforms
+// and orders the rest by priority (highest first), then globs before
+// extensions before MIME types, then file order.
+// [IMPL:FILE_ASSOCIATIONS] [ARCH:FILE_ASSOCIATIONS] [REQ:FILE_ASSOCIATIONS]
+func Parse(data []byte, goos string) (*Table, error) {
+	var cfg fileConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return &Table{}, err
+	}
+	rules := make([]Rule, 0, len(cfg.Associations))
+	for idx, rule := range cfg.Associations {
+		rule, err := normalize(rule)
+		if err != nil {
+			return &Table{}, fmt.Errorf("association %d: %w", idx, err)
+		}
+		if !onPlatform(rule.Platforms, goos) {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].Priority != rules[j].Priority {
+			return rules[i].Priority > rules[j].Priority
+		}
+		return rules[i].kind() < rules[j].kind()
+	})
+	return &Table{rules}, nil
+}
+
+func normalize(r Rule) (Rule, error) {
+	r.Ext = strings.ToLower(strings.TrimSpace(r.Ext))
+	if r.Ext != "" && !strings.HasPrefix(r.Ext, ".") {
+		r.Ext = "." + r.Ext
+	}
+	r.Glob = strings.TrimSpace(r.Glob)
+	r.MIME = strings.ToLower(strings.TrimSpace(r.MIME))
+	r.Menu = strings.TrimSpace(r.Menu)
+	r.Action = strings.TrimSpace(r.Action)
+
+	if count(r.Ext, r.Glob, r.MIME) != 1 {
+		return r, errors.New("must set exactly one of `ext`, `glob` and `mime`")
+	}
+	if r.Glob != "" {
+		if _, err := filepath.Match(r.Glob, ""); err != nil {
+			return r, fmt.Errorf("invalid glob %q: %w", r.Glob, err)
+		}
+	}
+	if r.MIME != "" {
+		if _, err := path.Match(r.MIME, ""); err != nil {
+			return r, fmt.Errorf("invalid mime %q: %w", r.MIME, err)
+		}
+	}
+	if count(r.Command, r.Spawn, r.Menu, r.Action) != 1 {
+		return r, errors.New("must set exactly one of `command`, `spawn`, `menu` and `action`")
+	}
+	return r, nil
+}
+
+func count(values ...string) int {
+	n := 0
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			n++
+		}
+	}
+	return n
+}
+
+func onPlatform(platforms []string, goos string) bool {
+	if len(platforms) == 0 {
+		return true
+	}
+	for _, platform := range platforms {
+		if strings.EqualFold(strings.TrimSpace(platform), goos) {
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of rules.
+func (t *Table) Len() int { return len(t.rules) }
+
+// Rules returns the rules in match order.
+func (t *Table) Rules() []Rule { return append([]Rule(nil), t.rules...) }
+
+// Match returns the first rule matching the file name at fullpath. The MIME
+// type is only detected when a MIME rule is reached.
+// [IMPL:FILE_ASSOCIATIONS] [ARCH:FILE_ASSOCIATIONS] [REQ:FILE_ASSOCIATIONS]
+func (t *Table) Match(name, fullpath string) (Rule, bool) {
+	ext := filepath.Ext(name)
+	if ext == name {
+		ext = "" // dot files such as .bashrc have no extension
+	}
+	ext = strings.ToLower(ext)
+	mimeType, detected := "", false
+	for _, rule := range t.rules {
+		switch {
+		case rule.Glob != "":
+			if ok, _ := filepath.Match(rule.Glob, name); ok {
+				return rule, true
+			}
+		case rule.Ext != "":
+			if rule.Ext == ext {
+				return rule, true
+			}
+		default:
+			if !detected {
+				mimeType, detected = DetectMIME(fullpath, ext), true
+			}
+			if ok, _ := path.Match(rule.MIME, mimeType); ok && mimeType != "" {
+				return rule, true
+			}
+		}
+	}
+	return Rule{}, false
+}
+
+// sniffLen is the number of bytes http.DetectContentType considers.
+const sniffLen = 512
+
+// DetectMIME returns the media type of the file at fullpath without
+// parameters: from its extension when known, otherwise by sniffing its
+// leading bytes. Only regular files are sniffed, since reading a FIFO or a
+// device can block. It returns "" when the file cannot be read.
+// [IMPL:FILE_ASSOCIATIONS] [ARCH:FILE_ASSOCIATIONS] [REQ:FILE_ASSOCIATIONS]
+func DetectMIME(fullpath, ext string) string {
+	mimeType := ""
+	if ext != "" {
+		mimeType = mime.TypeByExtension(ext)
+	}
+	if mimeType == "" {
+		if fi, err := os.Stat(fullpath); err != nil || !fi.Mode().IsRegular() {
+			return ""
+		}
+		file, err := os.Open(fullpath)
+		if err != nil {
+			return ""
+		}
+		defer file.Close()
+		buf := make([]byte, sniffLen)
+		n, err := io.ReadFull(file, buf)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			return ""
+		}
+		mimeType = http.DetectContentType(buf[:n])
+	}
+	if media, _, err := mime.ParseMediaType(mimeType); err == nil {
+		return media
+	}
+	return mimeType
+}
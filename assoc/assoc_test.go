@@ -0,0 +1,145 @@
+package assoc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sample = `
+associations:
+  - ext: dat
+    command: "datview %f"
+  - ext: .JPG
+    menu: image
+  - glob: "report-*.dat"
+    spawn: "reporttool %f %&"
+  - mime: "image/*"
+    menu: image
+  - mime: application/pdf
+    spawn: "zathura %f %&"
+    priority: 10
+  - ext: .exe
+    command: "wine %f"
+    platforms: [linux, darwin]
+  - ext: .ps1
+    action: file.powershell
+    platforms: [windows]
+`
+
+// TestLoad_REQ_FILE_ASSOCIATIONS tests loading YAML and JSON files, missing files and invalid rules.
+// [REQ:FILE_ASSOCIATIONS] [ARCH:FILE_ASSOCIATIONS] [IMPL:FILE_ASSOCIATIONS]
+func TestLoad_REQ_FILE_ASSOCIATIONS(t *testing.T) {
+	dir := t.TempDir()
+	table, err := Load(filepath.Join(dir, "missing.yaml"), "linux")
+	if err != nil || table.Len() != 0 {
+		t.Fatalf("missing file: len=%d err=%v", table.Len(), err)
+	}
+
+	yamlPath := filepath.Join(dir, "associations.yaml")
+	os.WriteFile(yamlPath, []byte(sample), 0o644)
+	table, err = Load(yamlPath, "linux")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if table.Len() != 6 {
+		t.Errorf("linux rules = %d, want 6", table.Len())
+	}
+	if table, _ := Load(yamlPath, "windows"); table.Len() != 6 {
+		t.Errorf("windows rules = %d, want 6", table.Len())
+	}
+
+	jsonPath := filepath.Join(dir, "associations.json")
+	os.WriteFile(jsonPath, []byte(`{"associations": [{"ext": "dat", "menu": "data"}]}`), 0o644)
+	if table, err := Load(jsonPath, "linux"); err != nil || table.Len() != 1 {
+		t.Errorf("json: len=%d err=%v", table.Len(), err)
+	}
+
+	invalid := []struct {
+		yaml string
+		want string
+	}{
+		{"associations:\n  - command: x\n", "exactly one of `ext`"},
+		{"associations:\n  - ext: .a\n    glob: '*.a'\n    command: x\n", "exactly one of `ext`"},
+		{"associations:\n  - ext: .a\n", "exactly one of `command`"},
+		{"associations:\n  - ext: .a\n    command: x\n    menu: y\n", "exactly one of `command`"},
+		{"associations:\n  - glob: '[a'\n    command: x\n", "invalid glob"},
+		{"associations: {ext: .a}\n", ""},
+	}
+	for _, tt := range invalid {
+		_, err := Parse([]byte(tt.yaml), "linux")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%q) error = %v, want %q", tt.yaml, err, tt.want)
+		}
+	}
+}
+
+// TestMatch_REQ_FILE_ASSOCIATIONS tests priority and kind ordering, case-insensitive
+// extensions, dot files and MIME rules.
+// [REQ:FILE_ASSOCIATIONS] [ARCH:FILE_ASSOCIATIONS] [IMPL:FILE_ASSOCIATIONS]
+func TestMatch_REQ_FILE_ASSOCIATIONS(t *testing.T) {
+	table, err := Parse([]byte(sample), "linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"data.dat", "datview %f"},
+		{"DATA.DAT", "datview %f"},
+		{"report-2024.dat", "reporttool %f %&"},
+		{"photo.jpg", "image"},
+		{"photo.png", "image"},
+		{"book.pdf", "zathura %f %&"},
+		{"setup.exe", "wine %f"},
+		{"script.ps1", ""},
+		{".dat", ""},
+		{"notes.txt", ""},
+	}
+	for _, tt := range tests {
+		fullpath := filepath.Join(dir, tt.name)
+		os.WriteFile(fullpath, []byte("plain words"), 0o644)
+		rule, ok := table.Match(tt.name, fullpath)
+		got := rule.Command + rule.Spawn + rule.Menu + rule.Action
+		if ok != (tt.want != "") || got != tt.want {
+			t.Errorf("Match(%q) = %q, %v; want %q", tt.name, got, ok, tt.want)
+		}
+	}
+
+	if rules := table.Rules(); rules[0].MIME != "application/pdf" || rules[1].Glob == "" {
+		t.Errorf("rule order = %+v, want the priority rule then the glob", rules[:2])
+	}
+}
+
+// TestDetectMIME_REQ_FILE_ASSOCIATIONS tests sniffing the type of extension-less files.
+// [REQ:FILE_ASSOCIATIONS] [ARCH:FILE_ASSOCIATIONS] [IMPL:FILE_ASSOCIATIONS]
+func TestDetectMIME_REQ_FILE_ASSOCIATIONS(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		data string
+		want string
+	}{
+		{"\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR", "image/png"},
+		{"%PDF-1.7\n", "application/pdf"},
+		{"PK\x03\x04\x14\x00", "application/zip"},
+		{"just some text\n", "text/plain"},
+	}
+	for i, tt := range tests {
+		fullpath := filepath.Join(dir, "file"+string(rune('a'+i)))
+		os.WriteFile(fullpath, []byte(tt.data), 0o644)
+		if got := DetectMIME(fullpath, ""); got != tt.want {
+			t.Errorf("DetectMIME(%q) = %q, want %q", tt.data, got, tt.want)
+		}
+	}
+	if got := DetectMIME(filepath.Join(dir, "missing"), ""); got != "" {
+		t.Errorf("missing file = %q, want empty", got)
+	}
+
+	table, _ := Parse([]byte("associations:\n  - mime: image/png\n    menu: image\n"), "linux")
+	if rule, ok := table.Match("filea", filepath.Join(dir, "filea")); !ok || rule.Menu != "image" {
+		t.Errorf("extension-less png should match the mime rule, got %+v %v", rule, ok)
+	}
+}
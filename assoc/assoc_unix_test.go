@@ -0,0 +1,35 @@
+//go:build !windows
+// +build !windows
+
+package assoc
+
+import (
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestDetectMIMESkipsFIFO_REQ_FILE_ASSOCIATIONS tests that a named pipe is not
+// read, which would block until a writer appears.
+// [REQ:FILE_ASSOCIATIONS] [ARCH:FILE_ASSOCIATIONS] [IMPL:FILE_ASSOCIATIONS]
+func TestDetectMIMESkipsFIFO_REQ_FILE_ASSOCIATIONS(t *testing.T) {
+	fifo := filepath.Join(t.TempDir(), "pipe")
+	if err := syscall.Mkfifo(fifo, 0o644); err != nil {
+		t.Skipf("mkfifo: %v", err)
+	}
+	table, _ := Parse([]byte("associations:\n  - mime: text/*\n    menu: editor\n"), "linux")
+	done := make(chan bool, 1)
+	go func() {
+		_, ok := table.Match("pipe", fifo)
+		done <- ok
+	}()
+	select {
+	case ok := <-done:
+		if ok {
+			t.Error("a named pipe should not match a MIME rule")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Match blocked on a named pipe")
+	}
+}
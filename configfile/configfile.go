@@ -0,0 +1,250 @@
+// Package configfile loads the unified config file, a single config.yaml
+// that can hold the paths, theme, terminal options, keymaps, commands,
+// excludes, compare colors and associations otherwise kept in separate
+// files.
+// [IMPL:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [REQ:UNIFIED_CONFIG]
+package configfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fareedst/goful/assoc"
+	"github.com/fareedst/goful/configpaths"
+	"github.com/fareedst/goful/externalcmd"
+	"github.com/fareedst/goful/filer/comparecolors"
+	"github.com/fareedst/goful/keymapcfg"
+	"github.com/fareedst/goful/util"
+	"gopkg.in/yaml.v3"
+)
+
+// Sections that can be given inline instead of as a separate file.
+const (
+	Keymaps      = "keymaps"
+	Commands     = "commands"
+	Excludes     = "excludes"
+	Colors       = "colors"
+	Associations = "associations"
+)
+
+// Border styles for Terminal.Border.
+const (
+	BorderUnicode = "unicode"
+	BorderASCII   = "ascii"
+)
+
+// Themes accepted by look.Set.
+var Themes = []string{"default", "midnight", "black", "white"}
+
+// Terminal holds the terminal options.
+// [IMPL:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [REQ:UNIFIED_CONFIG]
+type Terminal struct {
+	Title          *bool  `yaml:"title"`          // set the window title to goful (default true)
+	Border         string `yaml:"border"`         // BorderUnicode, BorderASCII or "" to follow the locale
+	MessageSeconds int    `yaml:"messageSeconds"` // how long messages are shown (default 5)
+}
+
+// SetsTitle reports whether the window title should be set.
+func (t Terminal) SetsTitle() bool { return t.Title == nil || *t.Title }
+
+// File is a loaded config file. Sections that are absent keep their zero
+// value; Has tells them apart from empty ones.
+// [IMPL:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [REQ:UNIFIED_CONFIG]
+type File struct {
+	Path     string
+	Paths    configpaths.Paths // per-file locations; empty fields are unset
+	Theme    string
+	Terminal Terminal
+
+	Keymaps      keymapcfg.Config
+	Commands     externalcmd.Config
+	Excludes     []string
+	Colors       *comparecolors.Config
+	Associations *assoc.Table
+
+	sections map[string]bool
+}
+
+// Has reports whether the inline section was given.
+func (f *File) Has(section string) bool { return f != nil && f.sections[section] }
+
+type pathsConfig struct {
+	State         string `yaml:"state"`
+	History       string `yaml:"history"`
+	Commands      string `yaml:"commands"`
+	Excludes      string `yaml:"excludes"`
+	CompareColors string `yaml:"compareColors"`
+	Keymaps       string `yaml:"keymaps"`
+	Associations  string `yaml:"associations"`
+	Log           string `yaml:"log"`
+}
+
+type fileConfig struct {
+	Paths        pathsConfig `yaml:"paths"`
+	Theme        string      `yaml:"theme"`
+	Terminal     Terminal    `yaml:"terminal"`
+	Keymaps      yaml.Node   `yaml:"keymaps"`
+	Commands     yaml.Node   `yaml:"commands"`
+	Excludes     yaml.Node   `yaml:"excludes"`
+	Colors       yaml.Node   `yaml:"colors"`
+	Associations yaml.Node   `yaml:"associations"`
+}
+
+// Load reads the config file at path for goos. A missing file yields an
+// empty config; on errors the returned config is empty too, so callers can
+// keep going with the per-file settings.
+// [IMPL:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [REQ:UNIFIED_CONFIG]
+func Load(path, goos string) (*File, error) {
+	path = util.ExpandPath(strings.TrimSpace(path))
+	empty := &File{Path: path}
+	if path == "" {
+		return empty, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return empty, nil
+		}
+		return empty, fmt.Errorf("read config %s: %w", path, err)
+	}
+	f, err := Parse(data, path, goos)
+	if err != nil {
+		return empty, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Parse decodes a YAML config file. Relative paths are taken relative to
+// the directory of path. Unknown keys are errors, and so is a section given
+// both inline and as a path.
+// [IMPL:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [REQ:UNIFIED_CONFIG]
+func Parse(data []byte, path, goos string) (*File, error) {
+	var cfg fileConfig
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && err != io.EOF {
+		return nil, err
+	}
+	f := &File{
+		Path:     path,
+		Theme:    strings.TrimSpace(cfg.Theme),
+		Terminal: cfg.Terminal,
+		sections: map[string]bool{},
+	}
+	if err := f.validate(); err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(path)
+	p := cfg.Paths
+	f.Paths = configpaths.Paths{
+		State:         relativeTo(dir, p.State),
+		History:       relativeTo(dir, p.History),
+		Commands:      relativeTo(dir, p.Commands),
+		Excludes:      relativeTo(dir, p.Excludes),
+		CompareColors: relativeTo(dir, p.CompareColors),
+		Keymaps:       relativeTo(dir, p.Keymaps),
+		Associations:  relativeTo(dir, p.Associations),
+		Log:           relativeTo(dir, p.Log),
+	}
+
+	sections := []struct {
+		name  string
+		node  *yaml.Node
+		path  string
+		parse func(data []byte) error
+	}{
+		{Keymaps, &cfg.Keymaps, p.Keymaps, func(data []byte) (err error) {
+			f.Keymaps, err = keymapcfg.Parse(data)
+			return err
+		}},
+		{Commands, &cfg.Commands, p.Commands, func(data []byte) (err error) {
+			f.Commands, err = externalcmd.LoadConfig(externalcmd.Options{
+				Path:     path,
+				GOOS:     goos,
+				ReadFile: func(string) ([]byte, error) { return data, nil },
+			})
+			return err
+		}},
+		{Excludes, &cfg.Excludes, p.Excludes, func([]byte) error {
+			var names []string
+			if err := cfg.Excludes.Decode(&names); err != nil {
+				return err
+			}
+			for _, name := range names {
+				if name = strings.TrimSpace(name); name != "" {
+					f.Excludes = append(f.Excludes, name)
+				}
+			}
+			return nil
+		}},
+		{Colors, &cfg.Colors, p.CompareColors, func(data []byte) (err error) {
+			f.Colors, err = comparecolors.Parse(data)
+			return err
+		}},
+		{Associations, &cfg.Associations, p.Associations, func(data []byte) (err error) {
+			// The section holds the rules of an associations file.
+			wrapped := map[string]*yaml.Node{Associations: &cfg.Associations}
+			if data, err = yaml.Marshal(wrapped); err != nil {
+				return err
+			}
+			f.Associations, err = assoc.Parse(data, goos)
+			return err
+		}},
+	}
+	for _, s := range sections {
+		if s.node.Kind == 0 {
+			continue
+		}
+		if s.path != "" {
+			return nil, fmt.Errorf("%s is given both inline and in paths", s.name)
+		}
+		data, err := yaml.Marshal(s.node)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", s.name, err)
+		}
+		if err := s.parse(data); err != nil {
+			return nil, fmt.Errorf("%s: %w", s.name, err)
+		}
+		f.sections[s.name] = true
+	}
+	return f, nil
+}
+
+func (f *File) validate() error {
+	if f.Theme != "" && !contains(Themes, f.Theme) {
+		return fmt.Errorf("unknown theme %q (want one of %s)", f.Theme, strings.Join(Themes, ", "))
+	}
+	switch f.Terminal.Border {
+	case "", BorderUnicode, BorderASCII:
+	default:
+		return fmt.Errorf("unknown terminal.border %q (want %s or %s)", f.Terminal.Border, BorderUnicode, BorderASCII)
+	}
+	if f.Terminal.MessageSeconds < 0 {
+		return fmt.Errorf("terminal.messageSeconds must not be negative")
+	}
+	return nil
+}
+
+// relativeTo resolves a relative path against dir, leaving empty paths and
+// paths starting with ~ alone.
+func relativeTo(dir, path string) string {
+	path = strings.TrimSpace(path)
+	if path == "" || strings.HasPrefix(path, "~") || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
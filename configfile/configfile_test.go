@@ -0,0 +1,103 @@
+package configfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseSections_REQ_UNIFIED_CONFIG(t *testing.T) {
+	// [REQ:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [IMPL:UNIFIED_CONFIG]
+	data := []byte(`
+paths:
+  state: state/state.json
+  history: ~/history
+  keymaps: /etc/goful/keymaps.yaml
+  log: logs
+theme: midnight
+terminal:
+  title: false
+  border: ascii
+  messageSeconds: 3
+commands:
+  - {menu: tools, key: t, label: top, command: top}
+excludes: [".DS_Store", " ", "Thumbs.db"]
+colors:
+  name: {present: magenta}
+associations:
+  - {ext: .gfd, command: "gfdview %f"}
+`)
+	f, err := Parse(data, "/home/u/.config/goful/config.yaml", "linux")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if want := filepath.Join("/home/u/.config/goful", "state", "state.json"); f.Paths.State != want {
+		t.Errorf("relative state path = %q, want %q", f.Paths.State, want)
+	}
+	if want := filepath.Join("/home/u/.config/goful", "logs"); f.Paths.Log != want {
+		t.Errorf("relative log path = %q, want %q", f.Paths.Log, want)
+	}
+	if f.Paths.History != "~/history" || f.Paths.Keymaps != "/etc/goful/keymaps.yaml" {
+		t.Errorf("paths = %+v", f.Paths)
+	}
+	if f.Theme != "midnight" || f.Terminal.SetsTitle() || f.Terminal.Border != BorderASCII || f.Terminal.MessageSeconds != 3 {
+		t.Errorf("theme/terminal = %q %+v", f.Theme, f.Terminal)
+	}
+	// Like a commands file, the list form keeps the default commands after its own.
+	if !f.Has(Commands) || len(f.Commands.Entries) < 2 || f.Commands.Entries[0].Menu != "tools" {
+		t.Errorf("commands = %+v", f.Commands)
+	}
+	if !f.Has(Excludes) || strings.Join(f.Excludes, "|") != ".DS_Store|Thumbs.db" {
+		t.Errorf("excludes = %q", f.Excludes)
+	}
+	if !f.Has(Colors) || f.Colors.Name.Present != "magenta" || f.Colors.Size.Equal != "cyan" {
+		t.Errorf("colors should overlay the defaults, got %+v", f.Colors)
+	}
+	if !f.Has(Associations) || f.Associations.Len() != 1 {
+		t.Errorf("associations = %v", f.Associations)
+	}
+	if f.Has(Keymaps) {
+		t.Error("keymaps section was not given")
+	}
+}
+
+func TestParseErrors_REQ_UNIFIED_CONFIG(t *testing.T) {
+	// [REQ:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [IMPL:UNIFIED_CONFIG]
+	cases := map[string]string{
+		"unknown key":        "colours: {}\n",
+		"unknown theme":      "theme: neon\n",
+		"unknown border":     "terminal: {border: double}\n",
+		"negative seconds":   "terminal: {messageSeconds: -1}\n",
+		"inline and path":    "paths: {keymaps: k.yaml}\nkeymaps: {filer: {M-x: app.help}}\n",
+		"bad keymap context": "keymaps: {nowhere: {M-x: app.help}}\n",
+		"bad command":        "commands: [{key: z}]\n",
+	}
+	for name, data := range cases {
+		if _, err := Parse([]byte(data), "/c/config.yaml", "linux"); err == nil {
+			t.Errorf("%s: Parse(%q) should fail", name, data)
+		}
+	}
+}
+
+func TestLoad_REQ_UNIFIED_CONFIG(t *testing.T) {
+	// [REQ:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [IMPL:UNIFIED_CONFIG]
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	f, err := Load(path, "linux")
+	if err != nil || f.Path != path || f.Has(Keymaps) {
+		t.Fatalf("missing file should load empty, got %+v, %v", f, err)
+	}
+	if err := os.WriteFile(path, []byte("# comments only\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path, "linux"); err != nil {
+		t.Fatalf("empty file: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("theme: [\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path, "linux"); err == nil || !strings.Contains(err.Error(), path) {
+		t.Fatalf("broken file error should name the path, got %v", err)
+	}
+}
@@ -2,15 +2,22 @@ package configpaths
 
 import (
 	"os"
+	"path/filepath"
 
 	"github.com/fareedst/goful/util"
 )
 
 const (
-	// DefaultStatePath is the legacy location for the persisted UI state.
+	// DefaultStatePath is the legacy location for the persisted UI state. It
+	// is used while it exists; otherwise state goes under $XDG_STATE_HOME.
 	DefaultStatePath = "~/.goful/state.json"
-	// DefaultHistoryPath is the legacy location for cmdline history.
+	// DefaultHistoryPath is the legacy location for cmdline history. It is
+	// used while it exists; otherwise history goes under $XDG_STATE_HOME.
 	DefaultHistoryPath = "~/.goful/history/shell"
+	// DefaultConfigPath is the legacy location for the unified config file,
+	// used when $XDG_CONFIG_HOME/goful/config.yaml does not exist.
+	// [IMPL:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [REQ:UNIFIED_CONFIG]
+	DefaultConfigPath = "~/.goful/config.yaml"
 	// DefaultCommandsPath is the default location for external command configs.
 	DefaultCommandsPath = "~/.goful/external_commands.yaml"
 	// DefaultExcludesPath is the default list of filenames to hide.
@@ -18,6 +25,16 @@ const (
 	// DefaultCompareColorsPath is the default location for comparison color config.
 	// [IMPL:COMPARE_COLOR_CONFIG] [ARCH:FILE_COMPARISON_ENGINE] [REQ:FILE_COMPARISON_COLORS]
 	DefaultCompareColorsPath = "~/.goful/compare_colors.yaml"
+	// DefaultKeymapsPath is the default location for user key bindings.
+	// [IMPL:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [REQ:USER_KEYMAPS]
+	DefaultKeymapsPath = "~/.goful/keymaps.yaml"
+	// DefaultAssociationsPath is the default location for file-type associations.
+	// [IMPL:FILE_ASSOCIATIONS] [ARCH:FILE_ASSOCIATIONS] [REQ:FILE_ASSOCIATIONS]
+	DefaultAssociationsPath = "~/.goful/associations.yaml"
+	// DefaultLogPath is the legacy directory for info.log and error.log. It
+	// is used while it exists; otherwise logs go under $XDG_STATE_HOME.
+	// [IMPL:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [REQ:UNIFIED_CONFIG]
+	DefaultLogPath = "~/.goful/log"
 
 	// EnvStateKey configures the state path when flags are not provided.
 	EnvStateKey = "GOFUL_STATE_PATH"
@@ -30,12 +47,35 @@ const (
 	// EnvCompareColorsKey configures the comparison color config path.
 	// [IMPL:COMPARE_COLOR_CONFIG] [ARCH:FILE_COMPARISON_ENGINE] [REQ:FILE_COMPARISON_COLORS]
 	EnvCompareColorsKey = "GOFUL_COMPARE_COLORS"
+	// EnvKeymapsKey configures the user key bindings path.
+	// [IMPL:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [REQ:USER_KEYMAPS]
+	EnvKeymapsKey = "GOFUL_KEYMAPS_FILE"
+	// EnvAssociationsKey configures the file-type associations path.
+	// [IMPL:FILE_ASSOCIATIONS] [ARCH:FILE_ASSOCIATIONS] [REQ:FILE_ASSOCIATIONS]
+	EnvAssociationsKey = "GOFUL_ASSOCIATIONS_FILE"
+	// EnvConfigKey configures the unified config file path.
+	// [IMPL:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [REQ:UNIFIED_CONFIG]
+	EnvConfigKey = "GOFUL_CONFIG_FILE"
+	// EnvXDGConfigHome and EnvXDGStateHome are the XDG base directories.
+	// [IMPL:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [REQ:UNIFIED_CONFIG]
+	EnvXDGConfigHome = "XDG_CONFIG_HOME"
+	EnvXDGStateHome  = "XDG_STATE_HOME"
+
+	// DefaultSource is the provenance of paths that nothing configured.
+	DefaultSource = defaultSourceLabel
+	// ConfigSource is the provenance of paths set in the unified config file.
+	// [IMPL:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [REQ:UNIFIED_CONFIG]
+	ConfigSource = "config"
 
 	flagStateSourceLabel         = "flag:-state"
 	flagHistorySourceLabel       = "flag:-history"
 	flagCommandsSourceLabel      = "flag:-commands"
 	flagExcludesSourceLabel      = "flag:-exclude-names"
 	flagCompareColorsSourceLabel = "flag:-compare-colors"
+	flagKeymapsSourceLabel       = "flag:-keymaps"
+	flagAssociationsSourceLabel  = "flag:-associations"
+	flagConfigSourceLabel        = "flag:-config"
+	xdgSourceLabel               = "xdg"
 	defaultSourceLabel           = "default"
 )
 
@@ -46,28 +86,71 @@ type Paths struct {
 	Commands            string
 	Excludes            string
 	CompareColors       string
+	Keymaps             string
+	Associations        string
 	StateSource         string
 	HistorySource       string
 	CommandsSource      string
 	ExcludesSource      string
 	CompareColorsSource string
+	KeymapsSource       string
+	AssociationsSource  string
+
+	// Log is the directory of info.log and error.log, set from the config
+	// file or defaulted under the state directory.
+	// [IMPL:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [REQ:UNIFIED_CONFIG]
+	Log       string
+	LogSource string
+
+	// Config is the unified config file, set from Resolver.ConfigFile.
+	// [IMPL:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [REQ:UNIFIED_CONFIG]
+	Config       string
+	ConfigSource string
+}
+
+// Flags holds the path flag values from the command line; empty fields were
+// not given.
+// [IMPL:STATE_PATH_RESOLVER] [ARCH:STATE_PATH_SELECTION] [REQ:CONFIGURABLE_STATE_PATHS]
+type Flags struct {
+	State         string
+	History       string
+	Commands      string
+	Excludes      string
+	CompareColors string
+	Keymaps       string
+	Associations  string
 }
 
 // Resolver enforces the [REQ:CONFIGURABLE_STATE_PATHS] precedence contract:
-// CLI flags override environment variables, which override defaults.
+// CLI flags override environment variables, which override the unified
+// config file, which overrides defaults.
 // [IMPL:STATE_PATH_RESOLVER] [ARCH:STATE_PATH_SELECTION] [REQ:CONFIGURABLE_STATE_PATHS]
 type Resolver struct {
 	LookupEnv func(string) (string, bool)
+
+	// Configured holds the paths set in the unified config file; empty
+	// fields are not configured.
+	// [IMPL:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [REQ:UNIFIED_CONFIG]
+	Configured Paths
+	// Exists reports whether a file exists; nil uses os.Stat.
+	Exists func(string) bool
 }
 
-// Resolve returns the final state/history/commands/excludes/compareColors paths plus provenance metadata.
-// [IMPL:STATE_PATH_RESOLVER] [ARCH:STATE_PATH_SELECTION] [REQ:CONFIGURABLE_STATE_PATHS] [REQ:EXTERNAL_COMMAND_CONFIG] [REQ:FILER_EXCLUDE_NAMES] [REQ:FILE_COMPARISON_COLORS]
-func (r Resolver) Resolve(flagState, flagHistory, flagCommands, flagExcludes, flagCompareColors string) Paths {
-	state, stateSource := r.resolveOne(flagState, EnvStateKey, DefaultStatePath, flagStateSourceLabel)
-	history, historySource := r.resolveOne(flagHistory, EnvHistoryKey, DefaultHistoryPath, flagHistorySourceLabel)
-	commands, commandsSource := r.resolveOne(flagCommands, EnvCommandsKey, DefaultCommandsPath, flagCommandsSourceLabel)
-	excludes, excludesSource := r.resolveOne(flagExcludes, EnvExcludesKey, DefaultExcludesPath, flagExcludesSourceLabel)
-	compareColors, compareColorsSource := r.resolveOne(flagCompareColors, EnvCompareColorsKey, DefaultCompareColorsPath, flagCompareColorsSourceLabel)
+// Resolve returns the final state/history/commands/excludes/compareColors/keymaps/associations paths plus provenance metadata.
+// [IMPL:STATE_PATH_RESOLVER] [ARCH:STATE_PATH_SELECTION] [REQ:CONFIGURABLE_STATE_PATHS] [REQ:EXTERNAL_COMMAND_CONFIG] [REQ:FILER_EXCLUDE_NAMES] [REQ:FILE_COMPARISON_COLORS] [REQ:USER_KEYMAPS] [REQ:FILE_ASSOCIATIONS]
+func (r Resolver) Resolve(f Flags) Paths {
+	c := r.Configured
+	state, stateSource := r.resolveOne(f.State, EnvStateKey, c.State, r.stateDefault(DefaultStatePath, "state.json"), flagStateSourceLabel)
+	history, historySource := r.resolveOne(f.History, EnvHistoryKey, c.History, r.stateDefault(DefaultHistoryPath, filepath.Join("history", "shell")), flagHistorySourceLabel)
+	commands, commandsSource := r.resolveOne(f.Commands, EnvCommandsKey, c.Commands, DefaultCommandsPath, flagCommandsSourceLabel)
+	excludes, excludesSource := r.resolveOne(f.Excludes, EnvExcludesKey, c.Excludes, DefaultExcludesPath, flagExcludesSourceLabel)
+	compareColors, compareColorsSource := r.resolveOne(f.CompareColors, EnvCompareColorsKey, c.CompareColors, DefaultCompareColorsPath, flagCompareColorsSourceLabel)
+	keymaps, keymapsSource := r.resolveOne(f.Keymaps, EnvKeymapsKey, c.Keymaps, DefaultKeymapsPath, flagKeymapsSourceLabel)
+	associations, associationsSource := r.resolveOne(f.Associations, EnvAssociationsKey, c.Associations, DefaultAssociationsPath, flagAssociationsSourceLabel)
+	log, logSource := util.ExpandPath(c.Log), ConfigSource
+	if c.Log == "" {
+		log, logSource = util.ExpandPath(r.stateDefault(DefaultLogPath, "log")), defaultSourceLabel
+	}
 
 	return Paths{
 		State:               state,
@@ -75,21 +158,71 @@ func (r Resolver) Resolve(flagState, flagHistory, flagCommands, flagExcludes, fl
 		Commands:            commands,
 		Excludes:            excludes,
 		CompareColors:       compareColors,
+		Keymaps:             keymaps,
+		Associations:        associations,
 		StateSource:         stateSource,
 		HistorySource:       historySource,
 		CommandsSource:      commandsSource,
 		ExcludesSource:      excludesSource,
 		CompareColorsSource: compareColorsSource,
+		KeymapsSource:       keymapsSource,
+		AssociationsSource:  associationsSource,
+		Log:                 log,
+		LogSource:           logSource,
+	}
+}
+
+// ConfigFile returns the unified config file path plus its provenance: the
+// -config flag, then GOFUL_CONFIG_FILE, then the first existing of
+// $XDG_CONFIG_HOME/goful/config.yaml and ~/.goful/config.yaml. When neither
+// exists the XDG location is returned.
+// [IMPL:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [REQ:UNIFIED_CONFIG]
+func (r Resolver) ConfigFile(flagConfig string) (string, string) {
+	if flagConfig != "" {
+		return util.ExpandPath(flagConfig), flagConfigSourceLabel
+	}
+	if envValue, ok := r.lookupEnv(EnvConfigKey); ok && envValue != "" {
+		return util.ExpandPath(envValue), "env:" + EnvConfigKey
 	}
+	xdg := filepath.Join(r.baseDir(EnvXDGConfigHome, "~/.config"), "goful", "config.yaml")
+	if r.exists(xdg) {
+		return xdg, xdgSourceLabel
+	}
+	if legacy := util.ExpandPath(DefaultConfigPath); r.exists(legacy) {
+		return legacy, defaultSourceLabel
+	}
+	return xdg, xdgSourceLabel
+}
+
+// stateDefault returns legacy while it exists so that upgrades keep their
+// state, and otherwise name under $XDG_STATE_HOME/goful.
+// [IMPL:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [REQ:UNIFIED_CONFIG]
+func (r Resolver) stateDefault(legacy, name string) string {
+	if r.exists(util.ExpandPath(legacy)) {
+		return legacy
+	}
+	return filepath.Join(r.baseDir(EnvXDGStateHome, "~/.local/state"), "goful", name)
+}
+
+// baseDir returns the XDG base directory named by envKey. Relative values
+// are ignored as the specification requires.
+func (r Resolver) baseDir(envKey, fallback string) string {
+	if dir, ok := r.lookupEnv(envKey); ok && filepath.IsAbs(dir) {
+		return dir
+	}
+	return util.ExpandPath(fallback)
 }
 
-func (r Resolver) resolveOne(flagValue, envKey, defaultValue, flagLabel string) (string, string) {
+func (r Resolver) resolveOne(flagValue, envKey, configValue, defaultValue, flagLabel string) (string, string) {
 	if flagValue != "" {
 		return util.ExpandPath(flagValue), flagLabel
 	}
 	if envValue, ok := r.lookupEnv(envKey); ok && envValue != "" {
 		return util.ExpandPath(envValue), "env:" + envKey
 	}
+	if configValue != "" {
+		return util.ExpandPath(configValue), ConfigSource
+	}
 	return util.ExpandPath(defaultValue), defaultSourceLabel
 }
 
@@ -99,3 +232,11 @@ func (r Resolver) lookupEnv(key string) (string, bool) {
 	}
 	return os.LookupEnv(key)
 }
+
+func (r Resolver) exists(path string) bool {
+	if r.Exists != nil {
+		return r.Exists(path)
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
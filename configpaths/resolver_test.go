@@ -1,6 +1,7 @@
 package configpaths
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/fareedst/goful/util"
@@ -22,10 +23,20 @@ func TestResolvePathsPrefersFlags_REQ_CONFIGURABLE_STATE_PATHS(t *testing.T) {
 			EnvCommandsKey:      "/env/commands.json",
 			EnvExcludesKey:      "/env/excludes.txt",
 			EnvCompareColorsKey: "/env/compare_colors.yaml",
+			EnvKeymapsKey:       "/env/keymaps.yaml",
+			EnvAssociationsKey:  "/env/associations.yaml",
 		}),
 	}
 
-	paths := resolver.Resolve("/flag/state.json", "/flag/history", "/flag/commands.json", "/flag/excludes.txt", "/flag/compare_colors.yaml")
+	paths := resolver.Resolve(Flags{
+		State:         "/flag/state.json",
+		History:       "/flag/history",
+		Commands:      "/flag/commands.json",
+		Excludes:      "/flag/excludes.txt",
+		CompareColors: "/flag/compare_colors.yaml",
+		Keymaps:       "/flag/keymaps.yaml",
+		Associations:  "/flag/associations.yaml",
+	})
 	if paths.State != "/flag/state.json" || paths.StateSource != flagStateSourceLabel {
 		t.Fatalf("flags must override env/default, got state=%q src=%q", paths.State, paths.StateSource)
 	}
@@ -41,6 +52,14 @@ func TestResolvePathsPrefersFlags_REQ_CONFIGURABLE_STATE_PATHS(t *testing.T) {
 	if paths.CompareColors != "/flag/compare_colors.yaml" || paths.CompareColorsSource != flagCompareColorsSourceLabel {
 		t.Fatalf("flags must override env/default for compare-colors, got %q (%q)", paths.CompareColors, paths.CompareColorsSource)
 	}
+	// [REQ:USER_KEYMAPS] [IMPL:USER_KEYMAPS]
+	if paths.Keymaps != "/flag/keymaps.yaml" || paths.KeymapsSource != flagKeymapsSourceLabel {
+		t.Fatalf("flags must override env/default for keymaps, got %q (%q)", paths.Keymaps, paths.KeymapsSource)
+	}
+	// [REQ:FILE_ASSOCIATIONS] [IMPL:FILE_ASSOCIATIONS]
+	if paths.Associations != "/flag/associations.yaml" || paths.AssociationsSource != flagAssociationsSourceLabel {
+		t.Fatalf("flags must override env/default for associations, got %q (%q)", paths.Associations, paths.AssociationsSource)
+	}
 }
 
 func TestResolvePathsFallsBackToEnv_REQ_CONFIGURABLE_STATE_PATHS(t *testing.T) {
@@ -57,10 +76,12 @@ func TestResolvePathsFallsBackToEnv_REQ_CONFIGURABLE_STATE_PATHS(t *testing.T) {
 			EnvCommandsKey:      commandsEnv,
 			EnvExcludesKey:      excludesEnv,
 			EnvCompareColorsKey: compareColorsEnv,
+			EnvKeymapsKey:       "/env/keymaps.yaml",
+			EnvAssociationsKey:  "/env/associations.yaml",
 		}),
 	}
 
-	paths := resolver.Resolve("", "", "", "", "")
+	paths := resolver.Resolve(Flags{})
 	if paths.State != stateEnv || paths.StateSource != "env:"+EnvStateKey {
 		t.Fatalf("env should supply state path, got %q (%q)", paths.State, paths.StateSource)
 	}
@@ -76,12 +97,21 @@ func TestResolvePathsFallsBackToEnv_REQ_CONFIGURABLE_STATE_PATHS(t *testing.T) {
 	if paths.CompareColors != compareColorsEnv || paths.CompareColorsSource != "env:"+EnvCompareColorsKey {
 		t.Fatalf("env should supply compare-colors path, got %q (%q)", paths.CompareColors, paths.CompareColorsSource)
 	}
+	// [REQ:USER_KEYMAPS] [IMPL:USER_KEYMAPS]
+	if paths.Keymaps != "/env/keymaps.yaml" || paths.KeymapsSource != "env:"+EnvKeymapsKey {
+		t.Fatalf("env should supply keymaps path, got %q (%q)", paths.Keymaps, paths.KeymapsSource)
+	}
+	// [REQ:FILE_ASSOCIATIONS] [IMPL:FILE_ASSOCIATIONS]
+	if paths.Associations != "/env/associations.yaml" || paths.AssociationsSource != "env:"+EnvAssociationsKey {
+		t.Fatalf("env should supply associations path, got %q (%q)", paths.Associations, paths.AssociationsSource)
+	}
 }
 
 func TestResolvePathsDefaults_REQ_CONFIGURABLE_STATE_PATHS(t *testing.T) {
 	// [REQ:CONFIGURABLE_STATE_PATHS] [REQ:EXTERNAL_COMMAND_CONFIG] [ARCH:STATE_PATH_SELECTION] [IMPL:STATE_PATH_RESOLVER] [REQ:FILE_COMPARISON_COLORS]
-	resolver := Resolver{}
-	paths := resolver.Resolve("", "", "", "", "")
+	// [REQ:UNIFIED_CONFIG] Existing legacy state and history files keep being used.
+	resolver := Resolver{Exists: func(string) bool { return true }}
+	paths := resolver.Resolve(Flags{})
 
 	wantState := util.ExpandPath(DefaultStatePath)
 	wantHistory := util.ExpandPath(DefaultHistoryPath)
@@ -103,6 +133,18 @@ func TestResolvePathsDefaults_REQ_CONFIGURABLE_STATE_PATHS(t *testing.T) {
 	if paths.CompareColors != wantCompareColors || paths.CompareColorsSource != defaultSourceLabel {
 		t.Fatalf("default compare-colors mismatch: got %q (%q), want %q", paths.CompareColors, paths.CompareColorsSource, wantCompareColors)
 	}
+	// [REQ:USER_KEYMAPS] [IMPL:USER_KEYMAPS]
+	if want := util.ExpandPath(DefaultKeymapsPath); paths.Keymaps != want || paths.KeymapsSource != defaultSourceLabel {
+		t.Fatalf("default keymaps mismatch: got %q (%q), want %q", paths.Keymaps, paths.KeymapsSource, want)
+	}
+	// [REQ:FILE_ASSOCIATIONS] [IMPL:FILE_ASSOCIATIONS]
+	if want := util.ExpandPath(DefaultAssociationsPath); paths.Associations != want || paths.AssociationsSource != defaultSourceLabel {
+		t.Fatalf("default associations mismatch: got %q (%q), want %q", paths.Associations, paths.AssociationsSource, want)
+	}
+	// [REQ:UNIFIED_CONFIG] [IMPL:UNIFIED_CONFIG]
+	if want := util.ExpandPath(DefaultLogPath); paths.Log != want || paths.LogSource != defaultSourceLabel {
+		t.Fatalf("default log mismatch: got %q (%q), want %q", paths.Log, paths.LogSource, want)
+	}
 }
 
 func TestResolvePathsIgnoresEmptyEnv_REQ_CONFIGURABLE_STATE_PATHS(t *testing.T) {
@@ -116,8 +158,84 @@ func TestResolvePathsIgnoresEmptyEnv_REQ_CONFIGURABLE_STATE_PATHS(t *testing.T)
 			EnvCompareColorsKey: "",
 		}),
 	}
-	paths := resolver.Resolve("", "", "", "", "")
+	paths := resolver.Resolve(Flags{})
 	if paths.StateSource != defaultSourceLabel || paths.HistorySource != defaultSourceLabel || paths.CommandsSource != defaultSourceLabel || paths.ExcludesSource != defaultSourceLabel || paths.CompareColorsSource != defaultSourceLabel {
 		t.Fatalf("empty env values should fall back to defaults, got stateSrc=%q historySrc=%q commandsSrc=%q excludesSrc=%q compareColorsSrc=%q", paths.StateSource, paths.HistorySource, paths.CommandsSource, paths.ExcludesSource, paths.CompareColorsSource)
 	}
 }
+
+func TestResolvePathsXDGState_REQ_UNIFIED_CONFIG(t *testing.T) {
+	// [REQ:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [IMPL:UNIFIED_CONFIG]
+	resolver := Resolver{
+		LookupEnv: stubLookup(map[string]string{EnvXDGStateHome: "/xdg/state"}),
+		Exists:    func(string) bool { return false },
+	}
+	paths := resolver.Resolve(Flags{})
+	if want := filepath.Join("/xdg/state", "goful", "state.json"); paths.State != want || paths.StateSource != defaultSourceLabel {
+		t.Fatalf("state should default under XDG_STATE_HOME, got %q (%q), want %q", paths.State, paths.StateSource, want)
+	}
+	if want := filepath.Join("/xdg/state", "goful", "history", "shell"); paths.History != want {
+		t.Fatalf("history should default under XDG_STATE_HOME, got %q, want %q", paths.History, want)
+	}
+	if want := filepath.Join("/xdg/state", "goful", "log"); paths.Log != want {
+		t.Fatalf("log should default under XDG_STATE_HOME, got %q, want %q", paths.Log, want)
+	}
+
+	resolver.LookupEnv = stubLookup(map[string]string{EnvXDGStateHome: "relative/state"})
+	paths = resolver.Resolve(Flags{})
+	if want := filepath.Join(util.ExpandPath("~/.local/state"), "goful", "state.json"); paths.State != want {
+		t.Fatalf("relative XDG_STATE_HOME should be ignored, got %q, want %q", paths.State, want)
+	}
+}
+
+func TestResolvePathsConfigured_REQ_UNIFIED_CONFIG(t *testing.T) {
+	// [REQ:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [IMPL:UNIFIED_CONFIG]
+	resolver := Resolver{
+		LookupEnv: stubLookup(map[string]string{EnvHistoryKey: "/env/history"}),
+		Configured: Paths{
+			State:    "/config/state.json",
+			History:  "/config/history",
+			Commands: "/config/commands.yaml",
+			Log:      "/config/log",
+		},
+	}
+	paths := resolver.Resolve(Flags{Commands: "/flag/commands.yaml"})
+	if paths.State != "/config/state.json" || paths.StateSource != ConfigSource {
+		t.Fatalf("config file should override defaults, got %q (%q)", paths.State, paths.StateSource)
+	}
+	if paths.History != "/env/history" || paths.HistorySource != "env:"+EnvHistoryKey {
+		t.Fatalf("env should override the config file, got %q (%q)", paths.History, paths.HistorySource)
+	}
+	if paths.Commands != "/flag/commands.yaml" || paths.CommandsSource != flagCommandsSourceLabel {
+		t.Fatalf("flags should override the config file, got %q (%q)", paths.Commands, paths.CommandsSource)
+	}
+	if paths.Log != "/config/log" || paths.LogSource != ConfigSource {
+		t.Fatalf("config file should set the log directory, got %q (%q)", paths.Log, paths.LogSource)
+	}
+}
+
+func TestConfigFileLookup_REQ_UNIFIED_CONFIG(t *testing.T) {
+	// [REQ:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [IMPL:UNIFIED_CONFIG]
+	xdg := filepath.Join("/xdg/config", "goful", "config.yaml")
+	legacy := util.ExpandPath(DefaultConfigPath)
+	existing := map[string]bool{}
+	resolver := Resolver{
+		LookupEnv: stubLookup(map[string]string{EnvXDGConfigHome: "/xdg/config"}),
+		Exists:    func(path string) bool { return existing[path] },
+	}
+
+	if path, src := resolver.ConfigFile(""); path != xdg || src != xdgSourceLabel {
+		t.Fatalf("missing config should report the XDG location, got %q (%q)", path, src)
+	}
+	existing[legacy] = true
+	if path, src := resolver.ConfigFile(""); path != legacy || src != defaultSourceLabel {
+		t.Fatalf("legacy config should be found, got %q (%q)", path, src)
+	}
+	existing[xdg] = true
+	if path, _ := resolver.ConfigFile(""); path != xdg {
+		t.Fatalf("XDG config should win over the legacy one, got %q", path)
+	}
+	if path, src := resolver.ConfigFile("/flag/config.yaml"); path != "/flag/config.yaml" || src != flagConfigSourceLabel {
+		t.Fatalf("flag should override the lookup, got %q (%q)", path, src)
+	}
+}
@@ -0,0 +1,151 @@
+// Package diffresults provides a popup panel listing every difference found
+// by a complete difference search.
+// [IMPL:DIFF_RESULTS_PANEL] [ARCH:DIFF_RESULTS_PANEL] [REQ:DIFF_RESULTS_PANEL]
+package diffresults
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fareedst/goful/filer"
+	"github.com/fareedst/goful/look"
+	"github.com/fareedst/goful/widget"
+	"github.com/mattn/go-runewidth"
+)
+
+// Panel is a popup list box showing one row per difference.
+// [IMPL:DIFF_RESULTS_PANEL] [ARCH:DIFF_RESULTS_PANEL] [REQ:DIFF_RESULTS_PANEL]
+type Panel struct {
+	*widget.ListBox
+	filer    widget.Widget
+	report   *filer.DiffReport
+	onSelect func(filer.DiffEntry)
+}
+
+// New creates a results panel for the report based on filer widget sizes.
+// onSelect is called with the chosen entry after the panel closes.
+// [IMPL:DIFF_RESULTS_PANEL] [ARCH:DIFF_RESULTS_PANEL] [REQ:DIFF_RESULTS_PANEL]
+func New(filer widget.Widget, report *filer.DiffReport, onSelect func(filer.DiffEntry)) *Panel {
+	x, y, width, height := widget.PopupGeometry(len(report.Differences))
+	title := fmt.Sprintf("Differences (%d files checked)", report.TotalFilesChecked)
+	p := &Panel{
+		ListBox:  widget.NewListBox(x, y, width, height, title),
+		filer:    filer,
+		report:   report,
+		onSelect: onSelect,
+	}
+	for _, e := range report.Differences {
+		p.AppendList(&entry{e})
+	}
+	p.SetBorderStyle(widget.AllBorder)
+	return p
+}
+
+// Resize keeps the panel centered on the screen.
+// [IMPL:DIFF_RESULTS_PANEL] [ARCH:DIFF_RESULTS_PANEL] [REQ:DIFF_RESULTS_PANEL]
+func (p *Panel) Resize(x, y, width, height int) {
+	p.ListBox.Resize(widget.PopupGeometry(p.Upper()))
+}
+
+// Input handles keyboard input for the results panel.
+// [IMPL:DIFF_RESULTS_PANEL] [ARCH:DIFF_RESULTS_PANEL] [REQ:DIFF_RESULTS_PANEL]
+func (p *Panel) Input(key string) {
+	switch key {
+	case "q", "Q", "C-g", "C-[":
+		p.Exit()
+	case "C-m", "o":
+		p.Select()
+	case "C-n", "down", "j":
+		p.MoveCursor(1)
+	case "C-p", "up", "k":
+		p.MoveCursor(-1)
+	case "C-v", "pgdn":
+		p.PageDown()
+	case "M-v", "pgup":
+		p.PageUp()
+	case "C-a", "home", "^":
+		p.MoveTop()
+	case "C-e", "end", "$":
+		p.MoveBottom()
+	case "M-n":
+		p.Scroll(1)
+	case "M-p":
+		p.Scroll(-1)
+	}
+}
+
+// Select closes the panel and navigates to the entry under the cursor.
+// [IMPL:DIFF_RESULTS_PANEL] [ARCH:DIFF_RESULTS_PANEL] [REQ:DIFF_RESULTS_PANEL]
+func (p *Panel) Select() {
+	e, ok := p.CurrentContent().(*entry)
+	p.Exit()
+	if ok && p.onSelect != nil {
+		p.onSelect(e.DiffEntry)
+	}
+}
+
+// Exit closes the panel and returns to the filer.
+func (p *Panel) Exit() {
+	p.filer.Disconnect()
+}
+
+// Next implements widget.Widget.
+func (p *Panel) Next() widget.Widget {
+	return widget.Nil()
+}
+
+// Disconnect implements widget.Widget.
+func (p *Panel) Disconnect() {}
+
+// entry draws one difference as path, reason and the windows holding it.
+// [IMPL:DIFF_RESULTS_PANEL] [ARCH:DIFF_RESULTS_PANEL] [REQ:DIFF_RESULTS_PANEL]
+type entry struct {
+	filer.DiffEntry
+}
+
+// Name returns the entry path for ListBox compatibility.
+func (e *entry) Name() string { return e.Path }
+
+// Draw renders the entry; directories use the directory style.
+func (e *entry) Draw(x, y, width int, focus bool) {
+	style := look.Default()
+	if e.IsDir {
+		style = look.Directory()
+	}
+	if focus {
+		style = style.Reverse(true)
+	}
+	s := runewidth.Truncate(FormatEntry(e.DiffEntry, width), width, "~")
+	s = runewidth.FillRight(s, width)
+	widget.SetCells(x, y, s, style)
+}
+
+// FormatEntry lays out a difference in a single row of the given width:
+// the path on the left, then the reason and the windows holding the entry.
+// [IMPL:DIFF_RESULTS_PANEL] [ARCH:DIFF_RESULTS_PANEL] [REQ:DIFF_RESULTS_PANEL]
+func FormatEntry(e filer.DiffEntry, width int) string {
+	path := e.Path
+	if e.IsDir && !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+	detail := fmt.Sprintf("  %s  [%s]", e.Reason, formatPanes(e.Panes))
+	pathWidth := width - runewidth.StringWidth(detail)
+	if pathWidth < width/2 {
+		pathWidth = width / 2
+	}
+	path = widget.TruncLeft(path, pathWidth, "~")
+	return runewidth.FillRight(path, pathWidth) + detail
+}
+
+// formatPanes renders window numbers as "1,3", or "-" when none hold the entry.
+func formatPanes(panes []int) string {
+	if len(panes) == 0 {
+		return "-"
+	}
+	s := make([]string, len(panes))
+	for i, n := range panes {
+		s[i] = strconv.Itoa(n)
+	}
+	return strings.Join(s, ",")
+}
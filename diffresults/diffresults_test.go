@@ -0,0 +1,40 @@
+package diffresults
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fareedst/goful/filer"
+)
+
+// TestFormatEntry_REQ_DIFF_RESULTS_PANEL verifies rows show path, reason and windows.
+// [IMPL:DIFF_RESULTS_PANEL] [ARCH:DIFF_RESULTS_PANEL] [REQ:DIFF_RESULTS_PANEL]
+func TestFormatEntry_REQ_DIFF_RESULTS_PANEL(t *testing.T) {
+	e := filer.DiffEntry{Name: "b/", Path: "a/b", Reason: "missing in window 2", IsDir: true, Panes: []int{1, 3}}
+	got := FormatEntry(e, 60)
+	if !strings.HasPrefix(got, "a/b/ ") {
+		t.Errorf("expected directory path with trailing slash first, got %q", got)
+	}
+	if !strings.HasSuffix(got, "missing in window 2  [1,3]") {
+		t.Errorf("expected reason and windows at the end, got %q", got)
+	}
+
+	long := filer.DiffEntry{Path: strings.Repeat("x", 100) + "/tail.txt", Reason: "size mismatch", Panes: []int{1, 2}}
+	got = FormatEntry(long, 60)
+	if !strings.Contains(got, "tail.txt") || !strings.HasPrefix(got, "~") {
+		t.Errorf("expected long paths truncated from the left, got %q", got)
+	}
+	if !strings.HasSuffix(got, "[1,2]") {
+		t.Errorf("expected windows to stay visible, got %q", got)
+	}
+}
+
+// TestFormatPanes_REQ_DIFF_RESULTS_PANEL verifies the empty placeholder.
+func TestFormatPanes_REQ_DIFF_RESULTS_PANEL(t *testing.T) {
+	if got := formatPanes(nil); got != "-" {
+		t.Errorf("formatPanes(nil) = %q, want -", got)
+	}
+	if got := formatPanes([]int{2}); got != "2" {
+		t.Errorf("formatPanes([2]) = %q, want 2", got)
+	}
+}
@@ -0,0 +1,130 @@
+package externalcmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Show values for entries whose condition does not apply.
+// [IMPL:COMMAND_CONDITIONS] [ARCH:COMMAND_CONDITIONS] [REQ:COMMAND_CONDITIONS]
+const (
+	ShowHide    = "hide"    // leave the entry out of the menu (default)
+	ShowDisable = "disable" // show the entry dimmed and do not run it
+)
+
+// Condition limits an entry to the selections it makes sense for. It is
+// evaluated each time the menu opens; every set field must hold.
+// [IMPL:COMMAND_CONDITIONS] [ARCH:COMMAND_CONDITIONS] [REQ:COMMAND_CONDITIONS]
+type Condition struct {
+	Ext        []string `json:"ext" yaml:"ext"`               // extensions of the selected files
+	Glob       []string `json:"glob" yaml:"glob"`             // name patterns of the selected files
+	Marked     bool     `json:"marked" yaml:"marked"`         // requires marked files
+	Dir        bool     `json:"dir" yaml:"dir"`               // requires a directory under the cursor
+	Executable []string `json:"executable" yaml:"executable"` // commands required on $PATH
+	Panes      int      `json:"panes" yaml:"panes"`           // exact number of panes
+	MinPanes   int      `json:"minPanes" yaml:"minPanes"`     // minimum number of panes
+	Show       string   `json:"show" yaml:"show"`             // ShowHide or ShowDisable
+}
+
+// Selection is the state of the focused pane that conditions are evaluated
+// against.
+// [IMPL:COMMAND_CONDITIONS] [ARCH:COMMAND_CONDITIONS] [REQ:COMMAND_CONDITIONS]
+type Selection struct {
+	Files  []string // names of the marked files, or of the file under the cursor
+	Dir    bool     // the file under the cursor is a directory
+	Marked int      // number of marked files
+	Panes  int      // number of panes in the workspace
+}
+
+// IsZero reports whether the condition sets no requirement.
+func (c Condition) IsZero() bool {
+	return len(c.Ext) == 0 && len(c.Glob) == 0 && !c.Marked && !c.Dir &&
+		len(c.Executable) == 0 && c.Panes == 0 && c.MinPanes == 0
+}
+
+// Applies reports whether the condition holds for sel. hasCommand reports
+// whether an executable is on $PATH. Extensions and globs must match every
+// selected file; one of them matching is enough.
+// [IMPL:COMMAND_CONDITIONS] [ARCH:COMMAND_CONDITIONS] [REQ:COMMAND_CONDITIONS]
+func (c Condition) Applies(sel Selection, hasCommand func(name string) bool) bool {
+	switch {
+	case c.Marked && sel.Marked == 0:
+		return false
+	case c.Dir && !sel.Dir:
+		return false
+	case c.Panes > 0 && sel.Panes != c.Panes:
+		return false
+	case c.MinPanes > 0 && sel.Panes < c.MinPanes:
+		return false
+	}
+	if len(c.Ext) > 0 || len(c.Glob) > 0 {
+		if len(sel.Files) == 0 {
+			return false
+		}
+		for _, name := range sel.Files {
+			if !c.matchName(name) {
+				return false
+			}
+		}
+	}
+	for _, name := range c.Executable {
+		if hasCommand == nil || !hasCommand(name) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c Condition) matchName(name string) bool {
+	ext := filepath.Ext(name)
+	if ext == name {
+		ext = "" // dot files such as .bashrc have no extension
+	}
+	ext = strings.ToLower(ext)
+	for _, want := range c.Ext {
+		if ext != "" && want == ext {
+			return true
+		}
+	}
+	for _, glob := range c.Glob {
+		if ok, _ := filepath.Match(glob, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeCondition lowercases extensions with a leading dot and validates
+// globs, pane counts and show.
+// [IMPL:COMMAND_CONDITIONS] [ARCH:COMMAND_CONDITIONS] [REQ:COMMAND_CONDITIONS]
+func normalizeCondition(c Condition) (Condition, error) {
+	exts := trimNames(c.Ext)
+	for i, ext := range exts {
+		ext = strings.ToLower(ext)
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		exts[i] = ext
+	}
+	c.Ext = exts
+	c.Glob = trimNames(c.Glob)
+	for _, glob := range c.Glob {
+		if _, err := filepath.Match(glob, ""); err != nil {
+			return c, fmt.Errorf("invalid `when` glob %q: %w", glob, err)
+		}
+	}
+	c.Executable = trimNames(c.Executable)
+	if c.Panes < 0 || c.MinPanes < 0 {
+		return c, fmt.Errorf("`when` pane counts must not be negative")
+	}
+	c.Show = strings.ToLower(strings.TrimSpace(c.Show))
+	switch c.Show {
+	case "":
+		c.Show = ShowHide
+	case ShowHide, ShowDisable:
+	default:
+		return c, fmt.Errorf("unknown `when.show` %q (want %s or %s)", c.Show, ShowHide, ShowDisable)
+	}
+	return c, nil
+}
@@ -0,0 +1,86 @@
+package externalcmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadParsesConditions_REQ_COMMAND_CONDITIONS(t *testing.T) {
+	// [REQ:COMMAND_CONDITIONS] [ARCH:COMMAND_CONDITIONS] [IMPL:COMMAND_CONDITIONS]
+	load := func(raw string) ([]Entry, error) {
+		return Load(Options{
+			Path: "/conditions.yaml",
+			GOOS: "linux",
+			ReadFile: func(string) ([]byte, error) {
+				return []byte(raw), nil
+			},
+		})
+	}
+	entries, err := load(`
+inheritDefaults: false
+commands:
+  - key: x
+    label: "extract"
+    command: "tar xf %f"
+    when:
+      ext: [TAR, " .tgz "]
+      glob: ["*.tar.*"]
+      executable: [tar]
+      show: Disable
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	when := entries[0].When
+	if strings.Join(when.Ext, ",") != ".tar,.tgz" || when.Show != ShowDisable || when.Executable[0] != "tar" {
+		t.Fatalf("unexpected condition: %+v", when)
+	}
+
+	for raw, want := range map[string]string{
+		`[{"key":"x","label":"x","command":"ls","when":{"glob":["[a"]}}]`: "invalid `when` glob",
+		`[{"key":"x","label":"x","command":"ls","when":{"panes":-1}}]`:    "must not be negative",
+		`[{"key":"x","label":"x","command":"ls","when":{"show":"grey"}}]`: "unknown `when.show`",
+	} {
+		if _, err := load(raw); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: error = %v, want %q", raw, err, want)
+		}
+	}
+}
+
+func TestConditionApplies_REQ_COMMAND_CONDITIONS(t *testing.T) {
+	// [REQ:COMMAND_CONDITIONS] [ARCH:COMMAND_CONDITIONS] [IMPL:COMMAND_CONDITIONS]
+	onPath := func(name string) bool { return name == "tar" }
+	archive := Selection{Files: []string{"a.TAR"}, Panes: 2}
+	tests := []struct {
+		name string
+		when Condition
+		sel  Selection
+		want bool
+	}{
+		{"empty", Condition{}, Selection{}, true},
+		{"ext", Condition{Ext: []string{".tar"}}, archive, true},
+		{"ext mismatch", Condition{Ext: []string{".zip"}}, archive, false},
+		{"glob", Condition{Glob: []string{"*.tar.*"}}, Selection{Files: []string{"a.tar.gz"}}, true},
+		{"every marked file", Condition{Ext: []string{".tar"}}, Selection{Files: []string{"a.tar", "b.txt"}, Marked: 2}, false},
+		{"dot file", Condition{Ext: []string{".bashrc"}}, Selection{Files: []string{".bashrc"}}, false},
+		{"no files", Condition{Glob: []string{"*"}}, Selection{}, false},
+		{"marked", Condition{Marked: true}, archive, false},
+		{"marked present", Condition{Marked: true}, Selection{Marked: 1}, true},
+		{"dir", Condition{Dir: true}, archive, false},
+		{"dir present", Condition{Dir: true}, Selection{Dir: true}, true},
+		{"executable", Condition{Executable: []string{"tar"}}, archive, true},
+		{"missing executable", Condition{Executable: []string{"tar", "7z"}}, archive, false},
+		{"panes", Condition{Panes: 2}, archive, true},
+		{"panes mismatch", Condition{Panes: 3}, archive, false},
+		{"min panes", Condition{MinPanes: 3}, archive, false},
+	}
+	for _, tt := range tests {
+		tt.when, _ = normalizeCondition(tt.when)
+		if got := tt.when.Applies(tt.sel, onPath); got != tt.want {
+			t.Errorf("%s: Applies = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if !(Condition{Show: ShowDisable}).IsZero() {
+		t.Error("show alone should not make a condition")
+	}
+}
@@ -26,22 +26,33 @@ type Options struct {
 type fileConfig struct {
 	entries         []Entry
 	inheritDefaults bool
+	replaceMenus    []string
 }
 
 type configWrapper struct {
-	Commands        []Entry `json:"commands" yaml:"commands"`
-	InheritDefaults *bool   `json:"inheritDefaults" yaml:"inheritDefaults"`
+	Commands        []Entry  `json:"commands" yaml:"commands"`
+	InheritDefaults *bool    `json:"inheritDefaults" yaml:"inheritDefaults"`
+	ReplaceMenus    []string `json:"replaceMenus" yaml:"replaceMenus"`
 }
 
-// Load resolves and parses the external command configuration file.
+// Load resolves and parses the external command configuration file and
+// returns its entries.
 // Falls back to baked-in defaults if the file is missing or invalid.
 // [IMPL:EXTERNAL_COMMAND_LOADER] [ARCH:EXTERNAL_COMMAND_REGISTRY] [REQ:EXTERNAL_COMMAND_CONFIG]
 func Load(opts Options) ([]Entry, error) {
+	cfg, err := LoadConfig(opts)
+	return cfg.Entries, err
+}
+
+// LoadConfig resolves and parses the external command configuration file,
+// including the menus whose built-in items it replaces.
+// [IMPL:CONFIG_MENUS] [ARCH:CONFIG_MENUS] [REQ:CONFIG_MENUS]
+func LoadConfig(opts Options) (Config, error) {
 	defaults := Defaults(opts.GOOS)
 	resolvedPath := util.ExpandPath(strings.TrimSpace(opts.Path))
 	if resolvedPath == "" {
 		debugf(&opts, "using defaults for external commands; no path provided")
-		return defaults, nil
+		return Config{Entries: defaults}, nil
 	}
 
 	readFn := opts.ReadFile
@@ -53,30 +64,30 @@ func Load(opts Options) ([]Entry, error) {
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			debugf(&opts, "config %s not found; falling back to defaults", resolvedPath)
-			return defaults, nil
+			return Config{Entries: defaults}, nil
 		}
-		return defaults, fmt.Errorf("read external commands %s: %w", resolvedPath, err)
+		return Config{Entries: defaults}, fmt.Errorf("read external commands %s: %w", resolvedPath, err)
 	}
 
 	cfg, err := parseConfig(data)
 	if err != nil {
-		return defaults, fmt.Errorf("parse external commands %s: %w", resolvedPath, err)
+		return Config{Entries: defaults}, fmt.Errorf("parse external commands %s: %w", resolvedPath, err)
 	}
 
 	sanitized, err := sanitizeEntries(cfg.entries, opts.GOOS, &opts, resolvedPath)
 	if err != nil {
-		return defaults, err
+		return Config{Entries: defaults}, err
 	}
 
 	// [IMPL:EXTERNAL_COMMAND_APPEND] preserve compiled defaults unless configs opt out.
 	if cfg.inheritDefaults {
 		merged := mergeWithDefaults(defaults, sanitized)
 		debugf(&opts, "[IMPL:EXTERNAL_COMMAND_APPEND] inheritDefaults=true; prepended %d command(s) ahead of %d default(s) from %s", len(sanitized), len(defaults), resolvedPath)
-		return merged, nil
+		return Config{Entries: merged, ReplaceMenus: trimNames(cfg.replaceMenus)}, nil
 	}
 
 	debugf(&opts, "[IMPL:EXTERNAL_COMMAND_APPEND] inheritDefaults=false; replacing %d default(s) with %d command(s) from %s", len(defaults), len(sanitized), resolvedPath)
-	return sanitized, nil
+	return Config{Entries: sanitized, ReplaceMenus: trimNames(cfg.replaceMenus)}, nil
 }
 
 func parseConfig(data []byte) (fileConfig, error) {
@@ -100,6 +111,7 @@ func parseJSONConfig(data []byte) (fileConfig, error) {
 		return fileConfig{
 			entries:         wrapper.Commands,
 			inheritDefaults: inheritDefaultsOrTrue(wrapper.InheritDefaults),
+			replaceMenus:    wrapper.ReplaceMenus,
 		}, nil
 	}
 	return fileConfig{}, errors.New("json decode failed")
@@ -116,11 +128,22 @@ func parseYAMLConfig(data []byte) (fileConfig, error) {
 		return fileConfig{
 			entries:         wrapper.Commands,
 			inheritDefaults: inheritDefaultsOrTrue(wrapper.InheritDefaults),
+			replaceMenus:    wrapper.ReplaceMenus,
 		}, nil
 	}
 	return fileConfig{}, errors.New("yaml decode failed")
 }
 
+func trimNames(names []string) []string {
+	trimmed := make([]string, 0, len(names))
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			trimmed = append(trimmed, name)
+		}
+	}
+	return trimmed
+}
+
 func inheritDefaultsOrTrue(flag *bool) bool {
 	if flag == nil {
 		return true
@@ -159,13 +182,52 @@ func sanitizeEntries(entries []Entry, goos string, opts *Options, path string) (
 
 		commandTrimmed := strings.TrimSpace(entry.Command)
 		runMenuTrimmed := strings.TrimSpace(entry.RunMenu)
-		if commandTrimmed == "" && runMenuTrimmed == "" {
-			return nil, fmt.Errorf("entry %q must provide `command` or `runMenu`", entry.Key)
+		actionTrimmed := strings.TrimSpace(entry.Action)
+		spawnTrimmed := strings.TrimSpace(entry.Spawn)
+		// [IMPL:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [REQ:ACTION_REGISTRY]
+		// [IMPL:CONFIG_MENUS] [ARCH:CONFIG_MENUS] [REQ:CONFIG_MENUS]
+		targets := 0
+		for _, target := range []string{commandTrimmed, runMenuTrimmed, actionTrimmed, spawnTrimmed} {
+			if target != "" {
+				targets++
+			}
+		}
+		if targets == 0 {
+			return nil, fmt.Errorf("entry %q must provide `command`, `spawn`, `runMenu` or `action`", entry.Key)
 		}
-		if commandTrimmed != "" && runMenuTrimmed != "" {
-			return nil, fmt.Errorf("entry %q cannot set both `command` and `runMenu`", entry.Key)
+		if targets > 1 {
+			return nil, fmt.Errorf("entry %q must set only one of `command`, `spawn`, `runMenu` and `action`", entry.Key)
 		}
 		entry.RunMenu = runMenuTrimmed
+		entry.Action = actionTrimmed
+
+		// [IMPL:COMMAND_PARAMS] [ARCH:COMMAND_PARAMS] [REQ:COMMAND_PARAMS]
+		if len(entry.Params) > 0 && commandTrimmed == "" && spawnTrimmed == "" {
+			return nil, fmt.Errorf("entry %q declares `params` without `command` or `spawn`", entry.Key)
+		}
+		params, err := normalizeParams(entry.Params, entry.Command+" "+entry.Spawn)
+		if err != nil {
+			return nil, fmt.Errorf("entry %q: %w", entry.Key, err)
+		}
+		entry.Params = params
+
+		// [IMPL:COMMAND_OUTPUT_CAPTURE] [ARCH:COMMAND_OUTPUT_CAPTURE] [REQ:COMMAND_OUTPUT_CAPTURE]
+		entry.Output = strings.ToLower(strings.TrimSpace(entry.Output))
+		switch {
+		case entry.Output != "" && entry.Output != OutputCapture:
+			return nil, fmt.Errorf("entry %q has unknown `output` %q (want %s)", entry.Key, entry.Output, OutputCapture)
+		case entry.Output == OutputCapture && commandTrimmed == "" && spawnTrimmed == "":
+			return nil, fmt.Errorf("entry %q captures output without `command` or `spawn`", entry.Key)
+		case entry.Reload && entry.Output != OutputCapture:
+			return nil, fmt.Errorf("entry %q sets `reload` without `output: %s`", entry.Key, OutputCapture)
+		}
+
+		// [IMPL:COMMAND_CONDITIONS] [ARCH:COMMAND_CONDITIONS] [REQ:COMMAND_CONDITIONS]
+		when, err := normalizeCondition(entry.When)
+		if err != nil {
+			return nil, fmt.Errorf("entry %q: %w", entry.Key, err)
+		}
+		entry.When = when
 
 		if len(entry.Platforms) > 0 {
 			match := false
@@ -218,3 +218,104 @@ func TestLoadCanDisableDefaults_REQ_EXTERNAL_COMMAND_CONFIG(t *testing.T) {
 		}
 	}
 }
+
+func TestLoadParsesActionEntries_REQ_ACTION_REGISTRY(t *testing.T) {
+	// [REQ:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [IMPL:ACTION_REGISTRY]
+	const raw = `[{"key":"c","label":"copy","action":" file.copy "}]`
+	entries, err := Load(Options{
+		Path: "/ignore.json",
+		GOOS: "linux",
+		ReadFile: func(string) ([]byte, error) {
+			return []byte(raw), nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entries[0].Action != "file.copy" {
+		t.Fatalf("expected trimmed action, got %+v", entries[0])
+	}
+
+	const both = `[{"key":"c","label":"copy","action":"file.copy","command":"cp %m %D"}]`
+	_, err = Load(Options{
+		Path: "/ignore.json",
+		GOOS: "linux",
+		ReadFile: func(string) ([]byte, error) {
+			return []byte(both), nil
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), "only one of") {
+		t.Fatalf("expected conflicting target error, got %v", err)
+	}
+}
+
+func TestLoadConfigParsesMenus_REQ_CONFIG_MENUS(t *testing.T) {
+	// [REQ:CONFIG_MENUS] [ARCH:CONFIG_MENUS] [IMPL:CONFIG_MENUS]
+	const raw = `
+replaceMenus: [" archive ", ""]
+commands:
+  - menu: archive
+    key: z
+    label: "zstd"
+    spawn: "tar --zstd -cf %x.tar.zst %m"
+  - menu: sort
+    key: n
+    label: "sort natural"
+    action: sort.natural
+  - menu: editor
+    key: h
+    label: "helix"
+    spawn: "hx %f"
+    platforms: [windows]
+`
+	cfg, err := LoadConfig(Options{
+		Path: "/menus.yaml",
+		GOOS: "linux",
+		ReadFile: func(string) ([]byte, error) {
+			return []byte(raw), nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.ReplaceMenus) != 1 || cfg.ReplaceMenus[0] != "archive" {
+		t.Fatalf("expected trimmed replaceMenus, got %q", cfg.ReplaceMenus)
+	}
+	if cfg.Entries[0].Spawn == "" || cfg.Entries[1].Action != "sort.natural" {
+		t.Fatalf("unexpected leading entries: %+v", cfg.Entries[:2])
+	}
+	for _, entry := range cfg.Entries {
+		if entry.Menu == "editor" {
+			t.Fatalf("windows-only entry should be filtered on linux: %+v", entry)
+		}
+	}
+}
+
+func TestLoadValidatesOutputCapture_REQ_COMMAND_OUTPUT_CAPTURE(t *testing.T) {
+	// [REQ:COMMAND_OUTPUT_CAPTURE] [ARCH:COMMAND_OUTPUT_CAPTURE] [IMPL:COMMAND_OUTPUT_CAPTURE]
+	load := func(raw string) ([]Entry, error) {
+		return Load(Options{
+			Path: "/capture.json",
+			GOOS: "linux",
+			ReadFile: func(string) ([]byte, error) {
+				return []byte(raw), nil
+			},
+		})
+	}
+	entries, err := load(`[{"key":"l","label":"log","command":"git log -5 %f","output":" Capture ","reload":true}]`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entries[0].Output != OutputCapture || !entries[0].Reload {
+		t.Fatalf("unexpected capture entry: %+v", entries[0])
+	}
+	for raw, want := range map[string]string{
+		`[{"key":"l","label":"log","command":"ls","output":"pager"}]`:     "unknown `output`",
+		`[{"key":"l","label":"log","runMenu":"sort","output":"capture"}]`: "without `command` or `spawn`",
+		`[{"key":"l","label":"log","command":"ls","reload":true}]`:        "`reload` without",
+	} {
+		if _, err := load(raw); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: error = %v, want %q", raw, err, want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package externalcmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/fareedst/goful/util"
+)
+
+var paramName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// normalizeParams trims and validates the params of an entry and checks that
+// every %{name} in command is declared.
+// [IMPL:COMMAND_PARAMS] [ARCH:COMMAND_PARAMS] [REQ:COMMAND_PARAMS]
+func normalizeParams(params []Param, command string) ([]Param, error) {
+	seen := make(map[string]bool, len(params))
+	var normalized []Param
+	for idx, param := range params {
+		param.Name = strings.TrimSpace(param.Name)
+		if !paramName.MatchString(param.Name) {
+			return nil, fmt.Errorf("param %d has invalid `name` %q", idx, param.Name)
+		}
+		if seen[param.Name] {
+			return nil, fmt.Errorf("duplicate param %q", param.Name)
+		}
+		seen[param.Name] = true
+
+		param.Type = strings.ToLower(strings.TrimSpace(param.Type))
+		switch param.Type {
+		case "":
+			param.Type = ParamText
+		case ParamText, ParamConfirm, ParamFile:
+		case ParamChoice:
+			param.Choices = trimNames(param.Choices)
+			if len(param.Choices) == 0 {
+				return nil, fmt.Errorf("param %q of type choice needs `choices`", param.Name)
+			}
+			if param.Default != "" && !param.IsChoice(param.Default) {
+				return nil, fmt.Errorf("param %q default %q is not one of its choices", param.Name, param.Default)
+			}
+		default:
+			return nil, fmt.Errorf("param %q has unknown type %q (want text, choice, confirm or file)", param.Name, param.Type)
+		}
+		normalized = append(normalized, param)
+	}
+	for _, name := range ParamRefs(command) {
+		if !seen[name] {
+			return nil, fmt.Errorf("command refers to undeclared param %q", name)
+		}
+	}
+	return normalized, nil
+}
+
+// IsChoice reports whether value is one of the choices of the param.
+func (p Param) IsChoice(value string) bool {
+	for _, choice := range p.Choices {
+		if choice == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ParamRefs returns the names referenced as %{name} or %~{name} in cmd.
+// [IMPL:COMMAND_PARAMS] [ARCH:COMMAND_PARAMS] [REQ:COMMAND_PARAMS]
+func ParamRefs(cmd string) []string {
+	var names []string
+	scanParams(cmd, func(name string, _ bool) (string, bool) {
+		names = append(names, name)
+		return "", false
+	})
+	return names
+}
+
+// ExpandParams substitutes values for %{name} (quoted) and %~{name} (raw)
+// in cmd. Percent signs and backslashes in values are escaped so the
+// file macros (%f, %D@, ...) expanded later leave them alone. References
+// without a value are kept as written.
+// [IMPL:COMMAND_PARAMS] [ARCH:COMMAND_PARAMS] [REQ:COMMAND_PARAMS]
+func ExpandParams(cmd string, values map[string]string) string {
+	return scanParams(cmd, func(name string, raw bool) (string, bool) {
+		value, ok := values[name]
+		if !ok {
+			return "", false
+		}
+		if !raw {
+			value = util.Quote(value)
+		}
+		return escapeMacro(value), true
+	})
+}
+
+// scanParams calls expand for each parameter reference in cmd, skipping
+// escaped characters, and replaces the reference when expand reports ok.
+func scanParams(cmd string, expand func(name string, raw bool) (string, bool)) string {
+	var b strings.Builder
+	for i := 0; i < len(cmd); i++ {
+		switch cmd[i] {
+		case '\\':
+			b.WriteByte(cmd[i])
+			if i+1 < len(cmd) {
+				i++
+				b.WriteByte(cmd[i])
+			}
+			continue
+		case '%':
+			start := i + 1
+			raw := strings.HasPrefix(cmd[start:], "~")
+			if raw {
+				start++
+			}
+			if strings.HasPrefix(cmd[start:], "{") {
+				if end := strings.IndexByte(cmd[start:], '}'); end > 0 {
+					name := cmd[start+1 : start+end]
+					if paramName.MatchString(name) {
+						if value, ok := expand(name, raw); ok {
+							b.WriteString(value)
+						} else {
+							b.WriteString(cmd[i : start+end+1])
+						}
+						i = start + end
+						continue
+					}
+				}
+			}
+		}
+		b.WriteByte(cmd[i])
+	}
+	return b.String()
+}
+
+func escapeMacro(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`).Replace(s)
+}
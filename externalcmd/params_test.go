@@ -0,0 +1,92 @@
+package externalcmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadParsesParams_REQ_COMMAND_PARAMS(t *testing.T) {
+	// [REQ:COMMAND_PARAMS] [ARCH:COMMAND_PARAMS] [IMPL:COMMAND_PARAMS]
+	const raw = `
+inheritDefaults: false
+commands:
+  - key: d
+    label: "deploy"
+    command: "deploy --env %{env} %~{tag} %f"
+    params:
+      - name: env
+        type: Choice
+        choices: [dev, " staging ", prod]
+        default: staging
+      - name: tag
+        prompt: "Release tag"
+      - name: sure
+        type: confirm
+`
+	entries, err := Load(Options{
+		Path: "/params.yaml",
+		GOOS: "linux",
+		ReadFile: func(string) ([]byte, error) {
+			return []byte(raw), nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	params := entries[0].Params
+	if len(params) != 3 || params[0].Type != ParamChoice || params[0].Choices[1] != "staging" || params[1].Type != ParamText {
+		t.Fatalf("unexpected params: %+v", params)
+	}
+}
+
+func TestLoadRejectsInvalidParams_REQ_COMMAND_PARAMS(t *testing.T) {
+	// [REQ:COMMAND_PARAMS] [ARCH:COMMAND_PARAMS] [IMPL:COMMAND_PARAMS]
+	tests := []struct {
+		entry string
+		want  string
+	}{
+		{`{"key":"a","label":"a","command":"echo %{x}"}`, `undeclared param "x"`},
+		{`{"key":"a","label":"a","command":"echo","params":[{"name":"a b"}]}`, "invalid `name`"},
+		{`{"key":"a","label":"a","command":"echo","params":[{"name":"x"},{"name":"x"}]}`, `duplicate param "x"`},
+		{`{"key":"a","label":"a","command":"echo","params":[{"name":"x","type":"date"}]}`, `unknown type "date"`},
+		{`{"key":"a","label":"a","command":"echo","params":[{"name":"x","type":"choice"}]}`, "needs `choices`"},
+		{`{"key":"a","label":"a","command":"echo","params":[{"name":"x","type":"choice","choices":["a"],"default":"b"}]}`, "not one of its choices"},
+		{`{"key":"a","label":"a","runMenu":"sort","params":[{"name":"x"}]}`, "without `command` or `spawn`"},
+	}
+	for _, tt := range tests {
+		_, err := Load(Options{
+			Path: "/params.json",
+			GOOS: "linux",
+			ReadFile: func(string) ([]byte, error) {
+				return []byte("[" + tt.entry + "]"), nil
+			},
+		})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want %q", tt.entry, err, tt.want)
+		}
+	}
+}
+
+func TestExpandParams_REQ_COMMAND_PARAMS(t *testing.T) {
+	// [REQ:COMMAND_PARAMS] [ARCH:COMMAND_PARAMS] [IMPL:COMMAND_PARAMS]
+	values := map[string]string{"env": "prod", "msg": `say "100%" \o/`}
+	tests := []struct {
+		in  string
+		out string
+	}{
+		{`deploy %{env} %f`, `deploy "prod" %f`},
+		{`deploy %~{env} %D@`, `deploy prod %D@`},
+		{`git commit -m %{msg}`, `git commit -m "say \\"100\%\\" \\o/"`},
+		{`echo %~{msg}`, `echo say "100\%" \\o/`},
+		{`echo \%{env} %{missing} %{bad name} %{`, `echo \%{env} %{missing} %{bad name} %{`},
+		{`echo %%{env}`, `echo %"prod"`},
+	}
+	for _, tt := range tests {
+		if got := ExpandParams(tt.in, values); got != tt.out {
+			t.Errorf("ExpandParams(%q) = %q, want %q", tt.in, got, tt.out)
+		}
+	}
+	if refs := ParamRefs(`a %{x} \%{y} %~{z}`); strings.Join(refs, ",") != "x,z" {
+		t.Errorf("ParamRefs = %v, want [x z]", refs)
+	}
+}
@@ -6,12 +6,51 @@ const MenuName = "external-command"
 // Entry represents a single external command binding definition.
 // [IMPL:EXTERNAL_COMMAND_LOADER] [ARCH:EXTERNAL_COMMAND_REGISTRY] [REQ:EXTERNAL_COMMAND_CONFIG]
 type Entry struct {
-	Menu      string   `json:"menu" yaml:"menu"`
-	Key       string   `json:"key" yaml:"key"`
-	Label     string   `json:"label" yaml:"label"`
-	Command   string   `json:"command" yaml:"command"`
-	RunMenu   string   `json:"runMenu" yaml:"runMenu"`
-	Offset    int      `json:"offset" yaml:"offset"`
-	Platforms []string `json:"platforms" yaml:"platforms"`
-	Disabled  bool     `json:"disabled" yaml:"disabled"`
+	Menu      string    `json:"menu" yaml:"menu"`
+	Key       string    `json:"key" yaml:"key"`
+	Label     string    `json:"label" yaml:"label"`
+	Command   string    `json:"command" yaml:"command"`
+	RunMenu   string    `json:"runMenu" yaml:"runMenu"`
+	Spawn     string    `json:"spawn" yaml:"spawn"`   // [IMPL:CONFIG_MENUS] run directly without the shell prompt
+	Action    string    `json:"action" yaml:"action"` // [IMPL:ACTION_REGISTRY] registered action name
+	Offset    int       `json:"offset" yaml:"offset"`
+	Params    []Param   `json:"params" yaml:"params"` // [IMPL:COMMAND_PARAMS] prompted before command/spawn
+	Output    string    `json:"output" yaml:"output"` // [IMPL:COMMAND_OUTPUT_CAPTURE] OutputCapture shows output in goful
+	Reload    bool      `json:"reload" yaml:"reload"` // [IMPL:COMMAND_OUTPUT_CAPTURE] reload panes after a captured command
+	When      Condition `json:"when" yaml:"when"`     // [IMPL:COMMAND_CONDITIONS] evaluated when the menu opens
+	Platforms []string  `json:"platforms" yaml:"platforms"`
+	Disabled  bool      `json:"disabled" yaml:"disabled"`
+}
+
+// Config is a loaded commands file: its entries and the menus whose built-in
+// items the file replaces instead of extending.
+// [IMPL:CONFIG_MENUS] [ARCH:CONFIG_MENUS] [REQ:CONFIG_MENUS]
+type Config struct {
+	Entries      []Entry
+	ReplaceMenus []string
+}
+
+// OutputCapture runs an entry through the shell and shows its output in a
+// popup instead of a terminal.
+// [IMPL:COMMAND_OUTPUT_CAPTURE] [ARCH:COMMAND_OUTPUT_CAPTURE] [REQ:COMMAND_OUTPUT_CAPTURE]
+const OutputCapture = "capture"
+
+// Param kinds prompted through the cmdline before an entry runs.
+// [IMPL:COMMAND_PARAMS] [ARCH:COMMAND_PARAMS] [REQ:COMMAND_PARAMS]
+const (
+	ParamText    = "text"    // free text
+	ParamChoice  = "choice"  // one of Choices
+	ParamConfirm = "confirm" // yes/no; no cancels the command
+	ParamFile    = "file"    // a path, prefilled from the file under the cursor of the next pane
+)
+
+// Param is an input prompted before an entry runs and substituted for
+// %{name} (quoted) or %~{name} (raw) in its command.
+// [IMPL:COMMAND_PARAMS] [ARCH:COMMAND_PARAMS] [REQ:COMMAND_PARAMS]
+type Param struct {
+	Name    string   `json:"name" yaml:"name"`
+	Type    string   `json:"type" yaml:"type"`
+	Prompt  string   `json:"prompt" yaml:"prompt"`
+	Default string   `json:"default" yaml:"default"`
+	Choices []string `json:"choices" yaml:"choices"`
 }
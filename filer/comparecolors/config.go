@@ -76,14 +76,24 @@ func Load(path string) (*Config, error) {
 		return DefaultConfig(), fmt.Errorf("read compare colors config: %w", err)
 	}
 
-	cfg := DefaultConfig() // Start with defaults so partial configs work
-	if err := yaml.Unmarshal(data, cfg); err != nil {
+	cfg, err := Parse(data)
+	if err != nil {
 		return DefaultConfig(), fmt.Errorf("parse compare colors config: %w", err)
 	}
 
 	return cfg, nil
 }
 
+// Parse decodes a YAML color configuration over the defaults.
+// [IMPL:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [REQ:UNIFIED_CONFIG]
+func Parse(data []byte) (*Config, error) {
+	cfg := DefaultConfig() // Start with defaults so partial configs work
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return DefaultConfig(), err
+	}
+	return cfg, nil
+}
+
 // ParsedConfig holds the resolved tcell.Style values for each comparison state.
 // [IMPL:COMPARE_COLOR_CONFIG] [ARCH:FILE_COMPARISON_ENGINE] [REQ:FILE_COMPARISON_COLORS]
 type ParsedConfig struct {
@@ -0,0 +1,48 @@
+// Package filer difference results collection for the results panel.
+// [IMPL:DIFF_RESULTS_PANEL] [ARCH:DIFF_RESULTS_PANEL] [REQ:DIFF_RESULTS_PANEL]
+package filer
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// PanesContaining returns the 1-based window numbers whose directory holds name.
+// The numbering matches the "missing in window N" reasons produced by CheckDifference.
+// [IMPL:DIFF_RESULTS_PANEL] [ARCH:DIFF_RESULTS_PANEL] [REQ:DIFF_RESULTS_PANEL]
+func PanesContaining(dirs []*Directory, name string) []int {
+	var panes []int
+	for i, d := range dirs {
+		if findEntryInDir(d, name) != nil {
+			panes = append(panes, i+1)
+		}
+	}
+	return panes
+}
+
+// NavigateToDiff moves every pane to the directory holding entry and places
+// the cursor on it. initialDirs are the roots the entry path is relative to,
+// one per pane, as recorded in DiffReport.Directories.
+// [IMPL:DIFF_RESULTS_PANEL] [ARCH:DIFF_RESULTS_PANEL] [REQ:DIFF_RESULTS_PANEL]
+func (n *WorkspaceNavigator) NavigateToDiff(initialDirs []string, entry DiffEntry) error {
+	dirs := n.GetDirs()
+	if len(initialDirs) != len(dirs) {
+		return fmt.Errorf("window count changed since the search (%d -> %d)", len(initialDirs), len(dirs))
+	}
+	for i, d := range dirs {
+		d.Chdir(initialDirs[i])
+	}
+
+	// Every ancestor of a reported entry exists in all windows because the
+	// walker only descends into subdirectories present everywhere.
+	rel := filepath.Dir(strings.TrimSuffix(entry.Path, "/"))
+	if rel != "." {
+		for _, part := range strings.Split(rel, string(filepath.Separator)) {
+			n.ChdirAll(part)
+		}
+	}
+	n.RebuildComparisonIndex()
+	n.ws.SetCursorByNameAll(strings.TrimSuffix(entry.Name, "/"))
+	return nil
+}
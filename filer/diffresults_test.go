@@ -0,0 +1,84 @@
+// Package filer difference results tests.
+// [IMPL:DIFF_RESULTS_PANEL] [ARCH:DIFF_RESULTS_PANEL] [REQ:DIFF_RESULTS_PANEL]
+package filer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// newDiffResultsWorkspace builds a workspace with one window per path.
+func newDiffResultsWorkspace(paths ...string) *Workspace {
+	ws := NewWorkspace(0, 0, 80, 40, "test")
+	for _, p := range paths {
+		d := NewDirectory(0, 0, 40, 20)
+		d.Chdir(p)
+		ws.Dirs = append(ws.Dirs, d)
+	}
+	return ws
+}
+
+// TestRunBatchDiffSearch_Panes_REQ_DIFF_RESULTS_PANEL verifies every difference is collected with the windows holding it.
+func TestRunBatchDiffSearch_Panes_REQ_DIFF_RESULTS_PANEL(t *testing.T) {
+	tmpDir := t.TempDir()
+	dir1 := filepath.Join(tmpDir, "dir1")
+	dir2 := filepath.Join(tmpDir, "dir2")
+	dir3 := filepath.Join(tmpDir, "dir3")
+	for _, d := range []string{dir1, dir2, dir3} {
+		os.MkdirAll(filepath.Join(d, "sub"), 0755)
+	}
+	os.WriteFile(filepath.Join(dir1, "a.txt"), []byte("a"), 0644)
+	os.WriteFile(filepath.Join(dir3, "a.txt"), []byte("a"), 0644)
+	os.WriteFile(filepath.Join(dir2, "sub", "b.txt"), []byte("b"), 0644)
+
+	report, err := RunBatchDiffSearch([]string{dir1, dir2, dir3}, nil)
+	if err != nil {
+		t.Fatalf("RunBatchDiffSearch failed: %v", err)
+	}
+	if len(report.Differences) != 2 {
+		t.Fatalf("expected 2 differences, got %+v", report.Differences)
+	}
+
+	first := report.Differences[0]
+	if first.Path != "a.txt" || !reflect.DeepEqual(first.Panes, []int{1, 3}) {
+		t.Errorf("unexpected first entry %+v", first)
+	}
+	second := report.Differences[1]
+	if second.Path != filepath.Join("sub", "b.txt") || !reflect.DeepEqual(second.Panes, []int{2}) {
+		t.Errorf("unexpected second entry %+v", second)
+	}
+}
+
+// TestNavigateToDiff_REQ_DIFF_RESULTS_PANEL verifies selection moves all windows to the entry.
+func TestNavigateToDiff_REQ_DIFF_RESULTS_PANEL(t *testing.T) {
+	tmpDir := t.TempDir()
+	dir1 := filepath.Join(tmpDir, "dir1")
+	dir2 := filepath.Join(tmpDir, "dir2")
+	os.MkdirAll(filepath.Join(dir1, "sub", "deep"), 0755)
+	os.MkdirAll(filepath.Join(dir2, "sub", "deep"), 0755)
+	os.WriteFile(filepath.Join(dir1, "sub", "deep", "x.txt"), []byte("1"), 0644)
+	os.WriteFile(filepath.Join(dir2, "sub", "deep", "x.txt"), []byte("22"), 0644)
+
+	ws := newDiffResultsWorkspace(tmpDir, tmpDir)
+	nav := NewWorkspaceNavigator(ws)
+	entry := DiffEntry{Name: "x.txt", Path: filepath.Join("sub", "deep", "x.txt"), Reason: "size mismatch"}
+	if err := nav.NavigateToDiff([]string{dir1, dir2}, entry); err != nil {
+		t.Fatalf("NavigateToDiff failed: %v", err)
+	}
+
+	want := []string{filepath.Join(dir1, "sub", "deep"), filepath.Join(dir2, "sub", "deep")}
+	for i, d := range ws.Dirs {
+		if d.Path != want[i] {
+			t.Errorf("window %d at %s, want %s", i+1, d.Path, want[i])
+		}
+		if d.File().Name() != "x.txt" {
+			t.Errorf("window %d cursor on %s, want x.txt", i+1, d.File().Name())
+		}
+	}
+
+	if err := nav.NavigateToDiff([]string{dir1}, entry); err == nil {
+		t.Error("expected an error when the window count differs from the report")
+	}
+}
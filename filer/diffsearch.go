@@ -300,6 +300,12 @@ func CheckDifference(name string, dirs []*Directory) (isDiff bool, reason string
 		}
 	}
 
+	// Entries compared against a snapshot manifest also check content and permissions
+	// [IMPL:DIFF_SNAPSHOT] [ARCH:DIFF_SNAPSHOT] [REQ:DIFF_SNAPSHOT]
+	if reason := manifestDrift(entries); reason != "" {
+		return true, reason, false
+	}
+
 	return false, "", false
 }
 
@@ -556,53 +562,39 @@ func (w *TreeWalker) Run(progressFn func()) Step {
 		// Determine subdirStartAfter: if startAfter is a subdirectory name, use it (to skip it and find next).
 		// If startAfter is a filename, check all subdirectories from the beginning (files first, then dirs).
 		subdirStartAfter := ""
-		subdirNames := CollectSubdirNames(dirs)
-		// Check if startAfter is a subdirectory name
-		for _, subdirName := range subdirNames {
-			if subdirName == startAfter {
-				// startAfter is a subdirectory name - we've already processed all files,
-				// so check subdirectories starting from this one (to skip it and find next)
-				subdirStartAfter = startAfter
-				break
-			}
+		if isSubdirName {
+			subdirStartAfter = startAfter
 		}
-		// If startAfter is not a subdirectory name (it's a filename or empty),
-		// subdirStartAfter remains "" to check all subdirectories from the beginning
-		// Use FindNextSubdirDifference to check ONLY subdirectories (not files)
-		// This ensures subdirectories are checked independently per the "files first, then dirs" requirement
-		subdirResult := FindNextSubdirDifference(dirs, subdirStartAfter)
-		// Only return if we found a directory difference (not a file)
-		if subdirResult.Found && subdirResult.IsDir {
-			// Found a subdir that differs (missing in some window)
-			return Step{
-				Type:   StepFoundDiff,
-				Name:   subdirResult.Name + "/",
-				Reason: subdirResult.Reason,
-				IsDir:  true,
+		// Visit subdirectories strictly in alphabetical order: report one that differs
+		// (missing in some window), descend into one present in all windows.
+		// Checking every differing subdir before descending would skip common subdirs
+		// that sort before a differing one, and the reverse walk mirrors this order.
+		// [IMPL:DIFF_SEARCH_REVERSE] [ARCH:DIFF_SEARCH_REVERSE] [REQ:DIFF_SEARCH_REVERSE]
+		descended := false
+		for {
+			subdir, existsInAll, found := FindNextSubdir(dirs, subdirStartAfter)
+			if !found {
+				break
 			}
-		}
-
-		// No differences at this level, try to descend into a subdir that exists in all
-		// [IMPL:DIFF_SEARCH] [ARCH:DIFF_SEARCH] [REQ:DIFF_SEARCH]
-		// CRITICAL FIX: When descending into subdirectories, we must use a subdirectory-specific
-		// startAfter position, not the file startAfter. If startAfter is a filename, we should
-		// check all subdirectories from the beginning. If startAfter is a subdirectory name,
-		// we should start from that subdirectory.
-		subdirStartAfterForDescent := ""
-		// Check if startAfter is a subdirectory name
-		subdirNamesForDescent := CollectSubdirNames(dirs)
-		for _, subdirName := range subdirNamesForDescent {
-			if subdirName == startAfter {
-				subdirStartAfterForDescent = startAfter
+			if existsInAll {
+				// Descend into this subdir in all windows
+				w.nav.ChdirAll(subdir)
+				startAfter = "" // Start from beginning in new directory
+				descended = true
 				break
 			}
+			if isDiff, reason, _ := CheckDifference(subdir, dirs); isDiff {
+				// Found a subdir that differs (missing in some window)
+				return Step{
+					Type:   StepFoundDiff,
+					Name:   subdir + "/",
+					Reason: reason,
+					IsDir:  true,
+				}
+			}
+			subdirStartAfter = subdir
 		}
-		// Use subdirectory-specific startAfter for descent
-		subdir, found := FindNextSubdirInAll(dirs, subdirStartAfterForDescent)
-		if found {
-			// Descend into this subdir in all windows
-			w.nav.ChdirAll(subdir)
-			startAfter = "" // Start from beginning in new directory
+		if descended {
 			continue
 		}
 
@@ -621,7 +613,7 @@ func (w *TreeWalker) Run(progressFn func()) Step {
 		w.nav.RebuildComparisonIndex()
 
 		// Continue searching from the child directory we just exited
-		// so FindNextSubdirInAll can find the next sibling
+		// so FindNextSubdir can find the next sibling
 		// [IMPL:DIFF_SEARCH] [ARCH:DIFF_SEARCH] [REQ:DIFF_SEARCH]
 		// After ascending, startAfter is set to the child directory name.
 		// When checking files, we should check all files from the beginning (files first, then dirs).
@@ -661,10 +653,11 @@ func (w *TreeWalker) Run(progressFn func()) Step {
 // DiffEntry represents a single difference found during batch comparison.
 // [IMPL:BATCH_DIFF_REPORT] [ARCH:BATCH_DIFF_REPORT] [REQ:BATCH_DIFF_REPORT]
 type DiffEntry struct {
-	Name   string `yaml:"name"`   // Filename or dirname (with "/" suffix for dirs)
-	Path   string `yaml:"path"`   // Relative path from comparison root
-	Reason string `yaml:"reason"` // Why it differs (e.g., "size mismatch", "missing in window 2")
-	IsDir  bool   `yaml:"isDir"`  // Whether entry is a directory
+	Name   string `yaml:"name"`            // Filename or dirname (with "/" suffix for dirs)
+	Path   string `yaml:"path"`            // Relative path from comparison root
+	Reason string `yaml:"reason"`          // Why it differs (e.g., "size mismatch", "missing in window 2")
+	IsDir  bool   `yaml:"isDir"`           // Whether entry is a directory
+	Panes  []int  `yaml:"panes,omitempty"` // 1-based windows holding the entry [REQ:DIFF_RESULTS_PANEL]
 }
 
 // DiffReport is the YAML-serializable output of a batch diff search.
@@ -705,6 +698,17 @@ func NewBatchNavigator(paths []string) (*BatchNavigator, error) {
 		}
 		initialDirs[i] = absPath
 
+		// A regular file stands for a snapshot manifest of a directory
+		// [IMPL:DIFF_SNAPSHOT] [ARCH:DIFF_SNAPSHOT] [REQ:DIFF_SNAPSHOT]
+		if info, err := os.Stat(absPath); err == nil && info.Mode().IsRegular() {
+			dir, err := newManifestDirectory(absPath)
+			if err != nil {
+				return nil, fmt.Errorf("cannot load manifest %q: %w", path, err)
+			}
+			dirs[i] = dir
+			continue
+		}
+
 		// Create a headless directory using batch-safe loading
 		dir, err := newBatchDirectory(absPath)
 		if err != nil {
@@ -758,34 +762,47 @@ func newBatchDirectory(path string) (*Directory, error) {
 // batchRead reads directory contents for batch mode without TUI dependencies.
 // [IMPL:BATCH_DIFF_REPORT] [ARCH:BATCH_DIFF_REPORT] [REQ:BATCH_DIFF_REPORT]
 func (d *Directory) batchRead() error {
-	entries, err := os.ReadDir(d.Path)
-	if err != nil {
-		return err
-	}
-
 	// Build file list - collect names first for filtering and sorting
 	type fileEntry struct {
 		name  string
 		isDir bool
+		stat  *FileStat // preset for manifest-backed directories
 	}
 	var fileEntries []fileEntry
 
-	for _, entry := range entries {
-		name := entry.Name()
+	keep := func(name string) bool {
 		// Apply hidden file filter
-		if !showHiddens && strings.HasPrefix(name, ".") {
-			continue
+		if d.isHidden(name) {
+			return false
 		}
 		// Apply exclude filter
-		if shouldExcludeName(name) {
-			continue
+		return !shouldExcludeName(name)
+	}
+
+	// [IMPL:DIFF_SNAPSHOT] [ARCH:DIFF_SNAPSHOT] [REQ:DIFF_SNAPSHOT]
+	r, fromManifest := d.reader.(*manifestReader)
+	if fromManifest {
+		for _, fs := range d.manifestFileStats(r) {
+			if keep(fs.Name()) {
+				fileEntries = append(fileEntries, fileEntry{name: fs.Name(), isDir: fs.IsDir(), stat: fs})
+			}
+		}
+	} else {
+		entries, err := os.ReadDir(d.Path)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if keep(entry.Name()) {
+				fileEntries = append(fileEntries, fileEntry{name: entry.Name(), isDir: entry.IsDir()})
+			}
 		}
-		fileEntries = append(fileEntries, fileEntry{name: name, isDir: entry.IsDir()})
 	}
 
-	// Sort entries - directories first if priorityDir is set, then by name
+	// Sort entries - directories first if the view prioritizes them, then by name
+	priority := d.ViewSettings().PriorityDir
 	sort.Slice(fileEntries, func(i, j int) bool {
-		if priorityDir {
+		if priority {
 			if fileEntries[i].isDir && !fileEntries[j].isDir {
 				return true
 			}
@@ -800,14 +817,19 @@ func (d *Directory) batchRead() error {
 	list := make([]widget.Drawer, 0, len(fileEntries)+1)
 
 	// Add parent directory entry ".."
-	parentStat := NewFileStat(d.Path, "..")
-	if parentStat != nil {
-		list = append(list, parentStat)
+	if !fromManifest {
+		parentStat := NewFileStat(d.Path, "..")
+		if parentStat != nil {
+			list = append(list, parentStat)
+		}
 	}
 
 	// Add regular entries
 	for _, entry := range fileEntries {
-		fs := NewFileStat(d.Path, entry.name)
+		fs := entry.stat
+		if fs == nil {
+			fs = NewFileStat(d.Path, entry.name)
+		}
 		if fs != nil {
 			list = append(list, fs)
 		}
@@ -862,6 +884,11 @@ func (n *BatchNavigator) ChdirParentAll() {
 // batchChdir changes directory for batch mode without TUI dependencies.
 // [IMPL:BATCH_DIFF_REPORT] [ARCH:BATCH_DIFF_REPORT] [REQ:BATCH_DIFF_REPORT]
 func (d *Directory) batchChdir(path string) error {
+	// [IMPL:DIFF_SNAPSHOT] [ARCH:DIFF_SNAPSHOT] [REQ:DIFF_SNAPSHOT]
+	if r, ok := d.reader.(*manifestReader); ok {
+		return d.manifestChdir(r, path)
+	}
+
 	// Compute absolute path
 	var absPath string
 	if filepath.IsAbs(path) {
@@ -1002,6 +1029,7 @@ func RunBatchDiffSearch(paths []string, progressFn ProgressCallback) (*DiffRepor
 				Path:   entryPath,
 				Reason: step.Reason,
 				IsDir:  step.IsDir,
+				Panes:  PanesContaining(nav.GetDirs(), strings.TrimSuffix(step.Name, "/")),
 			})
 
 			// Continue searching from after this difference
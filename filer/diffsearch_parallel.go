@@ -0,0 +1,206 @@
+// Package filer parallel traversal for the batch diff report.
+// [IMPL:DIFF_PARALLEL] [ARCH:DIFF_PARALLEL] [REQ:DIFF_PARALLEL]
+package filer
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// RunParallelDiffSearch produces the same report as RunBatchDiffSearch, in the
+// same order, while reading the windows' directories concurrently and walking
+// sibling subtrees in parallel. jobs bounds both the concurrent directory reads
+// and the subtree goroutines; jobs <= 1 falls back to RunBatchDiffSearch.
+// progressFn calls are serialized.
+// [IMPL:DIFF_PARALLEL] [ARCH:DIFF_PARALLEL] [REQ:DIFF_PARALLEL]
+func RunParallelDiffSearch(paths []string, jobs int, progressFn ProgressCallback) (*DiffReport, error) {
+	if jobs <= 1 {
+		return RunBatchDiffSearch(paths, progressFn)
+	}
+	startTime := time.Now()
+
+	nav, err := NewBatchNavigator(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &parallelCollector{
+		reads:      make(chan struct{}, jobs),
+		spawns:     make(chan struct{}, jobs-1),
+		progressFn: progressFn,
+	}
+	differences, err := c.level(nav.GetDirs(), "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &DiffReport{
+		Directories:               nav.InitialDirs(),
+		TotalFilesChecked:         c.filesChecked,
+		TotalDirectoriesTraversed: c.dirsTraversed,
+		DurationSeconds:           time.Since(startTime).Seconds(),
+		Differences:               differences,
+	}, nil
+}
+
+// parallelCollector collects differences level by level. Each level applies
+// the TreeWalker rules (files first, then subdirectories in order, resuming
+// after each reported name) and splices the results of its subtrees in order,
+// so the output does not depend on scheduling.
+// [IMPL:DIFF_PARALLEL] [ARCH:DIFF_PARALLEL] [REQ:DIFF_PARALLEL]
+type parallelCollector struct {
+	reads      chan struct{} // bounds concurrent directory reads
+	spawns     chan struct{} // bounds extra subtree goroutines
+	progressFn ProgressCallback
+
+	mu            sync.Mutex
+	filesChecked  int
+	dirsTraversed int
+	diffsFound    int
+}
+
+// level returns the differences at and below dirs, in TreeWalker order.
+func (c *parallelCollector) level(dirs []*Directory, rel string) ([]DiffEntry, error) {
+	subdirs := CollectSubdirNames(dirs)
+	c.progress(len(CollectFileNames(dirs)), dirs[0].Path)
+
+	// Files first. A reported name that is also a subdirectory somewhere ends the
+	// file phase and the subdirectory phase resumes after it, as in TreeWalker.Run.
+	var found []DiffEntry
+	after := ""
+	for {
+		r := FindNextDifference(dirs, after, true)
+		if !r.Found {
+			break
+		}
+		found = append(found, c.entry(dirs, rel, r.Name, r.Reason, r.IsDir))
+		after = r.Name
+		if containsName(subdirs, after) {
+			break
+		}
+	}
+	subAfter := ""
+	if containsName(subdirs, after) {
+		subAfter = after
+	}
+
+	// Then subdirectories in order: report those that differ, descend into the rest.
+	// Each part is filled by its own goroutine or inline, then spliced in order.
+	parts := []*levelPart{{entries: found}}
+	var wg sync.WaitGroup
+	for {
+		name, existsInAll, ok := FindNextSubdir(dirs, subAfter)
+		if !ok {
+			break
+		}
+		subAfter = name
+		if !existsInAll {
+			if isDiff, reason, _ := CheckDifference(name, dirs); isDiff {
+				parts = append(parts, &levelPart{entries: []DiffEntry{c.entry(dirs, rel, name+"/", reason, true)}})
+			}
+			continue
+		}
+
+		part := &levelPart{}
+		parts = append(parts, part)
+		childRel := name
+		if rel != "" {
+			childRel = filepath.Join(rel, name)
+		}
+		select {
+		case c.spawns <- struct{}{}:
+			wg.Add(1)
+			go func() {
+				defer func() { <-c.spawns; wg.Done() }()
+				c.descend(part, dirs, name, childRel)
+			}()
+		default:
+			c.descend(part, dirs, name, childRel)
+		}
+	}
+	wg.Wait()
+
+	var differences []DiffEntry
+	for _, part := range parts {
+		if part.err != nil {
+			return nil, part.err
+		}
+		differences = append(differences, part.entries...)
+	}
+	return differences, nil
+}
+
+// levelPart holds the ordered results of one slot of a level.
+type levelPart struct {
+	entries []DiffEntry
+	err     error
+}
+
+// descend reads the named subdirectory in every window and collects below it.
+func (c *parallelCollector) descend(part *levelPart, dirs []*Directory, name, rel string) {
+	children, err := c.chdir(dirs, name)
+	if err != nil {
+		part.err = err
+		return
+	}
+	part.entries, part.err = c.level(children, rel)
+}
+
+// chdir reads the named subdirectory of every window concurrently.
+func (c *parallelCollector) chdir(dirs []*Directory, name string) ([]*Directory, error) {
+	children := make([]*Directory, len(dirs))
+	errs := make([]error, len(dirs))
+	var wg sync.WaitGroup
+	for i, d := range dirs {
+		wg.Add(1)
+		go func(i int, d *Directory) {
+			defer wg.Done()
+			c.reads <- struct{}{}
+			defer func() { <-c.reads }()
+			child := &Directory{reader: d.reader, history: map[string]string{}, Path: d.Path, Sort: d.Sort, View: d.View}
+			if err := child.batchChdir(name); err != nil {
+				errs[i] = fmt.Errorf("cannot read %s: %w", filepath.Join(d.Path, name), err)
+				return
+			}
+			children[i] = child
+		}(i, d)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return children, nil
+}
+
+// entry builds a report entry the way RunBatchDiffSearch does.
+func (c *parallelCollector) entry(dirs []*Directory, rel, name, reason string, isDir bool) DiffEntry {
+	path := name
+	if rel != "" {
+		path = filepath.Join(rel, name)
+	}
+	c.mu.Lock()
+	c.diffsFound++
+	c.mu.Unlock()
+	return DiffEntry{
+		Name:   name,
+		Path:   path,
+		Reason: reason,
+		IsDir:  isDir,
+		Panes:  PanesContaining(dirs, filepath.Base(name)),
+	}
+}
+
+// progress records a visited level and reports the running totals.
+func (c *parallelCollector) progress(files int, path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dirsTraversed++
+	c.filesChecked += files
+	if c.progressFn != nil {
+		c.progressFn(c.filesChecked, c.dirsTraversed, c.diffsFound, path)
+	}
+}
@@ -0,0 +1,90 @@
+// Package filer parallel batch diff tests.
+// [IMPL:DIFF_PARALLEL] [ARCH:DIFF_PARALLEL] [REQ:DIFF_PARALLEL]
+package filer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeParallelTrees creates three roots with nested common, partial and
+// conflicting entries spread across several sibling subtrees.
+func writeParallelTrees(t *testing.T) []string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	roots := []string{filepath.Join(tmpDir, "a"), filepath.Join(tmpDir, "b"), filepath.Join(tmpDir, "c")}
+	for r, root := range roots {
+		for i := 0; i < 4; i++ {
+			for j := 0; j < 3; j++ {
+				dir := filepath.Join(root, fmt.Sprintf("d%d", i), fmt.Sprintf("s%d", j))
+				os.MkdirAll(dir, 0755)
+				os.WriteFile(filepath.Join(dir, "same.txt"), []byte("same"), 0644)
+				if (i+j+r)%3 == 0 {
+					os.WriteFile(filepath.Join(dir, fmt.Sprintf("only%d.txt", r)), []byte("x"), 0644)
+				}
+				if (i*j)%2 == 1 {
+					os.WriteFile(filepath.Join(dir, "size.txt"), make([]byte, r+1), 0644)
+				}
+			}
+			if i == r {
+				os.MkdirAll(filepath.Join(root, fmt.Sprintf("d%d", i), "partial"), 0755)
+			}
+		}
+		os.WriteFile(filepath.Join(root, "top.txt"), make([]byte, r%2), 0644)
+	}
+	// "zz" is a file in the first root and a directory in the others; a name of
+	// both kinds ends the file phase of its level, so it sorts last here.
+	os.WriteFile(filepath.Join(roots[0], "d3", "s2", "zz"), []byte("f"), 0644)
+	os.MkdirAll(filepath.Join(roots[1], "d3", "s2", "zz"), 0755)
+	os.MkdirAll(filepath.Join(roots[2], "d3", "s2", "zz"), 0755)
+	return roots
+}
+
+// TestRunParallelDiffSearch_MatchesSequential_REQ_DIFF_PARALLEL verifies every
+// job count yields exactly the sequential differences in the same order.
+func TestRunParallelDiffSearch_MatchesSequential_REQ_DIFF_PARALLEL(t *testing.T) {
+	roots := writeParallelTrees(t)
+	want, err := RunBatchDiffSearch(roots, nil)
+	if err != nil {
+		t.Fatalf("RunBatchDiffSearch failed: %v", err)
+	}
+	if len(want.Differences) < 10 {
+		t.Fatalf("fixture too small: %d differences", len(want.Differences))
+	}
+
+	for _, jobs := range []int{1, 2, 4, 16} {
+		for run := 0; run < 3; run++ {
+			got, err := RunParallelDiffSearch(roots, jobs, nil)
+			if err != nil {
+				t.Fatalf("jobs=%d: RunParallelDiffSearch failed: %v", jobs, err)
+			}
+			if !reflect.DeepEqual(got.Differences, want.Differences) {
+				t.Fatalf("jobs=%d: differences differ from sequential\ngot:  %+v\nwant: %+v", jobs, got.Differences, want.Differences)
+			}
+			if !reflect.DeepEqual(got.Directories, want.Directories) {
+				t.Errorf("jobs=%d: directories = %v, want %v", jobs, got.Directories, want.Directories)
+			}
+		}
+	}
+}
+
+// TestRunParallelDiffSearch_Progress_REQ_DIFF_PARALLEL verifies progress counts every level.
+func TestRunParallelDiffSearch_Progress_REQ_DIFF_PARALLEL(t *testing.T) {
+	roots := writeParallelTrees(t)
+	calls := 0
+	report, err := RunParallelDiffSearch(roots, 4, func(filesChecked, dirsTraversed, diffsFound int, currentPath string) {
+		calls++ // serialized by the collector
+	})
+	if err != nil {
+		t.Fatalf("RunParallelDiffSearch failed: %v", err)
+	}
+	if calls != report.TotalDirectoriesTraversed || calls < 1+4*3 {
+		t.Errorf("progress calls = %d, directories traversed = %d", calls, report.TotalDirectoriesTraversed)
+	}
+	if report.TotalFilesChecked == 0 {
+		t.Error("expected files to be counted")
+	}
+}
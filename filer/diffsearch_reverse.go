@@ -0,0 +1,126 @@
+// Package filer reverse difference search.
+// [IMPL:DIFF_SEARCH_REVERSE] [ARCH:DIFF_SEARCH_REVERSE] [REQ:DIFF_SEARCH_REVERSE]
+package filer
+
+// ReverseTreeWalker walks the comparison tree backward from a position,
+// visiting entries in exactly the reverse of the forward search order:
+// at each level, subdirectories in reverse alphabetical order (descending
+// into those present in every window and reporting those that differ),
+// then files in reverse alphabetical order, then the parent level.
+// [IMPL:DIFF_SEARCH_REVERSE] [ARCH:DIFF_SEARCH_REVERSE] [REQ:DIFF_SEARCH_REVERSE]
+type ReverseTreeWalker struct {
+	nav         Navigator
+	state       *DiffSearchState
+	startBefore string
+}
+
+// NewReverseTreeWalker creates a walker that finds the difference preceding
+// startBefore in the current directories. An empty startBefore (cursor on
+// "..") means nothing at the current level precedes the position.
+// [IMPL:DIFF_SEARCH_REVERSE] [ARCH:DIFF_SEARCH_REVERSE] [REQ:DIFF_SEARCH_REVERSE]
+func NewReverseTreeWalker(nav Navigator, state *DiffSearchState, startBefore string) *ReverseTreeWalker {
+	return &ReverseTreeWalker{
+		nav:         nav,
+		state:       state,
+		startBefore: startBefore,
+	}
+}
+
+// Run executes the backward traversal until a difference is found or the
+// start of the search tree is reached (StepComplete).
+// Calls progressFn after each directory is processed (for UI updates).
+// [IMPL:DIFF_SEARCH_REVERSE] [ARCH:DIFF_SEARCH_REVERSE] [REQ:DIFF_SEARCH_REVERSE]
+func (w *ReverseTreeWalker) Run(progressFn func()) Step {
+	before := w.startBefore
+	fromEnd := false // true right after descending: every entry at this level precedes the position
+
+	for {
+		if progressFn != nil {
+			progressFn()
+		}
+
+		dirs := w.nav.GetDirs()
+
+		// Subdirectories come after files in forward order, so they are visited first here.
+		// They are only candidates when the position is a subdirectory or the end of the level.
+		subdirs := CollectSubdirNames(dirs)
+		inSubdirs := fromEnd || containsName(subdirs, before)
+		if inSubdirs {
+			descended := false
+			for i := len(subdirs) - 1; i >= 0; i-- {
+				name := subdirs[i]
+				if !fromEnd && name >= before {
+					continue
+				}
+				if isDiff, reason, _ := CheckDifference(name, dirs); isDiff {
+					return Step{
+						Type:   StepFoundDiff,
+						Name:   name + "/",
+						Reason: reason,
+						IsDir:  true,
+					}
+				}
+				if subdirInAll(dirs, name) {
+					// Its contents precede the current position; walk them from the end.
+					w.nav.ChdirAll(name)
+					descended = true
+					break
+				}
+			}
+			if descended {
+				fromEnd = true
+				continue
+			}
+		}
+
+		// Then files in reverse order: all of them when coming from the subdirectory
+		// phase, otherwise only those before the file under the position.
+		files := CollectFileNames(dirs)
+		for i := len(files) - 1; i >= 0; i-- {
+			name := files[i]
+			if !inSubdirs && (before == "" || name >= before) {
+				continue
+			}
+			if isDiff, reason, isDir := CheckDifference(name, dirs); isDiff {
+				return Step{
+					Type:   StepFoundDiff,
+					Name:   name,
+					Reason: reason,
+					IsDir:  isDir,
+				}
+			}
+		}
+
+		// Nothing left at this level; the parent's entries before this directory come next.
+		if w.state.AtInitialDirs(dirs) {
+			return Step{Type: StepComplete}
+		}
+		childDirName := dirs[0].Base()
+		w.nav.ChdirParentAll()
+		w.nav.RebuildComparisonIndex()
+		before = childDirName
+		fromEnd = false
+	}
+}
+
+// containsName reports whether names holds name.
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// subdirInAll reports whether name is a directory in every window.
+// [IMPL:DIFF_SEARCH_REVERSE] [ARCH:DIFF_SEARCH_REVERSE] [REQ:DIFF_SEARCH_REVERSE]
+func subdirInAll(dirs []*Directory, name string) bool {
+	for _, dir := range dirs {
+		entry := findEntryInDir(dir, name)
+		if entry == nil || !entry.IsDir() {
+			return false
+		}
+	}
+	return true
+}
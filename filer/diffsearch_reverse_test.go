@@ -0,0 +1,161 @@
+// Package filer reverse difference search tests.
+// [IMPL:DIFF_SEARCH_REVERSE] [ARCH:DIFF_SEARCH_REVERSE] [REQ:DIFF_SEARCH_REVERSE]
+package filer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeReverseTree creates two comparison roots:
+//
+//	a.txt      differs (size)
+//	b.txt      identical
+//	m/         only in root 1
+//	sub/       in both, holding x.txt (size) and deep/y.txt (root 2 only)
+//	zzz/       only in root 1
+func writeReverseTree(t *testing.T) (string, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	dir1 := filepath.Join(tmpDir, "dir1")
+	dir2 := filepath.Join(tmpDir, "dir2")
+	for _, d := range []string{dir1, dir2} {
+		os.MkdirAll(filepath.Join(d, "sub", "deep"), 0755)
+		os.WriteFile(filepath.Join(d, "b.txt"), []byte("same"), 0644)
+	}
+	os.WriteFile(filepath.Join(dir1, "a.txt"), []byte("1"), 0644)
+	os.WriteFile(filepath.Join(dir2, "a.txt"), []byte("22"), 0644)
+	os.WriteFile(filepath.Join(dir1, "sub", "x.txt"), []byte("1"), 0644)
+	os.WriteFile(filepath.Join(dir2, "sub", "x.txt"), []byte("22"), 0644)
+	os.WriteFile(filepath.Join(dir2, "sub", "deep", "y.txt"), []byte("y"), 0644)
+	os.MkdirAll(filepath.Join(dir1, "m"), 0755)
+	os.MkdirAll(filepath.Join(dir1, "zzz"), 0755)
+	return dir1, dir2
+}
+
+// newReverseWalk returns a batch navigator positioned at rel and a matching search state.
+func newReverseWalk(t *testing.T, dir1, dir2, rel string) (*BatchNavigator, *DiffSearchState) {
+	t.Helper()
+	nav, err := NewBatchNavigator([]string{dir1, dir2})
+	if err != nil {
+		t.Fatalf("NewBatchNavigator failed: %v", err)
+	}
+	state := &DiffSearchState{InitialDirs: nav.InitialDirs(), Active: true}
+	if rel != "" {
+		for _, part := range strings.Split(rel, string(filepath.Separator)) {
+			nav.ChdirAll(part)
+		}
+	}
+	return nav, state
+}
+
+// TestReverseTreeWalkerFromFile_REQ_DIFF_SEARCH_REVERSE tests stepping back to an earlier file.
+func TestReverseTreeWalkerFromFile_REQ_DIFF_SEARCH_REVERSE(t *testing.T) {
+	dir1, dir2 := writeReverseTree(t)
+	nav, state := newReverseWalk(t, dir1, dir2, "")
+
+	step := NewReverseTreeWalker(nav, state, "b.txt").Run(nil)
+	if step.Type != StepFoundDiff || step.Name != "a.txt" {
+		t.Fatalf("expected a.txt, got %+v", step)
+	}
+}
+
+// TestReverseTreeWalkerDescendsFromEnd_REQ_DIFF_SEARCH_REVERSE tests that common subdirs are walked from their last entry.
+func TestReverseTreeWalkerDescendsFromEnd_REQ_DIFF_SEARCH_REVERSE(t *testing.T) {
+	dir1, dir2 := writeReverseTree(t)
+	nav, state := newReverseWalk(t, dir1, dir2, "")
+
+	step := NewReverseTreeWalker(nav, state, "zzz").Run(nil)
+	if step.Type != StepFoundDiff || step.Name != "y.txt" {
+		t.Fatalf("expected deepest entry y.txt, got %+v", step)
+	}
+	if got := nav.CurrentRelativePath(); got != filepath.Join("sub", "deep") {
+		t.Errorf("expected walker inside sub/deep, got %q", got)
+	}
+}
+
+// TestReverseTreeWalkerAscends_REQ_DIFF_SEARCH_REVERSE tests leaving a subdir for the parent's earlier entries.
+func TestReverseTreeWalkerAscends_REQ_DIFF_SEARCH_REVERSE(t *testing.T) {
+	dir1, dir2 := writeReverseTree(t)
+	nav, state := newReverseWalk(t, dir1, dir2, "sub")
+
+	step := NewReverseTreeWalker(nav, state, "x.txt").Run(nil)
+	if step.Type != StepFoundDiff || step.Name != "m/" || !step.IsDir {
+		t.Fatalf("expected m/ in the parent, got %+v", step)
+	}
+	if got := nav.CurrentRelativePath(); got != "" {
+		t.Errorf("expected walker back at the root, got %q", got)
+	}
+}
+
+// TestReverseTreeWalkerComplete_REQ_DIFF_SEARCH_REVERSE tests reaching the start of the search.
+func TestReverseTreeWalkerComplete_REQ_DIFF_SEARCH_REVERSE(t *testing.T) {
+	dir1, dir2 := writeReverseTree(t)
+	nav, state := newReverseWalk(t, dir1, dir2, "")
+
+	if step := NewReverseTreeWalker(nav, state, "a.txt").Run(nil); step.Type != StepComplete {
+		t.Fatalf("expected StepComplete before the first entry, got %+v", step)
+	}
+	if step := NewReverseTreeWalker(nav, state, "").Run(nil); step.Type != StepComplete {
+		t.Fatalf("expected StepComplete from '..', got %+v", step)
+	}
+}
+
+// TestReverseTreeWalkerMirrorsForward_REQ_DIFF_SEARCH_REVERSE tests that stepping back from the
+// last difference visits the forward results in exactly reverse order.
+func TestReverseTreeWalkerMirrorsForward_REQ_DIFF_SEARCH_REVERSE(t *testing.T) {
+	dir1, dir2 := writeReverseTree(t)
+	report, err := RunBatchDiffSearch([]string{dir1, dir2}, nil)
+	if err != nil {
+		t.Fatalf("RunBatchDiffSearch failed: %v", err)
+	}
+	var forward []string
+	for _, d := range report.Differences {
+		forward = append(forward, strings.TrimSuffix(d.Path, "/"))
+	}
+	want := []string{"a.txt", "m", filepath.Join("sub", "x.txt"), filepath.Join("sub", "deep", "y.txt"), "zzz"}
+	if strings.Join(forward, ",") != strings.Join(want, ",") {
+		t.Fatalf("forward order = %v, want %v", forward, want)
+	}
+
+	last := forward[len(forward)-1]
+	rel := filepath.Dir(last)
+	if rel == "." {
+		rel = ""
+	}
+	nav, state := newReverseWalk(t, dir1, dir2, rel)
+	startBefore := filepath.Base(last)
+	var backward []string
+	for {
+		step := NewReverseTreeWalker(nav, state, startBefore).Run(nil)
+		if step.Type == StepComplete {
+			break
+		}
+		name := strings.TrimSuffix(step.Name, "/")
+		backward = append(backward, filepath.Join(nav.CurrentRelativePath(), name))
+		startBefore = name
+	}
+
+	for i, j := 0, len(forward)-2; j >= 0; i, j = i+1, j-1 {
+		if i >= len(backward) || backward[i] != forward[j] {
+			t.Fatalf("backward order = %v, want reverse of %v", backward, forward[:len(forward)-1])
+		}
+	}
+	if len(backward) != len(forward)-1 {
+		t.Fatalf("backward order = %v, want %d entries", backward, len(forward)-1)
+	}
+}
+
+// TestTreeWalkerCommonSubdirBeforeDiff_REQ_DIFF_SEARCH_REVERSE tests that the forward walk
+// descends into a common subdir sorting before a differing one instead of skipping it.
+func TestTreeWalkerCommonSubdirBeforeDiff_REQ_DIFF_SEARCH_REVERSE(t *testing.T) {
+	dir1, dir2 := writeReverseTree(t)
+	nav, state := newReverseWalk(t, dir1, dir2, "")
+
+	step := NewTreeWalker(nav, state, "m").Run(nil)
+	if step.Type != StepFoundDiff || step.Name != "x.txt" {
+		t.Fatalf("expected sub/x.txt after m/, got %+v", step)
+	}
+}
@@ -802,6 +802,47 @@ func TestTreeWalkerResumeFromSubdir_REQ_DIFF_SEARCH(t *testing.T) {
 	}
 }
 
+// TestTreeWalkerCommonSubdirBeforeDiffering_REQ_DIFF_SEARCH tests that a common subdirectory
+// sorting before a differing one is searched before the differing one is reported.
+func TestTreeWalkerCommonSubdirBeforeDiffering_REQ_DIFF_SEARCH(t *testing.T) {
+	tmpDir := t.TempDir()
+	dir1Path := filepath.Join(tmpDir, "dir1")
+	dir2Path := filepath.Join(tmpDir, "dir2")
+	// a exists in both windows and holds a difference; b is missing in dir2
+	os.MkdirAll(filepath.Join(dir1Path, "a"), 0755)
+	os.MkdirAll(filepath.Join(dir2Path, "a"), 0755)
+	os.MkdirAll(filepath.Join(dir1Path, "b"), 0755)
+	os.WriteFile(filepath.Join(dir1Path, "a", "x.txt"), []byte("x1"), 0644)
+	os.WriteFile(filepath.Join(dir2Path, "a", "x.txt"), []byte("x22"), 0644)
+
+	nav, err := NewBatchNavigator([]string{dir1Path, dir2Path})
+	if err != nil {
+		t.Fatalf("NewBatchNavigator failed: %v", err)
+	}
+	state := NewDiffSearchState(nav.GetDirs())
+
+	step := NewTreeWalker(nav, state, "").Run(nil)
+	if step.Type != StepFoundDiff {
+		t.Fatalf("Expected StepFoundDiff, got %v", step.Type)
+	}
+	if step.Name != "x.txt" || nav.CurrentRelativePath() != "a" {
+		t.Fatalf("Expected first diff a/x.txt, got '%s' in '%s'", step.Name, nav.CurrentRelativePath())
+	}
+
+	step = NewTreeWalker(nav, state, step.Name).Run(nil)
+	if step.Type != StepFoundDiff {
+		t.Fatalf("Expected StepFoundDiff, got %v", step.Type)
+	}
+	if step.Name != "b/" || nav.CurrentRelativePath() != "" {
+		t.Errorf("Expected second diff b/ at the root, got '%s' in '%s'", step.Name, nav.CurrentRelativePath())
+	}
+
+	step = NewTreeWalker(nav, state, "b").Run(nil)
+	if step.Type != StepComplete {
+		t.Errorf("Expected StepComplete after b/, got %v (Name: %s)", step.Type, step.Name)
+	}
+}
+
 // =============================================================================
 // Batch Diff Report Tests
 // [IMPL:BATCH_DIFF_REPORT] [ARCH:BATCH_DIFF_REPORT] [REQ:BATCH_DIFF_REPORT]
@@ -6,7 +6,6 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
-	"strings"
 
 	"github.com/fareedst/goful/look"
 	"github.com/fareedst/goful/message"
@@ -22,6 +21,14 @@ type Directory struct {
 	finder  *Finder
 	Path    string   `json:"path"`
 	Sort    SortType `json:"sort_kind"`
+	// LinkGroup is the linked navigation group: "" (default), "a"-"z" or LinkIndependent.
+	LinkGroup string `json:"link_group,omitempty"` // [IMPL:LINK_GROUPS] [ARCH:LINKED_NAVIGATION] [REQ:LINK_GROUPS]
+	// View is the pane's own view settings; nil uses DefaultView.
+	View *DirectoryView `json:"view,omitempty"` // [IMPL:PANE_VIEW_SETTINGS] [ARCH:PANE_VIEW_SETTINGS] [REQ:PANE_VIEW_SETTINGS]
+	// MapRoot is the directory the workspace path mapping rules are relative to.
+	MapRoot string `json:"map_root,omitempty"` // [IMPL:LINKED_PATH_MAP] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_PATH_MAP]
+	// LinkRoot is the directory the pane had when it was linked; linked chdir moves relative to it.
+	LinkRoot string `json:"link_root,omitempty"` // [IMPL:LINKED_CHDIR] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_CHDIR]
 }
 
 // NewDirectory creates a new directory based on specified size and coordinates.
@@ -60,22 +67,19 @@ const (
 	SortMtimeRev SortType = "Time[$]"
 	SortExt      SortType = "Ext[^]"
 	SortExtRev   SortType = "Ext[$]"
-)
-
-var priorityDir = true
-
-// TogglePriority toggles the priority for sorting files.
-// The directory is prioritized in sorting if this is true.
-func TogglePriority() {
-	priorityDir = !priorityDir
-}
-
-var showHiddens = true
 
-// ToggleShowHiddens toggles the showing of hidden files.
-func ToggleShowHiddens() {
-	showHiddens = !showHiddens
-}
+	// [IMPL:SORT_ORDERS] [ARCH:SORT_ORDERS] [REQ:SORT_ORDERS]
+	SortNatural     SortType = "Natural[^]"
+	SortNaturalRev  SortType = "Natural[$]"
+	SortVersion     SortType = "Version[^]"
+	SortVersionRev  SortType = "Version[$]"
+	SortNameFold    SortType = "IName[^]"
+	SortNameFoldRev SortType = "IName[$]"
+	SortPerm        SortType = "Perm[^]"
+	SortPermRev     SortType = "Perm[$]"
+	SortOwner       SortType = "Owner[^]"
+	SortOwnerRev    SortType = "Owner[$]"
+)
 
 type reader interface {
 	Read(callback func(name string))
@@ -96,9 +100,6 @@ func (s defaultReader) Read(callback func(string)) {
 	for {
 		names, err := fd.Readdirnames(100)
 		for _, name := range names {
-			if !showHiddens && strings.HasPrefix(name, ".") {
-				continue
-			}
 			callback(name)
 		}
 
@@ -124,9 +125,6 @@ func (s globPattern) Read(callback func(name string)) {
 		return
 	}
 	for _, name := range matches {
-		if !showHiddens && strings.HasPrefix(name, ".") {
-			continue
-		}
 		callback(name)
 	}
 }
@@ -143,11 +141,6 @@ func (s globDirPattern) Read(callback func(string)) {
 			return nil
 		}
 		if ok, _ := filepath.Match(string(s), info.Name()); ok {
-			if !showHiddens {
-				if strings.HasPrefix(path, ".") || strings.HasPrefix(info.Name(), ".") {
-					return nil
-				}
-			}
 			callback(path)
 		}
 		return nil
@@ -255,6 +248,10 @@ func (d *Directory) read() {
 	}
 
 	callback := func(name string) {
+		if d.isHidden(name) {
+			// [IMPL:PANE_VIEW_SETTINGS] [ARCH:PANE_VIEW_SETTINGS] [REQ:PANE_VIEW_SETTINGS]
+			return
+		}
 		if shouldExcludeName(name) {
 			// [IMPL:FILER_EXCLUDE_RULES] [ARCH:FILER_EXCLUDE_FILTER] [REQ:FILER_EXCLUDE_NAMES]
 			return
@@ -348,7 +345,7 @@ func (d *Directory) SortExtDec() { d.SortBy(SortExtRev) }
 
 // Less compares based on Sort.
 func (d *Directory) Less(i, j int) bool {
-	if priorityDir {
+	if d.ViewSettings().PriorityDir {
 		id := d.List()[i].(*FileStat).stat.IsDir()
 		jd := d.List()[j].(*FileStat).stat.IsDir()
 		if !(id && jd) && (id || jd) {
@@ -372,6 +369,26 @@ func (d *Directory) Less(i, j int) bool {
 		return d.lessExt(i, j)
 	case SortExtRev:
 		return d.lessExt(j, i)
+	case SortNatural:
+		return d.lessNames(i, j, compareNatural)
+	case SortNaturalRev:
+		return d.lessNames(j, i, compareNatural)
+	case SortVersion:
+		return d.lessNames(i, j, compareVersion)
+	case SortVersionRev:
+		return d.lessNames(j, i, compareVersion)
+	case SortNameFold:
+		return d.lessNames(i, j, compareFold)
+	case SortNameFoldRev:
+		return d.lessNames(j, i, compareFold)
+	case SortPerm:
+		return d.lessPerm(i, j)
+	case SortPermRev:
+		return d.lessPerm(j, i)
+	case SortOwner:
+		return d.lessOwner(i, j)
+	case SortOwnerRev:
+		return d.lessOwner(j, i)
 	}
 	return d.List()[i].Name() < d.List()[j].Name()
 }
@@ -564,7 +581,7 @@ func (d *Directory) drawFilesWithComparison(focus bool, dirIndex int, idx *Compa
 			cmp = idx.Get(dirIndex, fs.Name())
 		}
 
-		fs.DrawWithComparison(x, y, width, isFocused, cmp)
+		fs.DrawWithView(x, y, width, isFocused, cmp, d.View) // [IMPL:PANE_VIEW_SETTINGS] [ARCH:PANE_VIEW_SETTINGS] [REQ:PANE_VIEW_SETTINGS]
 		row++
 	}
 }
@@ -37,6 +37,17 @@ func ConfigureExcludedNames(names []string, activate bool) int {
 	return len(set)
 }
 
+// ReloadExcludedNames replaces the current exclude set and keeps whether the
+// filter is on, so a reload does not undo the user's toggle or the workspace
+// setting. A set loaded where there was none is activated, as at startup.
+// [IMPL:FILER_EXCLUDE_RULES] [IMPL:CONFIG_RELOAD] [ARCH:FILER_EXCLUDE_FILTER] [REQ:CONFIG_RELOAD]
+func ReloadExcludedNames(names []string) int {
+	excludedNamesMu.RLock()
+	activate := excludeEnabled || len(excludedNames) == 0
+	excludedNamesMu.RUnlock()
+	return ConfigureExcludedNames(names, activate)
+}
+
 // ToggleExcludedNames flips the active state when rules exist.
 // Returns (enabled, hasRules, ruleCount).
 // [IMPL:FILER_EXCLUDE_RULES] [ARCH:FILER_EXCLUDE_FILTER] [REQ:FILER_EXCLUDE_NAMES]
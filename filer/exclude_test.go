@@ -42,3 +42,28 @@ func TestToggleExcludedNames_REQ_FILER_EXCLUDE_NAMES(t *testing.T) {
 		t.Fatalf("second toggle should re-enable the filter (enabled=%v, hasRules=%v)", enabled, hasRules)
 	}
 }
+
+func TestReloadExcludedNamesKeepsState_REQ_CONFIG_RELOAD(t *testing.T) {
+	// [REQ:CONFIG_RELOAD] [ARCH:FILER_EXCLUDE_FILTER] [IMPL:FILER_EXCLUDE_RULES]
+	t.Cleanup(resetExcludesForTest)
+
+	if ReloadExcludedNames([]string{"a.tmp"}); !ExcludedNamesEnabled() {
+		t.Fatalf("names loaded where there were none should be active")
+	}
+	ToggleExcludedNames()
+	if ReloadExcludedNames([]string{"a.tmp", "b.tmp"}); ExcludedNamesEnabled() || !shouldExcludeNameIgnoringState("b.tmp") {
+		t.Fatalf("reload should keep the filter off and load the new names")
+	}
+	ToggleExcludedNames()
+	if ReloadExcludedNames([]string{"c.tmp"}); !ExcludedNamesEnabled() || !shouldExcludeName("c.tmp") {
+		t.Fatalf("reload should keep the filter on")
+	}
+}
+
+// shouldExcludeNameIgnoringState reports whether name is in the exclude set.
+func shouldExcludeNameIgnoringState(name string) bool {
+	excludedNamesMu.RLock()
+	defer excludedNamesMu.RUnlock()
+	_, ok := excludedNames[name]
+	return ok
+}
@@ -13,26 +13,6 @@ import (
 	"github.com/mattn/go-runewidth"
 )
 
-var statView = fileStatView{true, true, true}
-
-type fileStatView struct {
-	size       bool
-	permission bool
-	time       bool
-}
-
-// SetStatView sets the file state view.
-func SetStatView(size, permission, time bool) { statView = fileStatView{size, permission, time} }
-
-// ToggleSizeView toggles the file size view.
-func ToggleSizeView() { statView.size = !statView.size }
-
-// TogglePermView toggles the file permission view.
-func TogglePermView() { statView.permission = !statView.permission }
-
-// ToggleTimeView toggles the file time view.
-func ToggleTimeView() { statView.time = !statView.time }
-
 var timeFormat = "06-01-02 15:04"
 
 // SetTimeFormat sets the time format of files.
@@ -185,24 +165,6 @@ func (f *FileStat) suffix() string {
 	return ""
 }
 
-func (f *FileStat) states() string {
-	ret := f.Ext()
-	if statView.size {
-		if f.stat.IsDir() {
-			ret += fmt.Sprintf("%8s", "<DIR>")
-		} else {
-			ret += fmt.Sprintf("%8s", util.FormatSize(f.stat.Size()))
-		}
-	}
-	if statView.permission {
-		ret += " " + f.stat.Mode().String()
-	}
-	if statView.time {
-		ret += " " + f.stat.ModTime().Format(timeFormat)
-	}
-	return ret
-}
-
 func (f *FileStat) look() tcell.Style {
 	switch {
 	case f.IsMarked():
@@ -226,9 +188,21 @@ func (f *FileStat) Draw(x, y, width int, focus bool) {
 	f.DrawWithComparison(x, y, width, focus, nil)
 }
 
-// DrawWithComparison draws the file with optional comparison coloring.
+// DrawWithComparison draws the file with optional comparison coloring and
+// the stat columns of DefaultView.
 // [IMPL:COMPARISON_DRAW] [ARCH:FILE_COMPARISON_ENGINE] [REQ:FILE_COMPARISON_COLORS]
 func (f *FileStat) DrawWithComparison(x, y, width int, focus bool, cmp *CompareState) {
+	f.DrawWithView(x, y, width, focus, cmp, nil)
+}
+
+// DrawWithView draws the file with optional comparison coloring and the stat
+// columns of view, the directory's view; nil uses DefaultView.
+// [IMPL:COMPARISON_DRAW] [IMPL:PANE_VIEW_SETTINGS] [ARCH:FILE_COMPARISON_ENGINE] [REQ:FILE_COMPARISON_COLORS]
+func (f *FileStat) DrawWithView(x, y, width int, focus bool, cmp *CompareState, view *DirectoryView) {
+	if view == nil {
+		v := DefaultView()
+		view = &v
+	}
 	baseStyle := f.look()
 	if focus {
 		baseStyle = baseStyle.Reverse(true)
@@ -240,7 +214,7 @@ func (f *FileStat) DrawWithComparison(x, y, width int, focus bool, cmp *CompareS
 	// Calculate widths for each section
 	ext := f.Ext()
 	var sizeStr, timeStr string
-	if statView.size {
+	if view.StatSize {
 		if f.stat.IsDir() {
 			sizeStr = fmt.Sprintf("%8s", "<DIR>")
 		} else {
@@ -248,10 +222,10 @@ func (f *FileStat) DrawWithComparison(x, y, width int, focus bool, cmp *CompareS
 		}
 	}
 	permStr := ""
-	if statView.permission {
+	if view.StatPermission {
 		permStr = " " + f.stat.Mode().String()
 	}
-	if statView.time {
+	if view.StatTime {
 		timeStr = " " + f.stat.ModTime().Format(timeFormat)
 	}
 
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"unicode/utf8"
 
+	"github.com/fareedst/goful/action"
 	"github.com/fareedst/goful/look"
 	"github.com/fareedst/goful/message"
 	"github.com/fareedst/goful/util"
@@ -22,6 +23,7 @@ type Filer struct {
 	*widget.Window
 	keymap     widget.Keymap
 	extmap     widget.Extmap
+	associate  func(*FileStat) bool
 	Workspaces []*Workspace `json:"workspaces"`
 	Current    int          `json:"current"`
 }
@@ -310,6 +312,8 @@ func NewFromState(path string, x, y, width, height int) *Filer {
 
 // SaveState saves the filer state to the file.
 func (f *Filer) SaveState(path string) error {
+	// [IMPL:WORKSPACE_SETTINGS] [ARCH:WORKSPACE_SETTINGS] [REQ:WORKSPACE_SETTINGS]
+	f.Workspace().SaveSettings()
 	jsondata, err := json.MarshalIndent(f, "", "  ")
 	if err != nil {
 		return err
@@ -361,19 +365,19 @@ func (f *Filer) CloseWorkspace() {
 	if f.Current > len(f.Workspaces)-1 {
 		f.Current = len(f.Workspaces) - 1
 	}
-	f.Workspace().visible(true)
+	f.enterWorkspace()
 }
 
 // MoveWorkspace moves to the other workspace.
 func (f *Filer) MoveWorkspace(amount int) {
-	f.Workspace().visible(false)
+	f.leaveWorkspace()
 	f.Current += amount
 	if f.Current >= len(f.Workspaces) {
 		f.Current = 0
 	} else if f.Current < 0 {
 		f.Current = len(f.Workspaces) - 1
 	}
-	f.Workspace().visible(true)
+	f.enterWorkspace()
 }
 
 // SwitchToWorkspace switches directly to the workspace at the given index.
@@ -382,9 +386,9 @@ func (f *Filer) SwitchToWorkspace(index int) {
 	if index < 0 || index >= len(f.Workspaces) || index == f.Current {
 		return
 	}
-	f.Workspace().visible(false)
+	f.leaveWorkspace()
 	f.Current = index
-	f.Workspace().visible(true)
+	f.enterWorkspace()
 }
 
 // Workspace returns the current workspace.
@@ -402,7 +406,9 @@ func (f *Filer) File() *FileStat {
 	return f.Dir().File()
 }
 
-// AddKeymap adds to the filer keymap.
+// AddKeymap adds to the filer keymap as key and callback, where the callback
+// is a function or a registered action name.
+// [IMPL:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [REQ:ACTION_REGISTRY]
 func (f *Filer) AddKeymap(keys ...interface{}) {
 	if len(keys)%2 != 0 {
 		panic("items must be a multiple of 2")
@@ -410,8 +416,7 @@ func (f *Filer) AddKeymap(keys ...interface{}) {
 
 	for i := 0; i < len(keys); i += 2 {
 		key := keys[i].(string)
-		callback := keys[i+1].(func())
-		f.keymap[key] = callback
+		f.keymap[key] = action.Callback(keys[i+1])
 	}
 }
 
@@ -422,6 +427,31 @@ func (f *Filer) MergeKeymap(m widget.Keymap) {
 	}
 }
 
+// RemoveKeymap removes keys from the filer keymap.
+// [IMPL:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [REQ:USER_KEYMAPS]
+func (f *Filer) RemoveKeymap(keys ...string) {
+	for _, key := range keys {
+		delete(f.keymap, key)
+	}
+}
+
+// Keymap returns a copy of the filer keymap.
+// [IMPL:CONFIG_RELOAD] [ARCH:CONFIG_RELOAD] [REQ:CONFIG_RELOAD]
+func (f *Filer) Keymap() widget.Keymap {
+	m := make(widget.Keymap, len(f.keymap))
+	for key, callback := range f.keymap {
+		m[key] = callback
+	}
+	return m
+}
+
+// SetKeymap replaces the filer keymap with a copy of m.
+// [IMPL:CONFIG_RELOAD] [ARCH:CONFIG_RELOAD] [REQ:CONFIG_RELOAD]
+func (f *Filer) SetKeymap(m widget.Keymap) {
+	f.keymap = widget.Keymap{}
+	f.MergeKeymap(m)
+}
+
 // AddExtmap adds to the filer extmap.
 // [IMPL:EXTMAP_API_SAFETY] [ARCH:DEBT_MANAGEMENT] [REQ:DEBT_TRIAGE]
 // Safe for third-party integrations: allocates inner map if missing.
@@ -454,6 +484,41 @@ func (f *Filer) MergeExtmap(m widget.Extmap) {
 	}
 }
 
+// SetAssociation sets the handler Open consults before the extension
+// callbacks. It reports whether it opened the file.
+// [IMPL:FILE_ASSOCIATIONS] [ARCH:FILE_ASSOCIATIONS] [REQ:FILE_ASSOCIATIONS]
+func (f *Filer) SetAssociation(fn func(*FileStat) bool) {
+	f.associate = fn
+}
+
+// Open opens the file under the cursor with the callbacks of exts keyed like
+// an extmap entry: ".dir" for directories, ".exec" for executables, then the
+// handler set by SetAssociation and last the file extension. It reports
+// whether anything opened the file.
+// [IMPL:FILE_ASSOCIATIONS] [ARCH:FILE_ASSOCIATIONS] [REQ:FILE_ASSOCIATIONS]
+func (f *Filer) Open(exts map[string]func()) bool {
+	file := f.File()
+	if file.IsDir() || file.stat.IsDir() {
+		if callback, ok := exts[".dir"]; ok {
+			callback()
+			return true
+		}
+		return false
+	}
+	if callback, ok := exts[".exec"]; ok && file.IsExec() {
+		callback()
+		return true
+	}
+	if f.associate != nil && f.associate(file) {
+		return true
+	}
+	if callback, ok := exts[file.Ext()]; ok {
+		callback()
+		return true
+	}
+	return false
+}
+
 // Input for key events.
 func (f *Filer) Input(key string) {
 	if finder := f.Dir().finder; finder != nil {
@@ -587,7 +652,8 @@ func (f *Filer) drawHeader() {
 		if ws.Focus == i {
 			style = style.Reverse(true)
 		}
-		s := fmt.Sprintf("[%d] ", i+1)
+		// [IMPL:LINK_GROUPS] [ARCH:LINKED_NAVIGATION] [REQ:LINK_GROUPS]
+		s := fmt.Sprintf("[%d%s] ", i+1, ws.Dirs[i].LinkGroup)
 		x = widget.SetCells(x, y, s, style)
 		w := dirWidth - len(s)
 		if w < 1 {
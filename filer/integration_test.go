@@ -4,7 +4,10 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"testing"
+
+	"github.com/fareedst/goful/widget"
 )
 
 func newTestDirectory(t *testing.T, path string) *Directory {
@@ -573,3 +576,51 @@ func TestSetCursorByNameAllFocusUnchanged_REQ_MOUSE_CROSS_WINDOW_SYNC(t *testing
 		t.Errorf("focus changed from 1 to %d after SetCursorByNameAll", ws.Focus)
 	}
 }
+
+// TestSetAssociation_REQ_FILE_ASSOCIATIONS verifies that Open runs the association
+// handler before the extension callbacks, that unhandled files fall back to them,
+// and that other extmap keys never consult the handler.
+// [IMPL:FILE_ASSOCIATIONS] [ARCH:FILE_ASSOCIATIONS] [REQ:FILE_ASSOCIATIONS]
+func TestSetAssociation_REQ_FILE_ASSOCIATIONS(t *testing.T) {
+	tmp := t.TempDir()
+	for _, name := range []string{"a.dat", "b.txt", "c.bin"} {
+		os.WriteFile(filepath.Join(tmp, name), nil, 0o644)
+	}
+	ws := NewWorkspace(0, 0, 100, 40, "test")
+	ws.Dirs = append(ws.Dirs, newTestDirectory(t, tmp))
+
+	calls := []string{}
+	exts := map[string]func(){
+		".txt": func() { calls = append(calls, "ext") },
+		".dat": func() { calls = append(calls, "ext") },
+	}
+	f := &Filer{
+		Workspaces: []*Workspace{ws},
+		keymap:     widget.Keymap{},
+		extmap:     widget.Extmap{"x": exts},
+	}
+	f.SetAssociation(func(file *FileStat) bool {
+		if file.Ext() != ".dat" {
+			return false
+		}
+		calls = append(calls, "assoc "+file.Name())
+		return true
+	})
+
+	for _, name := range []string{"a.dat", "b.txt", "c.bin"} {
+		ws.Dir().SetCursorByName(name)
+		if !f.Open(exts) {
+			calls = append(calls, "opener")
+		}
+	}
+	if got := strings.Join(calls, ","); got != "assoc a.dat,ext,opener" {
+		t.Errorf("calls = %s, want assoc a.dat,ext,opener", got)
+	}
+
+	calls = calls[:0]
+	ws.Dir().SetCursorByName("a.dat")
+	f.Input("x")
+	if got := strings.Join(calls, ","); got != "ext" {
+		t.Errorf("extmap key calls = %s, want ext", got)
+	}
+}
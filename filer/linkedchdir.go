@@ -0,0 +1,91 @@
+package filer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fareedst/goful/util"
+)
+
+// ChdirAllRelativeNoRebuild moves the other panes of the focused link group
+// to target by its path relative to the focused pane's root, applied under
+// each pane's own root. A pane's root is its map root when path mapping rules
+// are set and otherwise the directory it had when it was linked. The path
+// mapping rules apply to target relative to the map roots, and otherwise to
+// each downward step. It returns the root relative path and the indexes of
+// panes that could not follow; those panes stay where they are. The focused
+// pane is not moved, and an error is returned if target is not a directory.
+// Does NOT rebuild the comparison index.
+// [IMPL:LINKED_CHDIR] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_CHDIR]
+func (w *Workspace) ChdirAllRelativeNoRebuild(target string) (rel string, missed []int, err error) {
+	target = filepath.Clean(util.ExpandPath(target))
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(w.Dir().Path, target)
+	}
+	if info, err := os.Stat(target); err != nil {
+		return "", nil, err
+	} else if !info.IsDir() {
+		return "", nil, fmt.Errorf("%s is not a directory", target)
+	}
+	for _, d := range w.Dirs {
+		if d.LinkRoot == "" {
+			d.LinkRoot = d.Path
+		}
+	}
+	if rel, err = filepath.Rel(linkRoot(w.Dir()), target); err != nil {
+		return "", nil, err
+	}
+	for i, d := range w.Dirs {
+		if i == w.Focus || !w.IsLinkedTo(i) {
+			continue
+		}
+		if path, ok := w.rootSubdir(d, target); ok {
+			d.Chdir(path)
+		} else if path, ok := w.resolveRelative(linkRoot(d), rel); ok {
+			d.Chdir(path)
+		} else {
+			missed = append(missed, i)
+		}
+	}
+	return rel, missed, nil
+}
+
+// SetLinkRoots makes the current directory of every pane the root that
+// linked chdir moves relative to.
+// [IMPL:LINKED_CHDIR] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_CHDIR]
+func (w *Workspace) SetLinkRoots() {
+	for _, d := range w.Dirs {
+		d.LinkRoot = d.Path
+	}
+}
+
+// linkRoot returns the root of d for linked chdir: its map root, else the
+// directory it was linked in.
+func linkRoot(d *Directory) string {
+	if d.MapRoot != "" {
+		return d.MapRoot
+	}
+	return d.LinkRoot
+}
+
+// resolveRelative applies rel to dir one step at a time and reports whether
+// every step exists as a directory.
+func (w *Workspace) resolveRelative(dir, rel string) (string, bool) {
+	if rel == "." {
+		return dir, true
+	}
+	for _, step := range strings.Split(rel, string(filepath.Separator)) {
+		if step == ".." {
+			dir = filepath.Dir(dir)
+			continue
+		}
+		p, ok := w.mappedSubdirOf(dir, step)
+		if !ok {
+			return "", false
+		}
+		dir = filepath.Join(dir, p)
+	}
+	return dir, true
+}
@@ -0,0 +1,109 @@
+package filer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestChdirAllRelative_REQ_LINKED_CHDIR tests that an absolute jump in the focused pane is
+// replayed as a relative move in the other linked panes.
+// [REQ:LINKED_CHDIR] [ARCH:LINKED_NAVIGATION] [IMPL:LINKED_CHDIR]
+func TestChdirAllRelative_REQ_LINKED_CHDIR(t *testing.T) {
+	var roots []string
+	for _, deep := range []string{"a/b/c/d/e", "a/b/c/d/e", "a/b/x", "a/source/c"} {
+		tmp := t.TempDir()
+		os.MkdirAll(filepath.Join(tmp, deep), 0o755)
+		roots = append(roots, filepath.Clean(tmp))
+	}
+	ws := NewWorkspace(0, 0, 100, 40, "test")
+	for _, root := range roots {
+		ws.Dirs = append(ws.Dirs, newTestDirectory(t, root))
+	}
+	ws.Focus = 0
+
+	target := filepath.Join(roots[0], "a", "b", "c", "d", "e")
+	rel, missed, err := ws.ChdirAllRelativeNoRebuild(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rel != filepath.Join("a", "b", "c", "d", "e") || !reflect.DeepEqual(missed, []int{2, 3}) {
+		t.Errorf("rel = %s, missed = %v", rel, missed)
+	}
+	if ws.Dirs[1].Path != filepath.Join(roots[1], rel) {
+		t.Errorf("pane 2 = %s", ws.Dirs[1].Path)
+	}
+	if ws.Dirs[0].Path != roots[0] || ws.Dirs[2].Path != roots[2] {
+		t.Errorf("focused or missed panes moved: %s, %s", ws.Dirs[0].Path, ws.Dirs[2].Path)
+	}
+
+	// Path mapping rules apply to each downward step; ".." steps go up.
+	ws.Dirs[1].Chdir(roots[1])
+	ws.AddPathMapRule(PathMapRule{From: "b", To: "source"})
+	if _, missed, _ = ws.ChdirAllRelativeNoRebuild("a/b/c"); !reflect.DeepEqual(missed, []int{2}) {
+		t.Errorf("mapped missed = %v", missed)
+	}
+	if ws.Dirs[3].Path != filepath.Join(roots[3], "a", "source", "c") {
+		t.Errorf("pane 4 = %s", ws.Dirs[3].Path)
+	}
+	ws.Dirs[0].Chdir(filepath.Join(roots[0], "a", "b", "c"))
+	if _, missed, _ = ws.ChdirAllRelativeNoRebuild("../.."); len(missed) != 0 {
+		t.Errorf("parent missed = %v", missed)
+	}
+	if ws.Dirs[3].Path != filepath.Join(roots[3], "a") {
+		t.Errorf("after ../..: pane 4 = %s", ws.Dirs[3].Path)
+	}
+
+	// A missing target moves nothing.
+	if _, _, err := ws.ChdirAllRelativeNoRebuild("nope"); err == nil {
+		t.Error("missing target should fail")
+	}
+
+	// Panes outside the focused link group never follow.
+	ws.Dirs[1].LinkGroup = LinkIndependent
+	before := ws.Dirs[1].Path
+	ws.ChdirAllRelativeNoRebuild(filepath.Join(roots[0], "a", "b", "c", "d"))
+	if ws.Dirs[1].Path != before {
+		t.Errorf("independent pane moved to %s", ws.Dirs[1].Path)
+	}
+}
+
+// TestChdirAllRelativeFromRoots_REQ_LINKED_CHDIR tests that a jump moves each pane by the
+// target's path relative to the focused pane's root, whatever depth the panes are at.
+// [REQ:LINKED_CHDIR] [ARCH:LINKED_NAVIGATION] [IMPL:LINKED_CHDIR]
+func TestChdirAllRelativeFromRoots_REQ_LINKED_CHDIR(t *testing.T) {
+	var bases []string
+	for i := 0; i < 2; i++ {
+		tmp := filepath.Clean(t.TempDir())
+		os.MkdirAll(filepath.Join(tmp, "root", "x", "y"), 0o755)
+		os.MkdirAll(filepath.Join(tmp, "other"), 0o755)
+		bases = append(bases, tmp)
+	}
+	ws := NewWorkspace(0, 0, 100, 40, "test")
+	for _, base := range bases {
+		ws.Dirs = append(ws.Dirs, newTestDirectory(t, filepath.Join(base, "root")))
+	}
+	ws.Focus = 0
+	ws.SetLinkRoots()
+	ws.Dirs[0].Chdir(filepath.Join(bases[0], "root", "x", "y"))
+	ws.Dirs[1].Chdir(filepath.Join(bases[1], "root", "x"))
+
+	rel, missed, err := ws.ChdirAllRelativeNoRebuild(filepath.Join(bases[0], "other"))
+	if err != nil || len(missed) != 0 {
+		t.Fatalf("rel = %s, missed = %v, err = %v", rel, missed, err)
+	}
+	if want := filepath.Join("..", "other"); rel != want {
+		t.Errorf("rel = %s, want %s", rel, want)
+	}
+	if want := filepath.Join(bases[1], "other"); ws.Dirs[1].Path != want {
+		t.Errorf("pane 2 = %s, want %s", ws.Dirs[1].Path, want)
+	}
+
+	if _, missed, _ = ws.ChdirAllRelativeNoRebuild(filepath.Join(bases[0], "root", "x", "y")); len(missed) != 0 {
+		t.Errorf("missed = %v", missed)
+	}
+	if want := filepath.Join(bases[1], "root", "x", "y"); ws.Dirs[1].Path != want {
+		t.Errorf("pane 2 = %s, want %s", ws.Dirs[1].Path, want)
+	}
+}
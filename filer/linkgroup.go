@@ -0,0 +1,85 @@
+package filer
+
+import (
+	"fmt"
+)
+
+// LinkIndependent is the link group of a pane that is never linked.
+// [IMPL:LINK_GROUPS] [ARCH:LINKED_NAVIGATION] [REQ:LINK_GROUPS]
+const LinkIndependent = "-"
+
+// ParseLinkGroup validates a link group name: empty for the default group
+// shared by all ungrouped panes, a single letter a-z, or "-" for independent.
+// [IMPL:LINK_GROUPS] [ARCH:LINKED_NAVIGATION] [REQ:LINK_GROUPS]
+func ParseLinkGroup(s string) (string, error) {
+	switch {
+	case s == "" || s == LinkIndependent:
+		return s, nil
+	case len(s) == 1 && s[0] >= 'a' && s[0] <= 'z':
+		return s, nil
+	}
+	return "", fmt.Errorf("link group must be a letter a-z, - for independent or empty for default: %s", s)
+}
+
+// IsLinkedTo reports whether pane i follows the focused pane in linked
+// navigation, i.e. whether both are in the same link group.
+// [IMPL:LINK_GROUPS] [ARCH:LINKED_NAVIGATION] [REQ:LINK_GROUPS]
+func (w *Workspace) IsLinkedTo(i int) bool {
+	if i == w.Focus {
+		return true
+	}
+	group := w.Dir().LinkGroup
+	return group != LinkIndependent && w.Dirs[i].LinkGroup == group
+}
+
+// LinkedCount returns the number of panes, including the focused one, in the focused pane's link group.
+// [IMPL:LINK_GROUPS] [ARCH:LINKED_NAVIGATION] [REQ:LINK_GROUPS]
+func (w *Workspace) LinkedCount() int {
+	n := 0
+	for i := range w.Dirs {
+		if w.IsLinkedTo(i) {
+			n++
+		}
+	}
+	return n
+}
+
+// SetCursorByNameLinked moves the cursor to the named entry, or its path
+// mapping, in every pane of the focused pane's link group.
+// [IMPL:LINK_GROUPS] [ARCH:LINKED_NAVIGATION] [REQ:LINK_GROUPS]
+func (w *Workspace) SetCursorByNameLinked(name string) {
+	for i, d := range w.Dirs {
+		if w.IsLinkedTo(i) {
+			d.setCursorByMappedName(name, w.mappedNames(d, name))
+		}
+	}
+}
+
+// UnlinkGroup makes the panes of the focused pane's link group independent,
+// leaving the other groups linked, and returns the group's name. It is used
+// when a linked chdir could not be followed by every pane of the group.
+// [IMPL:LINK_GROUPS] [IMPL:LINKED_NAVIGATION_AUTO_DISABLE] [ARCH:LINKED_NAVIGATION] [REQ:LINK_GROUPS]
+func (w *Workspace) UnlinkGroup() string {
+	group := w.Dir().LinkGroup
+	if group == LinkIndependent {
+		return group
+	}
+	for _, d := range w.Dirs {
+		if d.LinkGroup == group {
+			d.LinkGroup = LinkIndependent
+		}
+	}
+	return group
+}
+
+// HasLinkGroups reports whether any pane is assigned to a named link group
+// or is independent.
+// [IMPL:LINK_GROUPS] [ARCH:LINKED_NAVIGATION] [REQ:LINK_GROUPS]
+func (w *Workspace) HasLinkGroups() bool {
+	for _, d := range w.Dirs {
+		if d.LinkGroup != "" {
+			return true
+		}
+	}
+	return false
+}
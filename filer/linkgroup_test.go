@@ -0,0 +1,152 @@
+package filer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newLinkGroupWorkspace builds five panes, each in a temp dir holding "sub/" and "zz",
+// grouped as 1+2 in "a", 3+4 in "b" and 5 independent.
+func newLinkGroupWorkspace(t *testing.T) (*Workspace, []string) {
+	t.Helper()
+	ws := NewWorkspace(0, 0, 100, 40, "test")
+	var paths []string
+	for _, group := range []string{"a", "a", "b", "b", LinkIndependent} {
+		tmp := t.TempDir()
+		os.Mkdir(filepath.Join(tmp, "sub"), 0o755)
+		os.WriteFile(filepath.Join(tmp, "zz"), nil, 0o644)
+		paths = append(paths, filepath.Clean(tmp))
+		d := newTestDirectory(t, tmp)
+		d.LinkGroup = group
+		ws.Dirs = append(ws.Dirs, d)
+	}
+	ws.Focus = 0
+	return ws, paths
+}
+
+// TestParseLinkGroup_REQ_LINK_GROUPS tests link group validation.
+// [REQ:LINK_GROUPS] [ARCH:LINKED_NAVIGATION] [IMPL:LINK_GROUPS]
+func TestParseLinkGroup_REQ_LINK_GROUPS(t *testing.T) {
+	for _, s := range []string{"", "a", "z", LinkIndependent} {
+		if got, err := ParseLinkGroup(s); err != nil || got != s {
+			t.Errorf("ParseLinkGroup(%q) = %q, %v", s, got, err)
+		}
+	}
+	for _, s := range []string{"A", "1", "ab", "--"} {
+		if _, err := ParseLinkGroup(s); err == nil {
+			t.Errorf("ParseLinkGroup(%q) should fail", s)
+		}
+	}
+}
+
+// TestLinkGroupNavigation_REQ_LINK_GROUPS tests that linked subdirectory and parent
+// navigation only reach panes in the focused pane's group.
+// [REQ:LINK_GROUPS] [ARCH:LINKED_NAVIGATION] [IMPL:LINK_GROUPS]
+func TestLinkGroupNavigation_REQ_LINK_GROUPS(t *testing.T) {
+	ws, paths := newLinkGroupWorkspace(t)
+
+	if navigated, skipped := ws.ChdirAllToSubdirNoRebuild("sub"); navigated != 1 || skipped != 0 {
+		t.Errorf("group a: navigated=%d skipped=%d, want 1 and 0", navigated, skipped)
+	}
+	want := []string{paths[0], filepath.Join(paths[1], "sub"), paths[2], paths[3], paths[4]}
+	for i, d := range ws.Dirs {
+		if d.Path != want[i] {
+			t.Errorf("pane %d = %s, want %s", i+1, d.Path, want[i])
+		}
+	}
+
+	ws.Focus = 2
+	ws.ChdirAllToSubdir("sub")
+	if ws.Dirs[3].Path != filepath.Join(paths[3], "sub") || ws.Dirs[1].Path != filepath.Join(paths[1], "sub") {
+		t.Errorf("group b: pane 4 = %s, pane 2 = %s", ws.Dirs[3].Path, ws.Dirs[1].Path)
+	}
+	ws.ChdirAllToParent()
+	if ws.Dirs[3].Path != paths[3] || ws.Dirs[1].Path != filepath.Join(paths[1], "sub") {
+		t.Errorf("group b parent: pane 4 = %s, pane 2 = %s", ws.Dirs[3].Path, ws.Dirs[1].Path)
+	}
+
+	// An independent pane links to nothing.
+	ws.Focus = 4
+	if navigated, skipped := ws.ChdirAllToSubdirNoRebuild("sub"); navigated != 0 || skipped != 0 {
+		t.Errorf("independent: navigated=%d skipped=%d", navigated, skipped)
+	}
+	if ws.LinkedCount() != 1 {
+		t.Errorf("independent LinkedCount = %d", ws.LinkedCount())
+	}
+}
+
+// TestLinkGroupCursorAndSort_REQ_LINK_GROUPS tests that cursor sync and sort stay within the group.
+// [REQ:LINK_GROUPS] [ARCH:LINKED_NAVIGATION] [IMPL:LINK_GROUPS]
+func TestLinkGroupCursorAndSort_REQ_LINK_GROUPS(t *testing.T) {
+	ws, _ := newLinkGroupWorkspace(t)
+	for _, d := range ws.Dirs {
+		d.SetCursorByName("zz")
+	}
+
+	ws.SetCursorByNameLinked("sub")
+	for i, want := range []string{"sub", "sub", "zz", "zz", "zz"} {
+		if got := ws.Dirs[i].File().Name(); got != want {
+			t.Errorf("pane %d cursor = %s, want %s", i+1, got, want)
+		}
+	}
+
+	ws.SortAllBy(SortSizeRev)
+	for i, want := range []SortType{SortSizeRev, SortSizeRev, SortName, SortName, SortName} {
+		if ws.Dirs[i].Sort != want {
+			t.Errorf("pane %d sort = %v, want %v", i+1, ws.Dirs[i].Sort, want)
+		}
+	}
+
+	// Ungrouped panes keep the original all-panes behaviour.
+	for _, d := range ws.Dirs {
+		d.LinkGroup = ""
+	}
+	if ws.LinkedCount() != 5 {
+		t.Errorf("default group LinkedCount = %d", ws.LinkedCount())
+	}
+}
+
+// TestLinkGroupState_REQ_LINK_GROUPS tests that link groups are saved with the directory state.
+// [REQ:LINK_GROUPS] [ARCH:LINKED_NAVIGATION] [IMPL:LINK_GROUPS]
+func TestLinkGroupState_REQ_LINK_GROUPS(t *testing.T) {
+	d := NewDirectory(0, 0, 10, 10)
+	d.LinkGroup = "b"
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded Directory
+	if err := json.Unmarshal(data, &loaded); err != nil || loaded.LinkGroup != "b" {
+		t.Errorf("loaded LinkGroup = %q, %v", loaded.LinkGroup, err)
+	}
+}
+
+// TestUnlinkGroup_REQ_LINK_GROUPS tests that unlinking a diverged group leaves the other groups linked.
+// [REQ:LINK_GROUPS] [ARCH:LINKED_NAVIGATION] [IMPL:LINK_GROUPS]
+func TestUnlinkGroup_REQ_LINK_GROUPS(t *testing.T) {
+	ws, _ := newLinkGroupWorkspace(t)
+	if !ws.HasLinkGroups() {
+		t.Fatal("grouped workspace should report link groups")
+	}
+	if group := ws.UnlinkGroup(); group != "a" {
+		t.Errorf("UnlinkGroup = %q, want a", group)
+	}
+	for i, want := range []string{LinkIndependent, LinkIndependent, "b", "b", LinkIndependent} {
+		if got := ws.Dirs[i].LinkGroup; got != want {
+			t.Errorf("pane %d group = %q, want %q", i+1, got, want)
+		}
+	}
+	ws.Focus = 2
+	if ws.LinkedCount() != 2 {
+		t.Errorf("group b LinkedCount = %d, want 2", ws.LinkedCount())
+	}
+
+	for _, d := range ws.Dirs {
+		d.LinkGroup = ""
+	}
+	if ws.HasLinkGroups() {
+		t.Error("ungrouped workspace should not report link groups")
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+// +build !windows
+
+package filer
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+)
+
+// fileOwnerID returns the user id owning the file.
+// [IMPL:SORT_ORDERS] [ARCH:SORT_ORDERS] [REQ:SORT_ORDERS]
+func fileOwnerID(info os.FileInfo) string {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return strconv.FormatUint(uint64(st.Uid), 10)
+	}
+	return ""
+}
@@ -0,0 +1,12 @@
+//go:build windows
+// +build windows
+
+package filer
+
+import "os"
+
+// fileOwnerID returns "" because file ownership is not reported by os.FileInfo on Windows.
+// [IMPL:SORT_ORDERS] [ARCH:SORT_ORDERS] [REQ:SORT_ORDERS]
+func fileOwnerID(info os.FileInfo) string {
+	return ""
+}
@@ -0,0 +1,264 @@
+package filer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// PathMapRule rewrites a relative path for linked navigation so that panes
+// whose trees differ in naming stay linked. The path is relative to the pane's
+// MapRoot and uses forward slashes, so a rule may span several components. A
+// literal rule maps From to To and back; a regex rule replaces matches of From
+// with To (a regexp replacement).
+// [IMPL:LINKED_PATH_MAP] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_PATH_MAP]
+type PathMapRule struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Regex bool   `json:"regex,omitempty"`
+
+	re *regexp.Regexp // compiled From of a regex rule
+}
+
+// ParsePathMapRule parses "from=to" as a literal rule and "/regexp/=repl" as a regex rule.
+// [IMPL:LINKED_PATH_MAP] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_PATH_MAP]
+func ParsePathMapRule(s string) (PathMapRule, error) {
+	if strings.HasPrefix(s, "/") {
+		i := strings.LastIndex(s, "/=")
+		if i < 1 {
+			return PathMapRule{}, fmt.Errorf("regex rule must be like /regexp/=replacement: %s", s)
+		}
+		rule := PathMapRule{From: s[1:i], To: s[i+2:], Regex: true}
+		if err := rule.compile(); err != nil {
+			return PathMapRule{}, err
+		}
+		return rule, nil
+	}
+	from, to, ok := strings.Cut(s, "=")
+	if !ok || from == "" || to == "" || from == to {
+		return PathMapRule{}, fmt.Errorf("rule must be like from=to or /regexp/=replacement: %s", s)
+	}
+	return PathMapRule{From: from, To: to}, nil
+}
+
+// UnmarshalJSON decodes a rule from the state file and compiles its regexp.
+// A regexp that does not compile leaves the rule inert.
+func (r *PathMapRule) UnmarshalJSON(data []byte) error {
+	type plain PathMapRule
+	if err := json.Unmarshal(data, (*plain)(r)); err != nil {
+		return err
+	}
+	_ = r.compile()
+	return nil
+}
+
+// compile compiles the regexp of a regex rule once.
+func (r *PathMapRule) compile() error {
+	if !r.Regex || r.re != nil {
+		return nil
+	}
+	re, err := regexp.Compile(r.From)
+	if err != nil {
+		return err
+	}
+	r.re = re
+	return nil
+}
+
+// String returns the rule in the form accepted by ParsePathMapRule.
+func (r PathMapRule) String() string {
+	if r.Regex {
+		return "/" + r.From + "/=" + r.To
+	}
+	return r.From + "=" + r.To
+}
+
+// rewrite returns the relative path rewritten by the rule, if it applies.
+func (r PathMapRule) rewrite(rel string) (string, bool) {
+	if r.Regex {
+		if r.re == nil || !r.re.MatchString(rel) {
+			return "", false
+		}
+		return r.re.ReplaceAllString(rel, r.To), true
+	}
+	switch rel {
+	case r.From:
+		return r.To, true
+	case r.To:
+		return r.From, true
+	}
+	return "", false
+}
+
+// PathMapRules returns the mapping rules of the workspace.
+// [IMPL:LINKED_PATH_MAP] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_PATH_MAP]
+func (w *Workspace) PathMapRules() []PathMapRule {
+	return w.PathMap
+}
+
+// AddPathMapRule appends a mapping rule to the workspace. The first rule
+// makes the current directories of the panes their map roots.
+// [IMPL:LINKED_PATH_MAP] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_PATH_MAP]
+func (w *Workspace) AddPathMapRule(rule PathMapRule) {
+	_ = rule.compile()
+	if len(w.PathMap) == 0 {
+		w.SetPathMapRoots()
+	}
+	w.PathMap = append(w.PathMap, rule)
+}
+
+// ClearPathMap removes all mapping rules and map roots from the workspace.
+// [IMPL:LINKED_PATH_MAP] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_PATH_MAP]
+func (w *Workspace) ClearPathMap() {
+	w.PathMap = nil
+	for _, d := range w.Dirs {
+		d.MapRoot = ""
+	}
+}
+
+// SetPathMapRoots makes the current directory of every pane the root that
+// mapped paths are relative to.
+// [IMPL:LINKED_PATH_MAP] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_PATH_MAP]
+func (w *Workspace) SetPathMapRoots() {
+	for _, d := range w.Dirs {
+		d.MapRoot = d.Path
+	}
+}
+
+// MappedPaths returns rel followed by its rewrites under the workspace rules,
+// in rule order and without duplicates.
+// [IMPL:LINKED_PATH_MAP] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_PATH_MAP]
+func (w *Workspace) MappedPaths(rel string) []string {
+	paths := []string{rel}
+	for _, rule := range w.PathMap {
+		if p, ok := rule.rewrite(rel); ok && p != "" && !containsString(paths, p) {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// rootRel returns the slash-separated path of target, a path in the focused
+// pane, relative to the focused pane's map root. It fails without a root or
+// when target is outside it.
+func (w *Workspace) rootRel(target string) (string, bool) {
+	root := w.Dir().MapRoot
+	if root == "" {
+		return "", false
+	}
+	rel, err := filepath.Rel(root, target)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// rootTargets returns the paths in pane d that correspond to target, a path
+// in the focused pane: its root relative path and the rewrites of that path
+// joined to the map root of d.
+func (w *Workspace) rootTargets(d *Directory, target string) []string {
+	rel, ok := w.rootRel(target)
+	if !ok || d.MapRoot == "" {
+		return nil
+	}
+	var paths []string
+	for _, p := range w.MappedPaths(rel) {
+		paths = append(paths, filepath.Join(d.MapRoot, filepath.FromSlash(p)))
+	}
+	return paths
+}
+
+// rootSubdir resolves target, a directory in the focused pane, to a
+// directory in pane d one component at a time from the map root of d. At
+// each step the root relative path so far and its rewrites are tried first,
+// then the component and its rewrites under the directory resolved so far.
+func (w *Workspace) rootSubdir(d *Directory, target string) (string, bool) {
+	rel, ok := w.rootRel(target)
+	if !ok || d.MapRoot == "" {
+		return "", false
+	}
+	dir := d.MapRoot
+	steps := strings.Split(rel, "/")
+	for i, step := range steps {
+		next, found := "", false
+		for _, p := range w.MappedPaths(strings.Join(steps[:i+1], "/")) {
+			next = filepath.Join(d.MapRoot, filepath.FromSlash(p))
+			if info, err := os.Stat(next); err == nil && info.IsDir() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			p, ok := w.mappedSubdirOf(dir, step)
+			if !ok {
+				return "", false
+			}
+			next = filepath.Join(dir, p)
+		}
+		dir = next
+	}
+	return dir, true
+}
+
+// mappedSubdir returns the directory of pane d that corresponds to the
+// subdirectory name of the focused pane: a root target first, otherwise the
+// first mapped path of name that is a directory under d.
+func (w *Workspace) mappedSubdir(d *Directory, name string) (string, bool) {
+	if p, ok := w.rootSubdir(d, filepath.Join(w.Dir().Path, name)); ok {
+		return p, true
+	}
+	return w.mappedSubdirOf(d.Path, name)
+}
+
+// mappedSubdirOf returns the first of the mapped paths of rel that is a
+// directory under dir.
+func (w *Workspace) mappedSubdirOf(dir, rel string) (string, bool) {
+	for _, p := range w.MappedPaths(rel) {
+		if info, err := os.Stat(filepath.Join(dir, p)); err == nil && info.IsDir() {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// mappedNames returns the names in pane d to try for the entry name of the
+// focused pane: the root targets that lie in the directory of d, then name
+// and its rewrites.
+func (w *Workspace) mappedNames(d *Directory, name string) []string {
+	var names []string
+	for _, p := range w.rootTargets(d, filepath.Join(w.Dir().Path, name)) {
+		if filepath.Dir(p) == d.Path && !containsString(names, filepath.Base(p)) {
+			names = append(names, filepath.Base(p))
+		}
+	}
+	for _, n := range w.MappedPaths(name) {
+		if !containsString(names, n) {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// setCursorByMappedName moves the cursor to the first of names in d, hiding
+// the cursor under name when none exists.
+func (d *Directory) setCursorByMappedName(name string, names []string) {
+	for _, n := range names {
+		if d.IndexByName(n) != -1 {
+			d.SetCursorByName(n)
+			return
+		}
+	}
+	d.SetCursorByName(name)
+}
@@ -0,0 +1,167 @@
+package filer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestParsePathMapRule_REQ_LINKED_PATH_MAP tests parsing literal and regex mapping rules.
+// [REQ:LINKED_PATH_MAP] [ARCH:LINKED_NAVIGATION] [IMPL:LINKED_PATH_MAP]
+func TestParsePathMapRule_REQ_LINKED_PATH_MAP(t *testing.T) {
+	for in, want := range map[string]PathMapRule{
+		"src=source":          {From: "src", To: "source"},
+		`/^v1\.(\d+)$/=v2.$1`: {From: `^v1\.(\d+)$`, To: "v2.$1", Regex: true},
+		"/a=b/=c":             {From: "a=b", To: "c", Regex: true},
+	} {
+		got, err := ParsePathMapRule(in)
+		if err != nil || got.From != want.From || got.To != want.To || got.Regex != want.Regex {
+			t.Errorf("ParsePathMapRule(%q) = %+v, %v; want %+v", in, got, err, want)
+		}
+		if got.Regex && got.re == nil {
+			t.Errorf("ParsePathMapRule(%q) did not compile the regexp", in)
+		}
+		if got.String() != in {
+			t.Errorf("String() = %q, want %q", got.String(), in)
+		}
+	}
+	for _, in := range []string{"", "src", "=x", "x=", "x=x", "/(/=x", "/abc"} {
+		if _, err := ParsePathMapRule(in); err == nil {
+			t.Errorf("ParsePathMapRule(%q) should fail", in)
+		}
+	}
+}
+
+// TestMappedPaths_REQ_LINKED_PATH_MAP tests candidate order, literal reversal and de-duplication.
+// [REQ:LINKED_PATH_MAP] [ARCH:LINKED_NAVIGATION] [IMPL:LINKED_PATH_MAP]
+func TestMappedPaths_REQ_LINKED_PATH_MAP(t *testing.T) {
+	ws := NewWorkspace(0, 0, 80, 20, "test")
+	ws.AddPathMapRule(PathMapRule{From: "src", To: "source"})
+	ws.AddPathMapRule(PathMapRule{From: `^v1\.\d+$`, To: "v1.3", Regex: true})
+	ws.AddPathMapRule(PathMapRule{From: `^src$`, To: "source", Regex: true})
+
+	for rel, want := range map[string][]string{
+		"src":    {"src", "source"},
+		"source": {"source", "src"},
+		"v1.2":   {"v1.2", "v1.3"},
+		"docs":   {"docs"},
+	} {
+		if got := ws.MappedPaths(rel); !reflect.DeepEqual(got, want) {
+			t.Errorf("MappedPaths(%q) = %v, want %v", rel, got, want)
+		}
+	}
+	ws.ClearPathMap()
+	if got := ws.MappedPaths("src"); len(got) != 1 {
+		t.Errorf("after clear: %v", got)
+	}
+}
+
+// TestLinkedNavigationPathMap_REQ_LINKED_PATH_MAP tests that linked subdirectory navigation and
+// cursor sync follow mapping rules into differently named directories.
+// [REQ:LINKED_PATH_MAP] [ARCH:LINKED_NAVIGATION] [IMPL:LINKED_PATH_MAP]
+func TestLinkedNavigationPathMap_REQ_LINKED_PATH_MAP(t *testing.T) {
+	tmp1, tmp2, tmp3 := t.TempDir(), t.TempDir(), t.TempDir()
+	os.Mkdir(filepath.Join(tmp1, "src"), 0o755)
+	os.Mkdir(filepath.Join(tmp2, "source"), 0o755)
+	os.Mkdir(filepath.Join(tmp3, "other"), 0o755)
+
+	ws := NewWorkspace(0, 0, 80, 40, "test")
+	dir1, dir2, dir3 := newTestDirectory(t, tmp1), newTestDirectory(t, tmp2), newTestDirectory(t, tmp3)
+	ws.Dirs = []*Directory{dir1, dir2, dir3}
+	ws.Focus = 0
+
+	ws.SetCursorByNameAll("src")
+	if !dir2.IsCursorHidden() {
+		t.Errorf("without rules dir2 has no match and should hide its cursor")
+	}
+	if navigated, skipped := ws.ChdirAllToSubdirNoRebuild("src"); navigated != 0 || skipped != 2 {
+		t.Fatalf("without rules: navigated=%d skipped=%d", navigated, skipped)
+	}
+
+	ws.AddPathMapRule(PathMapRule{From: "src", To: "source"})
+	ws.SetCursorByNameAll("src")
+	if dir2.IsCursorHidden() || dir2.File().Name() != "source" {
+		t.Errorf("dir2 cursor = %q hidden=%v, want source", dir2.File().Name(), dir2.IsCursorHidden())
+	}
+	if !dir3.IsCursorHidden() {
+		t.Errorf("dir3 has no mapped entry and should hide its cursor")
+	}
+
+	navigated, skipped := ws.ChdirAllToSubdirNoRebuild("src")
+	if navigated != 1 || skipped != 1 {
+		t.Errorf("navigated=%d skipped=%d, want 1 and 1", navigated, skipped)
+	}
+	if dir2.Path != filepath.Join(tmp2, "source") || dir3.Path != filepath.Clean(tmp3) {
+		t.Errorf("dir2=%s dir3=%s", dir2.Path, dir3.Path)
+	}
+}
+
+// TestPathMapState_REQ_LINKED_PATH_MAP tests that mapping rules are saved with the workspace.
+// [REQ:LINKED_PATH_MAP] [ARCH:LINKED_NAVIGATION] [IMPL:LINKED_PATH_MAP]
+func TestPathMapState_REQ_LINKED_PATH_MAP(t *testing.T) {
+	ws := NewWorkspace(0, 0, 80, 20, "test")
+	ws.AddPathMapRule(PathMapRule{From: "a", To: "b"})
+	ws.AddPathMapRule(PathMapRule{From: "^x", To: "y", Regex: true})
+	data, err := json.Marshal(ws)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded Workspace
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	rules := loaded.PathMapRules()
+	if len(rules) != 2 || rules[0].String() != "a=b" || rules[1].String() != "/^x/=y" {
+		t.Errorf("loaded rules = %+v", rules)
+	}
+	if p, ok := rules[1].rewrite("xa"); !ok || p != "ya" {
+		t.Errorf("loaded regex rule rewrite = %q, %v", p, ok)
+	}
+}
+
+// TestPathMapRoots_REQ_LINKED_PATH_MAP tests that rules match the path relative to the
+// pane map roots, so a rule can span several components.
+// [REQ:LINKED_PATH_MAP] [ARCH:LINKED_NAVIGATION] [IMPL:LINKED_PATH_MAP]
+func TestPathMapRoots_REQ_LINKED_PATH_MAP(t *testing.T) {
+	tmp1, tmp2 := t.TempDir(), t.TempDir()
+	os.MkdirAll(filepath.Join(tmp1, "src", "v1", "deep"), 0o755)
+	os.MkdirAll(filepath.Join(tmp2, "source", "v1.3", "deep"), 0o755)
+
+	ws := NewWorkspace(0, 0, 80, 40, "test")
+	dir1, dir2 := newTestDirectory(t, tmp1), newTestDirectory(t, tmp2)
+	ws.Dirs = []*Directory{dir1, dir2}
+	ws.Focus = 0
+	ws.AddPathMapRule(PathMapRule{From: "src", To: "source"})
+	ws.AddPathMapRule(PathMapRule{From: `^src/(v\d+)$`, To: "source/$1.3", Regex: true})
+	if dir1.MapRoot != dir1.Path || dir2.MapRoot != dir2.Path {
+		t.Fatalf("map roots = %q, %q", dir1.MapRoot, dir2.MapRoot)
+	}
+
+	if navigated, _ := ws.ChdirAllToSubdirNoRebuild("src"); navigated != 1 {
+		t.Fatalf("entering src: navigated=%d", navigated)
+	}
+	dir1.Chdir("src")
+	ws.SetCursorByNameAll("v1")
+	if dir2.IsCursorHidden() || dir2.File().Name() != "v1.3" {
+		t.Errorf("dir2 cursor = %q hidden=%v, want v1.3", dir2.File().Name(), dir2.IsCursorHidden())
+	}
+	if navigated, _ := ws.ChdirAllToSubdirNoRebuild("v1"); navigated != 1 || dir2.Path != filepath.Join(tmp2, "source", "v1.3") {
+		t.Fatalf("entering v1: navigated=%d dir2=%s", navigated, dir2.Path)
+	}
+
+	dir1.Chdir(tmp1)
+	dir2.Chdir(tmp2)
+	if _, missed, err := ws.ChdirAllRelativeNoRebuild(filepath.Join(tmp1, "src", "v1", "deep")); err != nil || len(missed) != 0 {
+		t.Fatalf("relative jump: missed=%v err=%v", missed, err)
+	}
+	if dir2.Path != filepath.Join(tmp2, "source", "v1.3", "deep") {
+		t.Errorf("dir2 = %s", dir2.Path)
+	}
+
+	ws.ClearPathMap()
+	if dir1.MapRoot != "" || dir2.MapRoot != "" {
+		t.Errorf("ClearPathMap kept the map roots")
+	}
+}
@@ -0,0 +1,278 @@
+// Package filer reconcile plans for fixing a difference across windows.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+package filer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ReconcileSource selects which window's copy of an entry is propagated.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+type ReconcileSource int
+
+const (
+	ReconcileNewest  ReconcileSource = iota // Copy with the latest modification time
+	ReconcileLargest                        // Copy with the largest size
+	ReconcileFocused                        // Copy in the focused window
+)
+
+// String returns the source policy name shown in the plan preview.
+func (s ReconcileSource) String() string {
+	switch s {
+	case ReconcileLargest:
+		return "largest"
+	case ReconcileFocused:
+		return "focused"
+	default:
+		return "newest"
+	}
+}
+
+// Next returns the following source policy, wrapping around.
+func (s ReconcileSource) Next() ReconcileSource {
+	return (s + 1) % (ReconcileFocused + 1)
+}
+
+// ReconcileOptions controls how a reconcile plan is built.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+type ReconcileOptions struct {
+	Source       ReconcileSource
+	Focus        int  // 0-based focused window; used by ReconcileFocused and to break ties
+	DeleteExtras bool // Remove entries inside target directories that the source lacks
+}
+
+// ReconcileActionKind identifies the operation performed by a plan action.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+type ReconcileActionKind int
+
+const (
+	ReconcileCopy   ReconcileActionKind = iota // Target is missing: copy the source there
+	ReconcileUpdate                            // Target is older or differs: overwrite it
+	ReconcileDelete                            // Target holds an extra the source lacks
+)
+
+// String returns the action name shown in the plan preview.
+func (k ReconcileActionKind) String() string {
+	switch k {
+	case ReconcileUpdate:
+		return "update"
+	case ReconcileDelete:
+		return "delete"
+	default:
+		return "copy"
+	}
+}
+
+// ReconcileAction is one file operation of a reconcile plan.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+type ReconcileAction struct {
+	Kind   ReconcileActionKind
+	Window int    // 0-based target window
+	Src    string // Source path; empty for deletions
+	Dst    string // Target path
+	IsDir  bool
+}
+
+// ReconcileSkip records a target left untouched and why.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+type ReconcileSkip struct {
+	Window int // 0-based window
+	Path   string
+	Reason string
+}
+
+// ReconcilePlan is the previewable set of actions that makes every window
+// match the source window's copy of an entry.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+type ReconcilePlan struct {
+	Name    string
+	Source  int // 0-based source window
+	Options ReconcileOptions
+	Actions []ReconcileAction
+	Skipped []ReconcileSkip
+}
+
+// Sources returns the source paths of the copy and update actions.
+func (p *ReconcilePlan) Sources() []string {
+	var srcs []string
+	for _, a := range p.Actions {
+		if a.Kind != ReconcileDelete {
+			srcs = append(srcs, a.Src)
+		}
+	}
+	return srcs
+}
+
+// BuildReconcilePlan plans how to reconcile the named entry across dirs.
+// The source window is chosen by opts.Source from the windows holding the
+// entry, using the comparison index states; ties prefer the focused window,
+// then the lowest window number. Every other window where the entry is
+// missing, or holds an older or different copy, receives the source copy.
+// Directories present in both windows are compared recursively, so only
+// the differing files are copied. With opts.DeleteExtras, entries the source
+// lacks are deleted from the other windows, including the entry itself when
+// the focused source window does not hold it; without it they are listed as
+// skipped.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+func BuildReconcilePlan(dirs []*Directory, name string, opts ReconcileOptions) (*ReconcilePlan, error) {
+	if len(dirs) < 2 {
+		return nil, fmt.Errorf("reconcile requires at least 2 windows")
+	}
+	if opts.Source == ReconcileFocused && opts.Focus >= 0 && opts.Focus < len(dirs) &&
+		findEntryInDir(dirs[opts.Focus], name) == nil {
+		return buildReconcileRemoval(dirs, name, opts)
+	}
+	src, err := reconcileSourceWindow(dirs, name, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &ReconcilePlan{Name: name, Source: src, Options: opts}
+	srcPath := filepath.Join(dirs[src].Path, name)
+	srcInfo, err := os.Lstat(srcPath)
+	if err != nil {
+		return nil, err
+	}
+	for i, dir := range dirs {
+		if i == src || dir.Path == dirs[src].Path {
+			continue
+		}
+		if err := plan.add(i, srcPath, filepath.Join(dir.Path, name), srcInfo); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+// buildReconcileRemoval plans the removal of name from the other windows
+// when the focused source window lacks it.
+func buildReconcileRemoval(dirs []*Directory, name string, opts ReconcileOptions) (*ReconcilePlan, error) {
+	plan := &ReconcilePlan{Name: name, Source: opts.Focus, Options: opts}
+	for i, dir := range dirs {
+		if i == opts.Focus || dir.Path == dirs[opts.Focus].Path || findEntryInDir(dir, name) == nil {
+			continue
+		}
+		dst := filepath.Join(dir.Path, name)
+		info, err := os.Lstat(dst)
+		if err != nil {
+			return nil, err
+		}
+		plan.addExtra(i, dst, info.IsDir())
+	}
+	if len(plan.Actions) == 0 && len(plan.Skipped) == 0 {
+		return nil, fmt.Errorf("%s is not in any window", name)
+	}
+	return plan, nil
+}
+
+// addExtra plans the deletion of dst, which the source lacks, in window i,
+// or records it as skipped when extras are kept.
+func (p *ReconcilePlan) addExtra(i int, dst string, isDir bool) {
+	if p.Options.DeleteExtras {
+		p.Actions = append(p.Actions, ReconcileAction{ReconcileDelete, i, "", dst, isDir})
+	} else {
+		p.Skipped = append(p.Skipped, ReconcileSkip{i, dst, "not in the source"})
+	}
+}
+
+// reconcileSourceWindow picks the window whose copy of name is propagated.
+func reconcileSourceWindow(dirs []*Directory, name string, opts ReconcileOptions) (int, error) {
+	if opts.Source == ReconcileFocused {
+		if opts.Focus < 0 || opts.Focus >= len(dirs) || findEntryInDir(dirs[opts.Focus], name) == nil {
+			return 0, fmt.Errorf("%s is not in the focused window", name)
+		}
+		return opts.Focus, nil
+	}
+
+	idx := BuildComparisonIndex(dirs)
+	var holders, best []int
+	for i, dir := range dirs {
+		if findEntryInDir(dir, name) == nil {
+			continue
+		}
+		holders = append(holders, i)
+		state := idx.Get(i, name)
+		if state == nil {
+			continue
+		}
+		switch {
+		case opts.Source == ReconcileNewest && (state.TimeState == TimeLatest || state.TimeState == TimeEqual):
+			best = append(best, i)
+		case opts.Source == ReconcileLargest && (state.SizeState == SizeLargest || state.SizeState == SizeEqual):
+			best = append(best, i)
+		}
+	}
+	if len(holders) == 0 {
+		return 0, fmt.Errorf("%s is not in any window", name)
+	}
+	if len(best) == 0 {
+		best = holders // only one window holds it
+	}
+	for _, i := range best {
+		if i == opts.Focus {
+			return i, nil
+		}
+	}
+	return best[0], nil
+}
+
+// add plans the actions that make dst match src in window i.
+func (p *ReconcilePlan) add(i int, src, dst string, srcInfo os.FileInfo) error {
+	dstInfo, err := os.Lstat(dst)
+	if os.IsNotExist(err) {
+		p.Actions = append(p.Actions, ReconcileAction{ReconcileCopy, i, src, dst, srcInfo.IsDir()})
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	switch {
+	case srcInfo.IsDir() != dstInfo.IsDir() && p.Options.DeleteExtras:
+		p.Actions = append(p.Actions,
+			ReconcileAction{ReconcileDelete, i, "", dst, dstInfo.IsDir()},
+			ReconcileAction{ReconcileCopy, i, src, dst, srcInfo.IsDir()})
+	case srcInfo.IsDir() != dstInfo.IsDir():
+		p.Skipped = append(p.Skipped, ReconcileSkip{i, dst, "file/directory conflict"})
+	case srcInfo.IsDir():
+		return p.addDir(i, src, dst)
+	case dstInfo.ModTime().Truncate(time.Second).After(srcInfo.ModTime().Truncate(time.Second)):
+		p.Skipped = append(p.Skipped, ReconcileSkip{i, dst, "newer than the source"})
+	case !dstInfo.ModTime().Truncate(time.Second).Equal(srcInfo.ModTime().Truncate(time.Second)) ||
+		dstInfo.Size() != srcInfo.Size():
+		p.Actions = append(p.Actions, ReconcileAction{ReconcileUpdate, i, src, dst, false})
+	}
+	return nil
+}
+
+// addDir compares two directory trees, planning copies for what dst lacks or
+// holds older, and the extras dst holds.
+func (p *ReconcilePlan) addDir(i int, src, dst string) error {
+	srcEntries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(srcEntries))
+	for _, e := range srcEntries {
+		seen[e.Name()] = true
+		info, err := e.Info()
+		if err != nil {
+			return err
+		}
+		if err := p.add(i, filepath.Join(src, e.Name()), filepath.Join(dst, e.Name()), info); err != nil {
+			return err
+		}
+	}
+	dstEntries, err := os.ReadDir(dst)
+	if err != nil {
+		return err
+	}
+	for _, e := range dstEntries {
+		if !seen[e.Name()] {
+			p.addExtra(i, filepath.Join(dst, e.Name()), e.IsDir())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+// Package filer reconcile plan tests.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+package filer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeReconcileFile writes content to path with the given modification time.
+func writeReconcileFile(t *testing.T, path, content string, mtime time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newReconcileDirs creates n window roots and directories listing them.
+func newReconcileDirs(t *testing.T, n int) ([]string, func() []*Directory) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	roots := make([]string, n)
+	for i := range roots {
+		roots[i] = filepath.Join(tmpDir, string(rune('a'+i)))
+		os.MkdirAll(roots[i], 0755)
+	}
+	return roots, func() []*Directory {
+		return newDiffResultsWorkspace(roots...).Dirs
+	}
+}
+
+// TestBuildReconcilePlan_Missing_REQ_DIFF_RECONCILE tests copying to windows lacking the entry.
+func TestBuildReconcilePlan_Missing_REQ_DIFF_RECONCILE(t *testing.T) {
+	roots, dirs := newReconcileDirs(t, 3)
+	now := time.Now()
+	writeReconcileFile(t, filepath.Join(roots[1], "a.txt"), "a", now)
+
+	plan, err := BuildReconcilePlan(dirs(), "a.txt", ReconcileOptions{})
+	if err != nil {
+		t.Fatalf("BuildReconcilePlan failed: %v", err)
+	}
+	if plan.Source != 1 {
+		t.Errorf("expected the only holder (window 2) as source, got %d", plan.Source+1)
+	}
+	if len(plan.Actions) != 2 {
+		t.Fatalf("expected 2 copy actions, got %+v", plan.Actions)
+	}
+	for i, want := range []int{0, 2} {
+		a := plan.Actions[i]
+		if a.Kind != ReconcileCopy || a.Window != want || a.Dst != filepath.Join(roots[want], "a.txt") {
+			t.Errorf("unexpected action %+v", a)
+		}
+	}
+}
+
+// TestBuildReconcilePlan_Policies_REQ_DIFF_RECONCILE tests newest, largest and focused source selection.
+func TestBuildReconcilePlan_Policies_REQ_DIFF_RECONCILE(t *testing.T) {
+	roots, dirs := newReconcileDirs(t, 3)
+	old := time.Now().Add(-time.Hour)
+	writeReconcileFile(t, filepath.Join(roots[0], "f"), "large content", old)
+	writeReconcileFile(t, filepath.Join(roots[1], "f"), "new", old.Add(30*time.Minute))
+
+	plan, err := BuildReconcilePlan(dirs(), "f", ReconcileOptions{Source: ReconcileNewest})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Source != 1 {
+		t.Errorf("newest: expected window 2, got %d", plan.Source+1)
+	}
+	// Window 1 is older: updated. Window 3 lacks it: copied.
+	if len(plan.Actions) != 2 || plan.Actions[0].Kind != ReconcileUpdate || plan.Actions[1].Kind != ReconcileCopy {
+		t.Errorf("newest: unexpected actions %+v", plan.Actions)
+	}
+
+	plan, err = BuildReconcilePlan(dirs(), "f", ReconcileOptions{Source: ReconcileLargest})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Source != 0 {
+		t.Errorf("largest: expected window 1, got %d", plan.Source+1)
+	}
+	// Window 2 holds a newer copy: it is skipped rather than overwritten.
+	if len(plan.Skipped) != 1 || plan.Skipped[0].Window != 1 {
+		t.Errorf("largest: expected window 2 skipped, got %+v", plan.Skipped)
+	}
+
+	// The focused window 3 lacks the entry: the other copies are extras.
+	plan, err = BuildReconcilePlan(dirs(), "f", ReconcileOptions{Source: ReconcileFocused, Focus: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Source != 2 || len(plan.Actions) != 0 || len(plan.Skipped) != 2 || plan.Skipped[0].Reason != "not in the source" {
+		t.Errorf("focused: expected both copies skipped, got %+v", plan)
+	}
+	plan, err = BuildReconcilePlan(dirs(), "f", ReconcileOptions{Source: ReconcileFocused, Focus: 2, DeleteExtras: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Actions) != 2 || plan.Actions[0].Kind != ReconcileDelete || plan.Actions[1].Dst != filepath.Join(roots[1], "f") {
+		t.Errorf("focused with extras: expected both copies deleted, got %+v", plan.Actions)
+	}
+	if _, err := BuildReconcilePlan(dirs(), "none", ReconcileOptions{Source: ReconcileFocused, Focus: 2}); err == nil {
+		t.Error("focused: expected an error when no window holds the entry")
+	}
+}
+
+// TestBuildReconcilePlan_Directory_REQ_DIFF_RECONCILE tests recursive directory plans and deleting extras.
+func TestBuildReconcilePlan_Directory_REQ_DIFF_RECONCILE(t *testing.T) {
+	roots, dirs := newReconcileDirs(t, 2)
+	now := time.Now().Add(-time.Minute)
+	writeReconcileFile(t, filepath.Join(roots[0], "d", "same.txt"), "s", now)
+	writeReconcileFile(t, filepath.Join(roots[1], "d", "same.txt"), "s", now)
+	writeReconcileFile(t, filepath.Join(roots[0], "d", "sub", "new.txt"), "n", now)
+	writeReconcileFile(t, filepath.Join(roots[1], "d", "extra.txt"), "x", now)
+	future := time.Now().Add(time.Hour)
+	os.Chtimes(filepath.Join(roots[0], "d"), future, future)
+
+	plan, err := BuildReconcilePlan(dirs(), "d", ReconcileOptions{Source: ReconcileFocused, Focus: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Actions) != 1 {
+		t.Fatalf("expected only the missing subdir to be copied, got %+v", plan.Actions)
+	}
+	if len(plan.Skipped) != 1 || plan.Skipped[0].Path != filepath.Join(roots[1], "d", "extra.txt") {
+		t.Errorf("expected the kept extra to be listed, got %+v", plan.Skipped)
+	}
+	if a := plan.Actions[0]; a.Kind != ReconcileCopy || !a.IsDir || a.Dst != filepath.Join(roots[1], "d", "sub") {
+		t.Errorf("unexpected action %+v", a)
+	}
+
+	plan, err = BuildReconcilePlan(dirs(), "d", ReconcileOptions{Source: ReconcileFocused, Focus: 0, DeleteExtras: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Actions) != 2 {
+		t.Fatalf("expected copy and delete, got %+v", plan.Actions)
+	}
+	if a := plan.Actions[1]; a.Kind != ReconcileDelete || a.Dst != filepath.Join(roots[1], "d", "extra.txt") {
+		t.Errorf("unexpected delete action %+v", a)
+	}
+	if srcs := plan.Sources(); len(srcs) != 1 || srcs[0] != filepath.Join(roots[0], "d", "sub") {
+		t.Errorf("Sources() = %v", srcs)
+	}
+}
@@ -0,0 +1,299 @@
+// Package filer snapshot manifests for comparing a tree against a saved baseline.
+// [IMPL:DIFF_SNAPSHOT] [ARCH:DIFF_SNAPSHOT] [REQ:DIFF_SNAPSHOT]
+package filer
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestVersion is the manifest format version written by BuildManifest.
+const ManifestVersion = 1
+
+// Manifest records a directory tree's metadata so it can stand in for the
+// tree in a batch diff report.
+// [IMPL:DIFF_SNAPSHOT] [ARCH:DIFF_SNAPSHOT] [REQ:DIFF_SNAPSHOT]
+type Manifest struct {
+	Version int             `yaml:"version"`
+	Root    string          `yaml:"root"`
+	Created time.Time       `yaml:"created"`
+	Entries []ManifestEntry `yaml:"entries"`
+}
+
+// ManifestEntry describes one file, directory or symlink below the root.
+// [IMPL:DIFF_SNAPSHOT] [ARCH:DIFF_SNAPSHOT] [REQ:DIFF_SNAPSHOT]
+type ManifestEntry struct {
+	Path    string    `yaml:"path"`             // Slash-separated path relative to the root
+	Size    int64     `yaml:"size,omitempty"`   // Size in bytes as reported by lstat (not directories)
+	ModTime time.Time `yaml:"mtime"`            // Modification time
+	Mode    string    `yaml:"mode"`             // Permission bits in octal, e.g. "0644"
+	IsDir   bool      `yaml:"isDir,omitempty"`  // Whether the entry is a directory
+	Link    string    `yaml:"link,omitempty"`   // Symlink target (symlinks only)
+	Digest  string    `yaml:"digest,omitempty"` // xxHash64 of the content (regular files only)
+}
+
+// BuildManifest walks root and records every entry below it, sorted by path.
+// Hidden and excluded names are recorded too; filters apply when comparing.
+// [IMPL:DIFF_SNAPSHOT] [ARCH:DIFF_SNAPSHOT] [REQ:DIFF_SNAPSHOT]
+func BuildManifest(root string) (*Manifest, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(absRoot); err != nil {
+		return nil, err
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+
+	m := &Manifest{Version: ManifestVersion, Root: absRoot, Created: time.Now().UTC().Truncate(time.Second)}
+	err = filepath.WalkDir(absRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == absRoot {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(absRoot, path)
+		if err != nil {
+			return err
+		}
+		e := ManifestEntry{
+			Path:    filepath.ToSlash(rel),
+			ModTime: info.ModTime().UTC(),
+			Mode:    fmt.Sprintf("%04o", info.Mode().Perm()),
+			IsDir:   info.IsDir(),
+		}
+		if !e.IsDir {
+			e.Size = info.Size()
+		}
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			if e.Link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		case info.Mode().IsRegular():
+			digest, err := CalculateFileDigest(path)
+			if err != nil {
+				return err
+			}
+			e.Digest = formatDigest(digest)
+		}
+		m.Entries = append(m.Entries, e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(m.Entries, func(i, j int) bool { return m.Entries[i].Path < m.Entries[j].Path })
+	return m, nil
+}
+
+// LoadManifest reads a manifest written by BuildManifest.
+// [IMPL:DIFF_SNAPSHOT] [ARCH:DIFF_SNAPSHOT] [REQ:DIFF_SNAPSHOT]
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+	}
+	if m.Version != ManifestVersion {
+		return nil, fmt.Errorf("unsupported manifest version %d in %s", m.Version, path)
+	}
+	return &m, nil
+}
+
+// formatDigest renders a digest the way manifests store it.
+func formatDigest(digest uint64) string {
+	return fmt.Sprintf("%016x", digest)
+}
+
+// manifestTree indexes manifest entries by their parent directory.
+type manifestTree struct {
+	children map[string][]*ManifestEntry // key: slash-separated parent path, "" for the root
+	dirs     map[string]bool
+}
+
+// newManifestTree builds the parent index for m.
+func newManifestTree(m *Manifest) *manifestTree {
+	t := &manifestTree{children: map[string][]*ManifestEntry{}, dirs: map[string]bool{"": true}}
+	for i := range m.Entries {
+		e := &m.Entries[i]
+		parent := ""
+		if i := strings.LastIndex(e.Path, "/"); i >= 0 {
+			parent = e.Path[:i]
+		}
+		t.children[parent] = append(t.children[parent], e)
+		if e.IsDir {
+			t.dirs[e.Path] = true
+		}
+	}
+	return t
+}
+
+// manifestReader lists one directory of a manifest. It stands in for the
+// on-disk reader of a batch Directory whose Path is the manifest file
+// joined with the relative directory.
+// [IMPL:DIFF_SNAPSHOT] [ARCH:DIFF_SNAPSHOT] [REQ:DIFF_SNAPSHOT]
+type manifestReader struct {
+	tree *manifestTree
+	root string // absolute manifest path
+	rel  string // slash-separated directory relative to the manifest root
+}
+
+func (r *manifestReader) String() string { return "manifest" }
+func (r *manifestReader) Read(callback func(name string)) {
+	for _, e := range r.tree.children[r.rel] {
+		callback(pathBase(e.Path))
+	}
+}
+
+// pathBase returns the last element of a slash-separated path.
+func pathBase(p string) string {
+	return p[strings.LastIndex(p, "/")+1:]
+}
+
+// manifestFileInfo adapts a manifest entry to os.FileInfo.
+// [IMPL:DIFF_SNAPSHOT] [ARCH:DIFF_SNAPSHOT] [REQ:DIFF_SNAPSHOT]
+type manifestFileInfo struct {
+	entry *ManifestEntry
+}
+
+func (fi *manifestFileInfo) Name() string       { return pathBase(fi.entry.Path) }
+func (fi *manifestFileInfo) Size() int64        { return fi.entry.Size }
+func (fi *manifestFileInfo) ModTime() time.Time { return fi.entry.ModTime }
+func (fi *manifestFileInfo) IsDir() bool        { return fi.entry.IsDir }
+func (fi *manifestFileInfo) Sys() interface{}   { return fi.entry }
+func (fi *manifestFileInfo) Mode() os.FileMode {
+	perm, _ := strconv.ParseUint(fi.entry.Mode, 8, 32)
+	mode := os.FileMode(perm).Perm()
+	switch {
+	case fi.entry.IsDir:
+		mode |= os.ModeDir
+	case fi.entry.Link != "":
+		mode |= os.ModeSymlink
+	}
+	return mode
+}
+
+// newManifestDirectory creates a batch Directory backed by the manifest at path.
+// [IMPL:DIFF_SNAPSHOT] [ARCH:DIFF_SNAPSHOT] [REQ:DIFF_SNAPSHOT]
+func newManifestDirectory(path string) (*Directory, error) {
+	m, err := LoadManifest(path)
+	if err != nil {
+		return nil, err
+	}
+	dir := &Directory{
+		reader:  &manifestReader{tree: newManifestTree(m), root: path},
+		history: map[string]string{},
+		Path:    path,
+		Sort:    SortName,
+	}
+	if err := dir.batchRead(); err != nil {
+		return nil, err
+	}
+	return dir, nil
+}
+
+// manifestChdir moves a manifest-backed directory to path, which is relative
+// to the current directory or absolute under the manifest path.
+// [IMPL:DIFF_SNAPSHOT] [ARCH:DIFF_SNAPSHOT] [REQ:DIFF_SNAPSHOT]
+func (d *Directory) manifestChdir(r *manifestReader, path string) error {
+	absPath := filepath.Clean(path)
+	if !filepath.IsAbs(path) {
+		absPath = filepath.Clean(filepath.Join(d.Path, path))
+	}
+	rel, err := filepath.Rel(r.root, absPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("not in manifest: %s", absPath)
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == "." {
+		rel = ""
+	}
+	if !r.tree.dirs[rel] {
+		return fmt.Errorf("not a directory in manifest: %s", absPath)
+	}
+	d.Path = absPath
+	d.reader = &manifestReader{tree: r.tree, root: r.root, rel: rel}
+	return d.batchRead()
+}
+
+// manifestFileStats returns FileStats for the entries of the current manifest directory.
+func (d *Directory) manifestFileStats(r *manifestReader) []*FileStat {
+	var stats []*FileStat
+	for _, e := range r.tree.children[r.rel] {
+		name := pathBase(e.Path)
+		fi := &manifestFileInfo{entry: e}
+		stats = append(stats, &FileStat{FileInfo: fi, stat: fi, path: filepath.Join(d.Path, name), name: name, display: name})
+	}
+	return stats
+}
+
+// manifestDrift reports content or permission drift between entries that
+// have the same size when at least one of them comes from a manifest.
+// Real files are digested only in that case, so directory-only comparisons
+// keep their size-based behaviour.
+// [IMPL:DIFF_SNAPSHOT] [ARCH:DIFF_SNAPSHOT] [REQ:DIFF_SNAPSHOT]
+func manifestDrift(entries []*FileStat) string {
+	fromManifest := false
+	for _, e := range entries {
+		if _, ok := e.FileInfo.(*manifestFileInfo); ok {
+			fromManifest = true
+			break
+		}
+	}
+	if !fromManifest {
+		return ""
+	}
+
+	for i := 1; i < len(entries); i++ {
+		if entries[i].Mode().Perm() != entries[0].Mode().Perm() {
+			return "mode mismatch"
+		}
+	}
+	if !entries[0].Mode().IsRegular() {
+		return ""
+	}
+	first := ""
+	for i, e := range entries {
+		digest, err := entryDigest(e)
+		if err != nil {
+			return "unreadable"
+		}
+		if i == 0 {
+			first = digest
+		} else if digest != first {
+			return "digest mismatch"
+		}
+	}
+	return ""
+}
+
+// entryDigest returns the recorded digest of a manifest entry, or computes
+// the digest of a file on disk.
+func entryDigest(e *FileStat) (string, error) {
+	if fi, ok := e.FileInfo.(*manifestFileInfo); ok {
+		return fi.entry.Digest, nil
+	}
+	digest, err := CalculateFileDigest(e.Path())
+	if err != nil {
+		return "", err
+	}
+	return formatDigest(digest), nil
+}
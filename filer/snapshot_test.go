@@ -0,0 +1,154 @@
+// Package filer snapshot manifest tests.
+// [IMPL:DIFF_SNAPSHOT] [ARCH:DIFF_SNAPSHOT] [REQ:DIFF_SNAPSHOT]
+package filer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// writeSnapshotTree creates a release tree and returns its root.
+func writeSnapshotTree(t *testing.T, root string) {
+	t.Helper()
+	os.MkdirAll(filepath.Join(root, "sub"), 0755)
+	os.WriteFile(filepath.Join(root, "a.txt"), []byte("hello"), 0644)
+	os.WriteFile(filepath.Join(root, "sub", "run.sh"), []byte("#!/bin/sh"), 0755)
+	os.Symlink("a.txt", filepath.Join(root, "link"))
+}
+
+// saveManifest snapshots root into a manifest file and returns its path.
+func saveManifest(t *testing.T, root string) string {
+	t.Helper()
+	m, err := BuildManifest(root)
+	if err != nil {
+		t.Fatalf("BuildManifest failed: %v", err)
+	}
+	data, err := yaml.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestBuildManifest_REQ_DIFF_SNAPSHOT verifies entries, digests and the load round trip.
+func TestBuildManifest_REQ_DIFF_SNAPSHOT(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "release")
+	writeSnapshotTree(t, root)
+
+	loaded, err := LoadManifest(saveManifest(t, root))
+	if err != nil {
+		t.Fatalf("LoadManifest failed: %v", err)
+	}
+	var paths []string
+	for _, e := range loaded.Entries {
+		paths = append(paths, e.Path)
+	}
+	want := []string{"a.txt", "link", "sub", "sub/run.sh"}
+	if len(paths) != len(want) {
+		t.Fatalf("entries = %v, want %v", paths, want)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Fatalf("entries = %v, want %v", paths, want)
+		}
+	}
+
+	a, link, sub, run := loaded.Entries[0], loaded.Entries[1], loaded.Entries[2], loaded.Entries[3]
+	digest, _ := CalculateFileDigest(filepath.Join(root, "a.txt"))
+	if a.Size != 5 || a.Mode != "0644" || a.Digest != formatDigest(digest) {
+		t.Errorf("unexpected file entry %+v", a)
+	}
+	if link.Link != "a.txt" || link.Digest != "" {
+		t.Errorf("unexpected symlink entry %+v", link)
+	}
+	if !sub.IsDir || run.Mode != "0755" {
+		t.Errorf("unexpected entries %+v %+v", sub, run)
+	}
+
+	if _, err := BuildManifest(filepath.Join(root, "a.txt")); err == nil {
+		t.Error("expected an error when snapshotting a file")
+	}
+}
+
+// TestDiffReportAgainstManifest_REQ_DIFF_SNAPSHOT verifies a manifest stands in for a directory.
+func TestDiffReportAgainstManifest_REQ_DIFF_SNAPSHOT(t *testing.T) {
+	tmpDir := t.TempDir()
+	release := filepath.Join(tmpDir, "release")
+	target := filepath.Join(tmpDir, "target")
+	writeSnapshotTree(t, release)
+	manifest := saveManifest(t, release)
+
+	// An untouched copy matches its manifest.
+	report, err := RunBatchDiffSearch([]string{manifest, release}, nil)
+	if err != nil {
+		t.Fatalf("RunBatchDiffSearch failed: %v", err)
+	}
+	if len(report.Differences) != 0 {
+		t.Fatalf("expected no drift, got %+v", report.Differences)
+	}
+
+	// Same sizes, different content and permissions, plus an extra file.
+	writeSnapshotTree(t, target)
+	os.WriteFile(filepath.Join(target, "a.txt"), []byte("HELLO"), 0644)
+	os.Chmod(filepath.Join(target, "sub", "run.sh"), 0644)
+	os.WriteFile(filepath.Join(target, "sub", "extra"), []byte("x"), 0644)
+
+	report, err = RunBatchDiffSearch([]string{manifest, target}, nil)
+	if err != nil {
+		t.Fatalf("RunBatchDiffSearch failed: %v", err)
+	}
+	got := map[string]string{}
+	for _, d := range report.Differences {
+		got[d.Path] = d.Reason
+	}
+	want := map[string]string{
+		"a.txt":                        "digest mismatch",
+		filepath.Join("sub", "extra"):  "missing in window 1",
+		filepath.Join("sub", "run.sh"): "mode mismatch",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("differences = %v, want %v", got, want)
+	}
+	for path, reason := range want {
+		if got[path] != reason {
+			t.Errorf("%s: reason %q, want %q", path, got[path], reason)
+		}
+	}
+	if report.Directories[0] != manifest {
+		t.Errorf("expected the manifest path in the report, got %v", report.Directories)
+	}
+}
+
+// TestManifestChdir_REQ_DIFF_SNAPSHOT verifies navigation stays inside the manifest.
+func TestManifestChdir_REQ_DIFF_SNAPSHOT(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "release")
+	writeSnapshotTree(t, root)
+	manifest := saveManifest(t, root)
+
+	dir, err := newManifestDirectory(manifest)
+	if err != nil {
+		t.Fatalf("newManifestDirectory failed: %v", err)
+	}
+	if err := dir.batchChdir("sub"); err != nil {
+		t.Fatalf("batchChdir(sub) failed: %v", err)
+	}
+	if dir.Base() != "sub" || findEntryInDir(dir, "run.sh") == nil {
+		t.Errorf("expected sub listing, got path %s", dir.Path)
+	}
+	if err := dir.batchChdir(".."); err != nil || dir.Path != manifest {
+		t.Errorf("expected to return to the manifest root, got %s (%v)", dir.Path, err)
+	}
+	if err := dir.batchChdir("a.txt"); err == nil {
+		t.Error("expected an error changing into a file")
+	}
+	if err := dir.batchChdir(".."); err == nil {
+		t.Error("expected an error leaving the manifest root")
+	}
+}
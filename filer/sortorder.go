@@ -0,0 +1,180 @@
+package filer
+
+import (
+	"os/user"
+	"strings"
+	"sync"
+)
+
+// compareNatural compares a and b with digit runs compared as numbers, so
+// "file2" sorts before "file10". Equal numbers with more leading zeros sort
+// later, and otherwise equal names fall back to byte order.
+// [IMPL:SORT_ORDERS] [ARCH:SORT_ORDERS] [REQ:SORT_ORDERS]
+func compareNatural(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if isDigit(a[i]) && isDigit(b[j]) {
+			si, sj := i, j
+			for i < len(a) && isDigit(a[i]) {
+				i++
+			}
+			for j < len(b) && isDigit(b[j]) {
+				j++
+			}
+			if c := compareDigits(a[si:i], b[sj:j]); c != 0 {
+				return c
+			}
+			continue
+		}
+		if a[i] != b[j] {
+			if a[i] < b[j] {
+				return -1
+			}
+			return 1
+		}
+		i++
+		j++
+	}
+	switch {
+	case len(a)-i < len(b)-j:
+		return -1
+	case len(a)-i > len(b)-j:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// compareDigits compares two digit runs by numeric value, then by length.
+func compareDigits(a, b string) int {
+	ta, tb := strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	switch {
+	case len(ta) != len(tb):
+		if len(ta) < len(tb) {
+			return -1
+		}
+		return 1
+	case ta != tb:
+		return strings.Compare(ta, tb)
+	case len(a) != len(b):
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
+
+// splitVersion splits name into the text before its first digit, the
+// dotted version core and the pre-release or build suffix after '-' or '+'.
+func splitVersion(name string) (prefix, core, suffix string) {
+	start := strings.IndexFunc(name, func(r rune) bool { return '0' <= r && r <= '9' })
+	if start < 0 {
+		return name, "", ""
+	}
+	prefix, rest := name[:start], name[start:]
+	end := strings.IndexAny(rest, "-+")
+	if end < 0 {
+		return prefix, rest, ""
+	}
+	return prefix, rest[:end], rest[end:]
+}
+
+// compareVersion compares release names such as "v1.9.0" and "v1.10.0-rc1"
+// by semantic version: the dotted core numerically field by field, then a
+// pre-release before its release. Names with different prefixes are
+// compared naturally.
+// [IMPL:SORT_ORDERS] [ARCH:SORT_ORDERS] [REQ:SORT_ORDERS]
+func compareVersion(a, b string) int {
+	pa, ca, sa := splitVersion(a)
+	pb, cb, sb := splitVersion(b)
+	if pa != pb || ca == "" || cb == "" {
+		return compareNatural(a, b)
+	}
+	fa, fb := strings.Split(ca, "."), strings.Split(cb, ".")
+	for k := 0; k < len(fa) && k < len(fb); k++ {
+		if c := compareNatural(fa[k], fb[k]); c != 0 {
+			return c
+		}
+	}
+	if len(fa) != len(fb) {
+		if len(fa) < len(fb) {
+			return -1
+		}
+		return 1
+	}
+	preA, preB := strings.HasPrefix(sa, "-"), strings.HasPrefix(sb, "-")
+	switch {
+	case preA && !preB:
+		return -1
+	case !preA && preB:
+		return 1
+	}
+	return compareNatural(sa, sb)
+}
+
+// compareFold compares a and b case-insensitively, then by byte order.
+// [IMPL:SORT_ORDERS] [ARCH:SORT_ORDERS] [REQ:SORT_ORDERS]
+func compareFold(a, b string) int {
+	if c := strings.Compare(strings.ToLower(a), strings.ToLower(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+var (
+	ownerNamesMu sync.Mutex
+	ownerNames   = map[string]string{}
+)
+
+// ownerName returns the user name for uid, or uid itself when it has none.
+func ownerName(uid string) string {
+	if uid == "" {
+		return ""
+	}
+	ownerNamesMu.Lock()
+	defer ownerNamesMu.Unlock()
+	if name, ok := ownerNames[uid]; ok {
+		return name
+	}
+	name := uid
+	if u, err := user.LookupId(uid); err == nil {
+		name = u.Username
+	}
+	ownerNames[uid] = name
+	return name
+}
+
+// Owner returns the name of the user owning the file, or "" where the
+// platform does not report one.
+// [IMPL:SORT_ORDERS] [ARCH:SORT_ORDERS] [REQ:SORT_ORDERS]
+func (f *FileStat) Owner() string {
+	return ownerName(fileOwnerID(f.stat))
+}
+
+func (d *Directory) lessNames(i, j int, cmp func(a, b string) int) bool {
+	return cmp(d.List()[i].Name(), d.List()[j].Name()) < 0
+}
+
+func (d *Directory) lessPerm(i, j int) bool {
+	f1 := d.List()[i].(*FileStat)
+	f2 := d.List()[j].(*FileStat)
+	p1 := f1.stat.Mode().Perm()
+	p2 := f2.stat.Mode().Perm()
+	if p1 != p2 {
+		return p1 < p2
+	}
+	return f1.Name() < f2.Name()
+}
+
+func (d *Directory) lessOwner(i, j int) bool {
+	f1 := d.List()[i].(*FileStat)
+	f2 := d.List()[j].(*FileStat)
+	o1 := f1.Owner()
+	o2 := f2.Owner()
+	if o1 != o2 {
+		return o1 < o2
+	}
+	return f1.Name() < f2.Name()
+}
@@ -0,0 +1,81 @@
+package filer
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// TestSortOrderCompare_REQ_SORT_ORDERS tests the natural, version and case-insensitive orders.
+// [REQ:SORT_ORDERS] [ARCH:SORT_ORDERS] [IMPL:SORT_ORDERS]
+func TestSortOrderCompare_REQ_SORT_ORDERS(t *testing.T) {
+	tests := []struct {
+		name string
+		cmp  func(a, b string) int
+		in   []string
+		want []string
+	}{
+		{"natural", compareNatural,
+			[]string{"file10", "file2", "file1", "file02", "File3", "file"},
+			[]string{"File3", "file", "file1", "file2", "file02", "file10"}},
+		{"version", compareVersion,
+			[]string{"v1.10.0", "v1.2.0", "v1.10.0-rc1", "v1.9", "v1.10.0-rc10", "v1.10.0-rc2", "v2"},
+			[]string{"v1.2.0", "v1.9", "v1.10.0-rc1", "v1.10.0-rc2", "v1.10.0-rc10", "v1.10.0", "v2"}},
+		{"version prefixes", compareVersion,
+			[]string{"release-2.0", "beta-1.0", "release-10.0", "notes"},
+			[]string{"beta-1.0", "notes", "release-2.0", "release-10.0"}},
+		{"fold", compareFold,
+			[]string{"b", "B", "a", "C"},
+			[]string{"a", "B", "b", "C"}},
+	}
+	for _, tt := range tests {
+		got := append([]string(nil), tt.in...)
+		sort.SliceStable(got, func(i, j int) bool { return tt.cmp(got[i], got[j]) < 0 })
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+// TestSortOrderDirectory_REQ_SORT_ORDERS tests the new sort types on a directory
+// and through linked SortAllBy, with directories mixed into files.
+// [REQ:SORT_ORDERS] [ARCH:SORT_ORDERS] [IMPL:SORT_ORDERS]
+func TestSortOrderDirectory_REQ_SORT_ORDERS(t *testing.T) {
+	ws := NewWorkspace(0, 0, 100, 40, "test")
+	for k := 0; k < 2; k++ {
+		tmp := t.TempDir()
+		for _, name := range []string{"f10", "f2", "f1"} {
+			os.WriteFile(filepath.Join(tmp, name), nil, 0o644)
+		}
+		os.Chmod(filepath.Join(tmp, "f1"), 0o600)
+		os.Mkdir(filepath.Join(tmp, "f3"), 0o755)
+		ws.Dirs = append(ws.Dirs, newTestDirectory(t, tmp))
+	}
+
+	ws.SortAllBy(SortNatural)
+	for i, d := range ws.Dirs {
+		if got := listOrder(d); got[0] != "f3" || got[1] != "f1" || got[3] != "f10" {
+			t.Errorf("pane %d natural = %v, want [f3 f1 f2 f10]", i+1, got)
+		}
+	}
+
+	ws.ChangeView(false, func(v *DirectoryView) { v.PriorityDir = false })
+	ws.Dir().SortBy(SortNaturalRev)
+	if got := listOrder(ws.Dir()); got[0] != "f10" || got[1] != "f3" || got[3] != "f1" {
+		t.Errorf("mixed natural descending = %v, want [f10 f3 f2 f1]", got)
+	}
+
+	ws.Dir().SortBy(SortPerm)
+	if got := listOrder(ws.Dir()); got[0] != "f1" || got[3] != "f3" {
+		t.Errorf("permission = %v, want f1 first and f3 last", got)
+	}
+
+	ws.Dir().SortBy(SortOwnerRev)
+	if got := listOrder(ws.Dir()); got[0] != "f3" || got[3] != "f1" {
+		t.Errorf("owner descending = %v, want name order reversed for one owner", got)
+	}
+}
@@ -0,0 +1,118 @@
+package filer
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// DirectoryView is the view settings of a pane: hidden files, directory
+// priority in sorting and the file stat columns.
+// [IMPL:PANE_VIEW_SETTINGS] [ARCH:PANE_VIEW_SETTINGS] [REQ:PANE_VIEW_SETTINGS]
+type DirectoryView struct {
+	ShowHiddens    bool `json:"show_hiddens"`
+	PriorityDir    bool `json:"priority_dir"`
+	StatSize       bool `json:"stat_size"`
+	StatPermission bool `json:"stat_permission"`
+	StatTime       bool `json:"stat_time"`
+}
+
+// default view of panes that have no view of their own
+var (
+	priorityDir = true
+	showHiddens = true
+	statView    = fileStatView{true, true, true}
+)
+
+type fileStatView struct {
+	size       bool
+	permission bool
+	time       bool
+}
+
+// SetStatView sets the default file stat view of panes.
+func SetStatView(size, permission, time bool) { statView = fileStatView{size, permission, time} }
+
+// TogglePriority toggles the directory priority of the default view.
+//
+// Deprecated: change the pane view with Workspace.ChangeView.
+func TogglePriority() { priorityDir = !priorityDir }
+
+// ToggleShowHiddens toggles the hidden files of the default view.
+//
+// Deprecated: change the pane view with Workspace.ChangeView.
+func ToggleShowHiddens() { showHiddens = !showHiddens }
+
+// ToggleSizeView toggles the file size of the default view.
+//
+// Deprecated: change the pane view with Workspace.ChangeView.
+func ToggleSizeView() { statView.size = !statView.size }
+
+// TogglePermView toggles the file permission of the default view.
+//
+// Deprecated: change the pane view with Workspace.ChangeView.
+func TogglePermView() { statView.permission = !statView.permission }
+
+// ToggleTimeView toggles the file time of the default view.
+//
+// Deprecated: change the pane view with Workspace.ChangeView.
+func ToggleTimeView() { statView.time = !statView.time }
+
+// DefaultView returns the view of panes that have no view of their own.
+// [IMPL:PANE_VIEW_SETTINGS] [ARCH:PANE_VIEW_SETTINGS] [REQ:PANE_VIEW_SETTINGS]
+func DefaultView() DirectoryView {
+	return DirectoryView{
+		ShowHiddens:    showHiddens,
+		PriorityDir:    priorityDir,
+		StatSize:       statView.size,
+		StatPermission: statView.permission,
+		StatTime:       statView.time,
+	}
+}
+
+// ViewSettings returns the view of the directory.
+// [IMPL:PANE_VIEW_SETTINGS] [ARCH:PANE_VIEW_SETTINGS] [REQ:PANE_VIEW_SETTINGS]
+func (d *Directory) ViewSettings() DirectoryView {
+	if d.View == nil {
+		return DefaultView()
+	}
+	return *d.View
+}
+
+// SetViewSettings gives the directory its own view.
+// Reload the directory to apply hidden-file and priority changes.
+// [IMPL:PANE_VIEW_SETTINGS] [ARCH:PANE_VIEW_SETTINGS] [REQ:PANE_VIEW_SETTINGS]
+func (d *Directory) SetViewSettings(v DirectoryView) {
+	d.View = &v
+}
+
+// isHidden reports whether name is filtered out as a hidden file.
+func (d *Directory) isHidden(name string) bool {
+	if d.ViewSettings().ShowHiddens {
+		return false
+	}
+	return strings.HasPrefix(name, ".") || strings.HasPrefix(filepath.Base(name), ".")
+}
+
+// ChangeView applies fn to the view of the focused directory. When linked is
+// true the directories linked to it adopt the same view, like sorting does.
+// [IMPL:PANE_VIEW_SETTINGS] [ARCH:PANE_VIEW_SETTINGS] [REQ:PANE_VIEW_SETTINGS]
+func (w *Workspace) ChangeView(linked bool, fn func(v *DirectoryView)) {
+	v := w.Dir().ViewSettings()
+	fn(&v)
+	for i, d := range w.Dirs {
+		if i == w.Focus || linked && w.IsLinkedTo(i) {
+			d.SetViewSettings(v)
+		}
+	}
+	w.ReloadAll()
+}
+
+// ApplyViewToAll gives every directory the view of the focused directory.
+// [IMPL:PANE_VIEW_SETTINGS] [ARCH:PANE_VIEW_SETTINGS] [REQ:PANE_VIEW_SETTINGS]
+func (w *Workspace) ApplyViewToAll() {
+	v := w.Dir().ViewSettings()
+	for _, d := range w.Dirs {
+		d.SetViewSettings(v)
+	}
+	w.ReloadAll()
+}
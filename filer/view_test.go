@@ -0,0 +1,118 @@
+package filer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// listOrder returns the names of dir in list order.
+func listOrder(dir *Directory) []string {
+	names := make([]string, 0, dir.Len())
+	for _, item := range dir.List() {
+		names = append(names, item.Name())
+	}
+	return names
+}
+
+// TestDirectoryView_REQ_PANE_VIEW_SETTINGS tests that hidden files and priority are per pane.
+// [REQ:PANE_VIEW_SETTINGS] [ARCH:PANE_VIEW_SETTINGS] [IMPL:PANE_VIEW_SETTINGS]
+func TestDirectoryView_REQ_PANE_VIEW_SETTINGS(t *testing.T) {
+	tmp := t.TempDir()
+	os.WriteFile(filepath.Join(tmp, ".hidden"), nil, 0o644)
+	os.WriteFile(filepath.Join(tmp, "a"), nil, 0o644)
+	os.Mkdir(filepath.Join(tmp, "z"), 0o755)
+
+	shown := newTestDirectory(t, tmp)
+	hidden := newTestDirectory(t, tmp)
+	v := hidden.ViewSettings()
+	v.ShowHiddens = false
+	v.PriorityDir = false
+	hidden.SetViewSettings(v)
+	shown.reload()
+	hidden.reload()
+
+	if got := listOrder(shown); len(got) != 3 || got[0] != "z" {
+		t.Errorf("default pane names = %v, want z first and .hidden shown", got)
+	}
+	if got := listOrder(hidden); len(got) != 2 || got[0] != "a" || got[1] != "z" {
+		t.Errorf("own view names = %v, want [a z]", got)
+	}
+	if shown.View != nil {
+		t.Error("a pane without changes should keep the default view")
+	}
+}
+
+// TestChangeViewLinked_REQ_PANE_VIEW_SETTINGS tests that view changes reach linked panes only
+// when linked, and that apply-to-all reaches every pane.
+// [REQ:PANE_VIEW_SETTINGS] [ARCH:PANE_VIEW_SETTINGS] [IMPL:PANE_VIEW_SETTINGS]
+func TestChangeViewLinked_REQ_PANE_VIEW_SETTINGS(t *testing.T) {
+	ws, _ := newLinkGroupWorkspace(t)
+	noSize := func(v *DirectoryView) { v.StatSize = false }
+
+	ws.ChangeView(false, noSize)
+	for i, want := range []bool{false, true, true, true, true} {
+		if got := ws.Dirs[i].ViewSettings().StatSize; got != want {
+			t.Errorf("unlinked: pane %d size = %v, want %v", i+1, got, want)
+		}
+	}
+
+	ws.Focus = 2
+	ws.ChangeView(true, noSize)
+	for i, want := range []bool{false, true, false, false, true} {
+		if got := ws.Dirs[i].ViewSettings().StatSize; got != want {
+			t.Errorf("linked: pane %d size = %v, want %v", i+1, got, want)
+		}
+	}
+
+	ws.Focus = 0
+	ws.ApplyViewToAll()
+	for i, d := range ws.Dirs {
+		if d.ViewSettings().StatSize {
+			t.Errorf("apply to all: pane %d still shows size", i+1)
+		}
+	}
+}
+
+// TestDirectoryViewJSON_REQ_PANE_VIEW_SETTINGS tests that pane views round-trip through the state JSON.
+// [REQ:PANE_VIEW_SETTINGS] [ARCH:PANE_VIEW_SETTINGS] [IMPL:PANE_VIEW_SETTINGS]
+func TestDirectoryViewJSON_REQ_PANE_VIEW_SETTINGS(t *testing.T) {
+	d := NewDirectory(0, 0, 80, 20)
+	d.SetViewSettings(DirectoryView{ShowHiddens: true, StatTime: true})
+	data, err := json.Marshal([]*Directory{d, NewDirectory(0, 0, 80, 20)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dirs []*Directory
+	if err := json.Unmarshal(data, &dirs); err != nil {
+		t.Fatal(err)
+	}
+	if dirs[0].View == nil || *dirs[0].View != (DirectoryView{ShowHiddens: true, StatTime: true}) {
+		t.Errorf("view = %+v", dirs[0].View)
+	}
+	if dirs[1].View != nil {
+		t.Errorf("default view should not be saved, got %+v", dirs[1].View)
+	}
+}
+
+// TestDeprecatedToggles_REQ_PANE_VIEW_SETTINGS tests that the old global toggles change
+// the default view, which panes without a view of their own use.
+// [REQ:PANE_VIEW_SETTINGS] [ARCH:PANE_VIEW_SETTINGS] [IMPL:PANE_VIEW_SETTINGS]
+func TestDeprecatedToggles_REQ_PANE_VIEW_SETTINGS(t *testing.T) {
+	before := DefaultView()
+	toggles := []func(){TogglePriority, ToggleShowHiddens, ToggleSizeView, TogglePermView, ToggleTimeView}
+	for _, toggle := range toggles {
+		toggle()
+	}
+	want := DirectoryView{!before.ShowHiddens, !before.PriorityDir, !before.StatSize, !before.StatPermission, !before.StatTime}
+	if got := DefaultView(); got != want {
+		t.Errorf("DefaultView() = %+v, want %+v", got, want)
+	}
+	for _, toggle := range toggles {
+		toggle()
+	}
+	if got := DefaultView(); got != before {
+		t.Errorf("toggling twice should restore %+v, got %+v", before, got)
+	}
+}
@@ -2,7 +2,6 @@ package filer
 
 import (
 	"os"
-	"path/filepath"
 
 	"github.com/fareedst/goful/message"
 	"github.com/fareedst/goful/widget"
@@ -22,12 +21,14 @@ const (
 // Workspace is a box storing and layouting directories.
 type Workspace struct {
 	*widget.Window
-	Dirs            []*Directory     `json:"directories"`
-	Layout          layoutType       `json:"layout"`
-	Title           string           `json:"title"`
-	Focus           int              `json:"focus"`
-	comparisonIndex *ComparisonIndex // [IMPL:FILE_COMPARISON_INDEX] [ARCH:FILE_COMPARISON_ENGINE] [REQ:FILE_COMPARISON_COLORS]
-	diffSearch      *DiffSearchState // [IMPL:DIFF_SEARCH] [ARCH:DIFF_SEARCH] [REQ:DIFF_SEARCH]
+	Dirs            []*Directory       `json:"directories"`
+	Layout          layoutType         `json:"layout"`
+	Title           string             `json:"title"`
+	Focus           int                `json:"focus"`
+	comparisonIndex *ComparisonIndex   // [IMPL:FILE_COMPARISON_INDEX] [ARCH:FILE_COMPARISON_ENGINE] [REQ:FILE_COMPARISON_COLORS]
+	diffSearch      *DiffSearchState   // [IMPL:DIFF_SEARCH] [ARCH:DIFF_SEARCH] [REQ:DIFF_SEARCH]
+	PathMap         []PathMapRule      `json:"pathmap,omitempty"`  // [IMPL:LINKED_PATH_MAP] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_PATH_MAP]
+	Settings        *WorkspaceSettings `json:"settings,omitempty"` // [IMPL:WORKSPACE_SETTINGS] [ARCH:WORKSPACE_SETTINGS] [REQ:WORKSPACE_SETTINGS]
 }
 
 // NewWorkspace returns a new workspace of specified sizes.
@@ -225,20 +226,21 @@ func (w *Workspace) SetTitle(title string) {
 	w.Title = title
 }
 
-// ChdirAllToSubdirNoRebuild navigates all non-focused directories to a subdirectory with the given name,
-// if that subdirectory exists in each directory's current path.
+// ChdirAllToSubdirNoRebuild navigates the non-focused directories of the focused link group to a subdirectory with the given name,
+// if that subdirectory exists in each directory's current path. When it does not, the workspace
+// path mapping rules are tried in order.
 // Returns (navigated, skipped) counts: navigated is the number of non-focused windows that successfully
 // navigated, skipped is the number where the subdirectory does not exist.
 // Does NOT rebuild the comparison index - caller is responsible for calling RebuildComparisonIndex().
 // [IMPL:LINKED_NAVIGATION] [IMPL:LINKED_NAVIGATION_AUTO_DISABLE] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_NAVIGATION]
 func (w *Workspace) ChdirAllToSubdirNoRebuild(name string) (navigated, skipped int) {
 	for i, d := range w.Dirs {
-		if i == w.Focus {
-			continue // Skip focused directory; caller handles it
+		if i == w.Focus || !w.IsLinkedTo(i) {
+			continue // Skip focused directory (caller handles it) and other link groups
 		}
-		targetPath := filepath.Join(d.Path, name)
-		if info, err := os.Stat(targetPath); err == nil && info.IsDir() {
-			d.Chdir(name)
+		// [IMPL:LINKED_PATH_MAP] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_PATH_MAP]
+		if target, ok := w.mappedSubdir(d, name); ok {
+			d.Chdir(target)
 			navigated++
 		} else {
 			skipped++
@@ -248,38 +250,32 @@ func (w *Workspace) ChdirAllToSubdirNoRebuild(name string) (navigated, skipped i
 }
 
 // ChdirAllToSubdir navigates all non-focused directories to a subdirectory with the given name,
-// if that subdirectory exists in each directory's current path.
+// if that subdirectory (or a mapping of it) exists in each directory's current path.
 // [IMPL:LINKED_NAVIGATION] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_NAVIGATION]
 func (w *Workspace) ChdirAllToSubdir(name string) {
-	for i, d := range w.Dirs {
-		if i == w.Focus {
-			continue // Skip focused directory; caller handles it
-		}
-		targetPath := filepath.Join(d.Path, name)
-		if info, err := os.Stat(targetPath); err == nil && info.IsDir() {
-			d.Chdir(name)
-		}
-	}
+	w.ChdirAllToSubdirNoRebuild(name)
 	w.RebuildComparisonIndex()
 }
 
-// ChdirAllToParent navigates all directories (including focused) to their respective parent directories.
+// ChdirAllToParent navigates the non-focused directories of the focused link group to their respective parent directories.
 // [IMPL:LINKED_NAVIGATION] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_NAVIGATION]
 func (w *Workspace) ChdirAllToParent() {
 	for i, d := range w.Dirs {
-		if i == w.Focus {
-			continue // Skip focused directory; caller handles it
+		if i == w.Focus || !w.IsLinkedTo(i) {
+			continue // Skip focused directory (caller handles it) and other link groups
 		}
 		d.Chdir("..")
 	}
 	w.RebuildComparisonIndex()
 }
 
-// SortAllBy applies the given sort type to all directories in the workspace.
+// SortAllBy applies the given sort type to all directories in the focused link group.
 // [IMPL:LINKED_NAVIGATION] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_NAVIGATION]
 func (w *Workspace) SortAllBy(typ SortType) {
-	for _, d := range w.Dirs {
-		d.SortBy(typ)
+	for i, d := range w.Dirs {
+		if w.IsLinkedTo(i) { // [IMPL:LINK_GROUPS] [ARCH:LINKED_NAVIGATION] [REQ:LINK_GROUPS]
+			d.SortBy(typ)
+		}
 	}
 	w.RebuildComparisonIndex()
 }
@@ -310,11 +306,12 @@ func (w *Workspace) IsDiffSearchActive() bool {
 	return w.diffSearch.IsActive()
 }
 
-// SetCursorByNameAll moves the cursor to the named entry in all directories where it exists.
+// SetCursorByNameAll moves the cursor to the named entry in all directories where it exists,
+// or else to the first entry named by the workspace path mapping rules.
 // [IMPL:DIFF_SEARCH] [ARCH:DIFF_SEARCH] [REQ:DIFF_SEARCH]
 func (w *Workspace) SetCursorByNameAll(name string) {
 	for _, d := range w.Dirs {
-		d.SetCursorByName(name)
+		d.setCursorByMappedName(name, w.mappedNames(d, name)) // [IMPL:LINKED_PATH_MAP] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_PATH_MAP]
 	}
 }
 
@@ -0,0 +1,97 @@
+package filer
+
+import "github.com/fareedst/goful/look"
+
+// WorkspaceSettings is the link and comparison state of a workspace. Goful
+// keeps these settings globally while a workspace is shown; they are captured
+// when leaving the workspace or saving the state, and restored when it is
+// shown again or loaded from the state. Sort order and view settings are kept
+// per directory.
+// [IMPL:WORKSPACE_SETTINGS] [ARCH:WORKSPACE_SETTINGS] [REQ:WORKSPACE_SETTINGS]
+type WorkspaceSettings struct {
+	Linked        bool `json:"linked"`
+	CompareColors bool `json:"compare_colors"`
+	ExcludeNames  bool `json:"exclude_names"`
+}
+
+// linkedNavSetter is a callback that sets the linked navigation mode.
+// [IMPL:WORKSPACE_SETTINGS] [ARCH:WORKSPACE_SETTINGS] [REQ:WORKSPACE_SETTINGS]
+var linkedNavSetter func(bool)
+
+// SetLinkedNavSetter sets the callback used to restore linked navigation mode.
+// [IMPL:WORKSPACE_SETTINGS] [ARCH:WORKSPACE_SETTINGS] [REQ:WORKSPACE_SETTINGS]
+func SetLinkedNavSetter(fn func(bool)) {
+	linkedNavSetter = fn
+}
+
+// CurrentSettings returns the settings in effect now.
+// [IMPL:WORKSPACE_SETTINGS] [ARCH:WORKSPACE_SETTINGS] [REQ:WORKSPACE_SETTINGS]
+func CurrentSettings() WorkspaceSettings {
+	linked := true
+	if linkedNavIndicator != nil {
+		linked = linkedNavIndicator()
+	}
+	excludedNamesMu.RLock()
+	exclude := excludeEnabled
+	excludedNamesMu.RUnlock()
+	return WorkspaceSettings{
+		Linked:        linked,
+		CompareColors: look.ComparisonEnabled(),
+		ExcludeNames:  exclude,
+	}
+}
+
+// Apply makes the settings take effect. The exclude filter stays off when
+// no exclude rules are loaded.
+// [IMPL:WORKSPACE_SETTINGS] [ARCH:WORKSPACE_SETTINGS] [REQ:WORKSPACE_SETTINGS]
+func (s WorkspaceSettings) Apply() {
+	if linkedNavSetter != nil {
+		linkedNavSetter(s.Linked)
+	}
+	look.SetComparisonEnabled(s.CompareColors)
+	excludedNamesMu.Lock()
+	excludeEnabled = s.ExcludeNames && len(excludedNames) > 0
+	excludedNamesMu.Unlock()
+}
+
+// SaveSettings captures the current settings into the workspace.
+// [IMPL:WORKSPACE_SETTINGS] [ARCH:WORKSPACE_SETTINGS] [REQ:WORKSPACE_SETTINGS]
+func (w *Workspace) SaveSettings() {
+	s := CurrentSettings()
+	w.Settings = &s
+}
+
+// RestoreSettings applies the settings captured in the workspace and reports
+// whether it had any. A workspace without settings keeps the current ones.
+// [IMPL:WORKSPACE_SETTINGS] [ARCH:WORKSPACE_SETTINGS] [REQ:WORKSPACE_SETTINGS]
+func (w *Workspace) RestoreSettings() bool {
+	if w.Settings == nil {
+		return false
+	}
+	w.Settings.Apply()
+	return true
+}
+
+// RestoreSettings applies the settings of the current workspace and reloads
+// it. Call it after configuration so saved settings override the defaults.
+// [IMPL:WORKSPACE_SETTINGS] [ARCH:WORKSPACE_SETTINGS] [REQ:WORKSPACE_SETTINGS]
+func (f *Filer) RestoreSettings() {
+	if f.Workspace().RestoreSettings() {
+		f.Workspace().ReloadAll()
+	}
+}
+
+// leaveWorkspace captures the settings of the current workspace before
+// another one is shown.
+func (f *Filer) leaveWorkspace() {
+	f.Workspace().SaveSettings()
+	f.Workspace().visible(false)
+}
+
+// enterWorkspace shows the current workspace with its own settings.
+func (f *Filer) enterWorkspace() {
+	if f.Workspace().RestoreSettings() {
+		f.Workspace().ReloadAll()
+	}
+	f.Workspace().visible(true)
+}
@@ -0,0 +1,108 @@
+package filer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/fareedst/goful/look"
+	"github.com/fareedst/goful/widget"
+)
+
+// useTestSettings resets the global settings and linked callbacks when the test ends.
+func useTestSettings(t *testing.T) *bool {
+	t.Helper()
+	saved := CurrentSettings()
+	names := ConfigureExcludedNames([]string{"skip"}, false)
+	if names != 1 {
+		t.Fatalf("ConfigureExcludedNames = %d", names)
+	}
+	linked := true
+	SetLinkedNavIndicator(func() bool { return linked })
+	SetLinkedNavSetter(func(v bool) { linked = v })
+	t.Cleanup(func() {
+		saved.Apply()
+		ConfigureExcludedNames(nil, false)
+		SetLinkedNavIndicator(nil)
+		SetLinkedNavSetter(nil)
+	})
+	return &linked
+}
+
+// newSettingsFiler builds a filer with two workspaces of one pane each.
+func newSettingsFiler(t *testing.T) *Filer {
+	t.Helper()
+	f := &Filer{Window: widget.NewWindow(0, 0, 80, 40)}
+	for _, title := range []string{"1", "2"} {
+		ws := NewWorkspace(0, 1, 80, 39, title)
+		ws.Dirs = append(ws.Dirs, newTestDirectory(t, t.TempDir()))
+		f.Workspaces = append(f.Workspaces, ws)
+	}
+	return f
+}
+
+// TestWorkspaceSettingsSwitch_REQ_WORKSPACE_SETTINGS tests that each workspace keeps its own settings.
+// [REQ:WORKSPACE_SETTINGS] [ARCH:WORKSPACE_SETTINGS] [IMPL:WORKSPACE_SETTINGS]
+func TestWorkspaceSettingsSwitch_REQ_WORKSPACE_SETTINGS(t *testing.T) {
+	linked := useTestSettings(t)
+	base := WorkspaceSettings{Linked: true, CompareColors: true}
+	base.Apply()
+	f := newSettingsFiler(t)
+
+	// Workspace 1 becomes a comparison workspace.
+	*linked = false
+	look.SetComparisonEnabled(false)
+	ToggleExcludedNames()
+	f.SwitchToWorkspace(1)
+
+	// Workspace 2 had no settings, so it keeps the current ones until changed.
+	if got := CurrentSettings(); got.Linked || got.CompareColors || !got.ExcludeNames {
+		t.Fatalf("workspace 2 should start with the current settings, got %+v", got)
+	}
+	base.Apply()
+	f.SwitchToWorkspace(0)
+
+	want := WorkspaceSettings{ExcludeNames: true}
+	if got := CurrentSettings(); got != want {
+		t.Errorf("workspace 1 settings = %+v, want %+v", got, want)
+	}
+	if *linked {
+		t.Error("linked navigation should be restored to off")
+	}
+	f.MoveWorkspace(1)
+	if got := CurrentSettings(); got != base {
+		t.Errorf("workspace 2 settings = %+v, want %+v", got, base)
+	}
+}
+
+// TestWorkspaceSettingsState_REQ_WORKSPACE_SETTINGS tests that settings survive SaveState and NewFromState.
+// [REQ:WORKSPACE_SETTINGS] [ARCH:WORKSPACE_SETTINGS] [IMPL:WORKSPACE_SETTINGS]
+func TestWorkspaceSettingsState_REQ_WORKSPACE_SETTINGS(t *testing.T) {
+	linked := useTestSettings(t)
+	f := newSettingsFiler(t)
+	f.Workspaces[1].Settings = &WorkspaceSettings{Linked: true, CompareColors: true}
+	want := WorkspaceSettings{CompareColors: true, ExcludeNames: true}
+	want.Apply()
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := f.SaveState(path); err != nil {
+		t.Fatalf("SaveState: %v", err)
+	}
+	WorkspaceSettings{Linked: true}.Apply()
+
+	loaded := NewFromState(path, 0, 0, 80, 40)
+	if len(loaded.Workspaces) != 2 {
+		t.Fatalf("loaded %d workspaces, want 2", len(loaded.Workspaces))
+	}
+	if !loaded.Workspace().RestoreSettings() {
+		t.Fatal("current workspace should have settings")
+	}
+	if got := CurrentSettings(); got != want {
+		t.Errorf("restored settings = %+v, want %+v", got, want)
+	}
+	if *linked {
+		t.Error("linked navigation should be restored to off")
+	}
+	if got := loaded.Workspaces[1].Settings; got == nil || *got != (WorkspaceSettings{Linked: true, CompareColors: true}) {
+		t.Errorf("workspace 2 settings = %+v", got)
+	}
+}
@@ -4,8 +4,10 @@
 package help
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/fareedst/goful/action"
 	"github.com/fareedst/goful/look"
 	"github.com/fareedst/goful/widget"
 	"github.com/gdamore/tcell/v2"
@@ -70,7 +72,13 @@ var keystrokeCatalog = []string{
 	"  S then c           Copy same-named files (new name)",
 	"  S then d           Delete same-named files",
 	"  S then r           Rename same-named files",
+	"  S with marks       c/d/r act on all marked names",
+	"  S then k           Make directory in every pane",
+	"  S then n           New file in every pane",
+	"  S then h           Chmod same-named files (0644, u+x)",
+	"  S then l           Symlink to same-named files",
 	"  S then !           Toggle ignore-failures mode",
+	"  S then a           Toggle all-or-nothing mode (rollback)",
 	"",
 	"=== Search & Filter ===",
 	"f, /                 Find (filter)",
@@ -80,13 +88,20 @@ var keystrokeCatalog = []string{
 	"",
 	"=== View & Compare ===",
 	"s                    Sort menu",
+	"  s then a/v/i/p/o   Natural/version/nocase/perm/owner sort",
 	"v                    View menu",
 	"E                    Toggle filename excludes",
 	"`                    Toggle comparison colors",
 	"=                    Calculate file digest",
 	"L, M-l               Toggle linked navigation",
+	"  v then m           Linked path mapping rules",
+	"  v then g           Link group of focused pane",
+	"  v then s then a    Apply focused pane view to all panes",
 	"[                    Start difference search",
 	"]                    Continue difference search",
+	"{                    Previous difference (reverse search)",
+	"}                    List all differences (results panel)",
+	"|                    Reconcile panes (preview, then copy)",
 	"",
 	"=== Menus & Commands ===",
 	"b                    Bookmark menu",
@@ -95,16 +110,36 @@ var keystrokeCatalog = []string{
 	"X                    External command menu",
 	";                    Shell",
 	":                    Shell suspend",
+	"!                    Shell with output in a popup",
+	"&                    Background jobs",
 	"",
 	"=== Application ===",
 	// [IMPL:VERSION_NUMBER] [ARCH:VERSION_DISPLAY] [REQ:VERSION_NUMBER]
 	"Version: 1.0.0",
 	"?                    Help (this popup)",
+	"M-r                  Reload configuration files",
 	"q, Q                 Quit",
 	"",
 	"Press ?, q, C-g, or Esc to close",
 }
 
+// catalog returns the keystroke catalog with a section listing the
+// registered actions before the closing line.
+// [IMPL:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [REQ:ACTION_REGISTRY]
+func catalog() []string {
+	actions := action.List()
+	if len(actions) == 0 {
+		return keystrokeCatalog
+	}
+	last := len(keystrokeCatalog) - 1
+	lines := append([]string{}, keystrokeCatalog[:last]...)
+	lines = append(lines, "=== Actions (keymaps, menus, commands) ===")
+	for _, a := range actions {
+		lines = append(lines, fmt.Sprintf("%-*s%s", keyColumnWidth, a.Name, a.Doc))
+	}
+	return append(lines, "", keystrokeCatalog[last])
+}
+
 // keyColumnWidth is the fixed width for the key binding column.
 // [IMPL:HELP_STYLING] [ARCH:HELP_STYLING] [REQ:HELP_POPUP_STYLING]
 const keyColumnWidth = 21
@@ -132,7 +167,7 @@ func New(filer widget.Widget) *Help {
 		height = screenHeight - 4
 	}
 	// Ensure we don't exceed content size + borders
-	maxHeight := len(keystrokeCatalog) + 2
+	maxHeight := len(catalog()) + 2
 	if height > maxHeight {
 		height = maxHeight
 	}
@@ -148,7 +183,7 @@ func New(filer widget.Widget) *Help {
 
 	// Populate the list with styled help entries
 	// [IMPL:HELP_STYLING] [ARCH:HELP_STYLING] [REQ:HELP_POPUP_STYLING]
-	for _, entry := range keystrokeCatalog {
+	for _, entry := range catalog() {
 		h.AppendList(newHelpEntry(entry))
 	}
 
@@ -171,7 +206,7 @@ func (h *Help) Resize(x, y, width, height int) {
 	if ht < 10 {
 		ht = screenHeight - 4
 	}
-	maxHeight := len(keystrokeCatalog) + 2
+	maxHeight := len(catalog()) + 2
 	if ht > maxHeight {
 		ht = maxHeight
 	}
@@ -1,18 +1,37 @@
 package externalmenu
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/fareedst/goful/action"
 	"github.com/fareedst/goful/app"
 	"github.com/fareedst/goful/externalcmd"
 	"github.com/fareedst/goful/menu"
 	"github.com/fareedst/goful/message"
+	"github.com/fareedst/goful/util"
 )
 
 const placeholderExternalCommandLabel = "no external commands configured"
 
 type shellInvoker func(cmd string, offset ...int)
 type menuOpener func(name string)
+type spawnInvoker func(cmd string)
+type paramPrompter func(params []externalcmd.Param, run func(values map[string]string))
+type captureShellInvoker func(cmd string, reload bool, offset ...int)
+type captureInvoker func(cmd string, reload bool)
+
+// invokers are the goful operations menu callbacks call; nil ones are skipped.
+type invokers struct {
+	shell        shellInvoker
+	openMenu     menuOpener
+	spawn        spawnInvoker
+	prompt       paramPrompter                // [IMPL:COMMAND_PARAMS]
+	captureShell captureShellInvoker          // [IMPL:COMMAND_OUTPUT_CAPTURE]
+	capture      captureInvoker               // [IMPL:COMMAND_OUTPUT_CAPTURE]
+	selection    func() externalcmd.Selection // [IMPL:COMMAND_CONDITIONS]
+	hasCommand   func(name string) bool       // [IMPL:COMMAND_CONDITIONS]
+}
 
 type menuSpec struct {
 	Menu        string
@@ -20,24 +39,64 @@ type menuSpec struct {
 	Label       string
 	Command     string
 	RunMenu     string
+	Action      string
+	Spawn       string
 	Offset      int
+	Params      []externalcmd.Param
+	Capture     bool
+	Reload      bool
+	When        externalcmd.Condition
 	Placeholder bool
 }
 
-// Register wires menu entries produced by the loader into goful.
+// Register wires menu entries produced by the loader into goful. Entries of
+// menus already defined in main.go extend them, replacing items with the same
+// key, unless the config lists the menu in ReplaceMenus. Entries naming
+// unknown actions are registered anyway and returned as errors.
 // [IMPL:EXTERNAL_COMMAND_BINDER] [ARCH:EXTERNAL_COMMAND_REGISTRY] [REQ:EXTERNAL_COMMAND_CONFIG]
-func Register(g *app.Goful, entries []externalcmd.Entry) {
-	specs := ensureMenuSpecs(buildMenuSpecs(entries))
-	argsByMenu := buildMenuArgs(specs,
-		func(cmd string, offset ...int) { g.Shell(cmd, offset...) },
-		func(name string) { g.Menu(name) },
-	)
+// [IMPL:CONFIG_MENUS] [ARCH:CONFIG_MENUS] [REQ:CONFIG_MENUS]
+func Register(g *app.Goful, cfg externalcmd.Config) []error {
+	specs := ensureMenuSpecs(buildMenuSpecs(cfg.Entries))
+	errs := unknownActions(specs)
+	argsByMenu := buildMenuArgs(specs, invokers{
+		shell:        g.Shell,
+		openMenu:     g.Menu,
+		spawn:        g.Spawn,
+		prompt:       g.PromptParams,
+		captureShell: g.ShellCapture,
+		capture:      g.Capture,
+		selection:    func() externalcmd.Selection { return selectionOf(g) },
+		hasCommand:   commandSearcher(),
+	})
+	replace := map[string]bool{externalcmd.MenuName: true}
+	for _, name := range cfg.ReplaceMenus {
+		replace[name] = true
+		menu.Remove(name)
+	}
 	for name, args := range argsByMenu {
 		if len(args) == 0 {
 			continue
 		}
-		menu.Add(name, args...)
+		if replace[name] {
+			menu.Remove(name)
+			menu.Add(name, args...)
+			continue
+		}
+		menu.Merge(name, args...)
+	}
+	return errs
+}
+
+// unknownActions reports the entries whose action is not registered.
+// [IMPL:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [REQ:ACTION_REGISTRY]
+func unknownActions(specs []menuSpec) []error {
+	var errs []error
+	for _, spec := range specs {
+		if spec.Action != "" && !action.Exists(spec.Action) {
+			errs = append(errs, fmt.Errorf("%s/%s: unknown action %q", spec.Menu, spec.Key, spec.Action))
+		}
 	}
+	return errs
 }
 
 func buildMenuSpecs(entries []externalcmd.Entry) []menuSpec {
@@ -56,7 +115,13 @@ func buildMenuSpecs(entries []externalcmd.Entry) []menuSpec {
 			Label:   entry.Label,
 			Command: entry.Command,
 			RunMenu: entry.RunMenu,
+			Action:  entry.Action,
+			Spawn:   entry.Spawn,
 			Offset:  entry.Offset,
+			Params:  entry.Params,
+			Capture: entry.Output == externalcmd.OutputCapture,
+			Reload:  entry.Reload,
+			When:    entry.When,
 		})
 	}
 	return specs
@@ -76,16 +141,32 @@ func ensureMenuSpecs(specs []menuSpec) []menuSpec {
 	}
 }
 
-func buildMenuArgs(specs []menuSpec, shell shellInvoker, openMenu menuOpener) map[string][]interface{} {
+func buildMenuArgs(specs []menuSpec, inv invokers) map[string][]interface{} {
 	argsByMenu := make(map[string][]interface{})
 	for _, spec := range specs {
-		callback := makeCommandCallback(spec, shell, openMenu)
+		// [IMPL:ACTION_REGISTRY] menu.Add resolves action names through the registry.
+		var callback interface{} = spec.Action
+		switch {
+		case len(spec.Params) > 0:
+			callback = withParams(spec, inv) // [IMPL:COMMAND_PARAMS]
+		case spec.Action == "":
+			callback = makeCommandCallback(spec, inv)
+		}
+		// [IMPL:COMMAND_CONDITIONS] [ARCH:COMMAND_CONDITIONS] [REQ:COMMAND_CONDITIONS]
+		if !spec.When.IsZero() && inv.selection != nil {
+			when := spec.When
+			callback = menu.Guarded{
+				Callback: callback,
+				Applies:  func() bool { return when.Applies(inv.selection(), inv.hasCommand) },
+				Disable:  when.Show == externalcmd.ShowDisable,
+			}
+		}
 		argsByMenu[spec.Menu] = append(argsByMenu[spec.Menu], spec.Key, spec.Label, callback)
 	}
 	return argsByMenu
 }
 
-func makeCommandCallback(spec menuSpec, shell shellInvoker, openMenu menuOpener) func() {
+func makeCommandCallback(spec menuSpec, inv invokers) func() {
 	if spec.Placeholder {
 		return func() {
 			message.Info("[REQ:EXTERNAL_COMMAND_CONFIG] No external commands configured. Provide -commands, set GOFUL_COMMANDS_FILE, or create " + externalcmd.MenuName + " entries to replace the defaults.")
@@ -93,11 +174,34 @@ func makeCommandCallback(spec menuSpec, shell shellInvoker, openMenu menuOpener)
 	}
 	if spec.RunMenu != "" {
 		return func() {
-			if openMenu != nil {
-				openMenu(spec.RunMenu)
+			if inv.openMenu != nil {
+				inv.openMenu(spec.RunMenu)
+			}
+		}
+	}
+	// [IMPL:COMMAND_OUTPUT_CAPTURE] [ARCH:COMMAND_OUTPUT_CAPTURE] [REQ:COMMAND_OUTPUT_CAPTURE]
+	if spec.Capture && spec.Spawn != "" {
+		return func() {
+			if inv.capture != nil {
+				inv.capture(spec.Spawn, spec.Reload)
+			}
+		}
+	}
+	// [IMPL:CONFIG_MENUS] [ARCH:CONFIG_MENUS] [REQ:CONFIG_MENUS]
+	if spec.Spawn != "" {
+		return func() {
+			if inv.spawn != nil {
+				inv.spawn(spec.Spawn)
 			}
 		}
 	}
+	shell := inv.shell
+	if spec.Capture {
+		shell = nil
+		if inv.captureShell != nil {
+			shell = func(cmd string, offset ...int) { inv.captureShell(cmd, spec.Reload, offset...) }
+		}
+	}
 	return func() {
 		if shell == nil || spec.Command == "" {
 			message.Errorf("[REQ:EXTERNAL_COMMAND_CONFIG] command is empty; skipping entry %s", spec.Key)
@@ -110,3 +214,52 @@ func makeCommandCallback(spec menuSpec, shell shellInvoker, openMenu menuOpener)
 		shell(spec.Command)
 	}
 }
+
+// withParams returns a callback that prompts for the params of spec and then
+// runs its command or spawn with %{name} references substituted.
+// [IMPL:COMMAND_PARAMS] [ARCH:COMMAND_PARAMS] [REQ:COMMAND_PARAMS]
+func withParams(spec menuSpec, inv invokers) func() {
+	return func() {
+		if inv.prompt == nil {
+			message.Errorf("[REQ:COMMAND_PARAMS] cannot prompt for parameters of %s", spec.Key)
+			return
+		}
+		inv.prompt(spec.Params, func(values map[string]string) {
+			expanded := spec
+			expanded.Command = externalcmd.ExpandParams(spec.Command, values)
+			expanded.Spawn = externalcmd.ExpandParams(spec.Spawn, values)
+			makeCommandCallback(expanded, inv)()
+		})
+	}
+}
+
+// selectionOf describes the focused pane of g for entry conditions.
+// [IMPL:COMMAND_CONDITIONS] [ARCH:COMMAND_CONDITIONS] [REQ:COMMAND_CONDITIONS]
+func selectionOf(g *app.Goful) externalcmd.Selection {
+	dir := g.Dir()
+	sel := externalcmd.Selection{
+		Dir:    dir.File().IsDir(),
+		Marked: dir.MarkCount(),
+		Panes:  len(g.Workspace().Dirs),
+	}
+	if sel.Marked > 0 {
+		sel.Files = dir.MarkfileNames()
+	} else {
+		sel.Files = []string{dir.File().Name()}
+	}
+	return sel
+}
+
+// commandSearcher returns a lookup of the commands on $PATH. The path is
+// searched once, the first time a condition needs it, and again after the
+// commands file is registered anew.
+// [IMPL:COMMAND_CONDITIONS] [ARCH:COMMAND_CONDITIONS] [REQ:COMMAND_CONDITIONS]
+func commandSearcher() func(name string) bool {
+	var commands map[string]bool
+	return func(name string) bool {
+		if commands == nil {
+			commands, _ = util.SearchCommands() // unreadable $PATH entries leave partial results
+		}
+		return commands[name]
+	}
+}
@@ -1,9 +1,12 @@
 package externalmenu
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/fareedst/goful/externalcmd"
+	"github.com/fareedst/goful/menu"
 )
 
 func TestEnsureMenuSpecsAddsPlaceholder_REQ_EXTERNAL_COMMAND_CONFIG(t *testing.T) {
@@ -28,12 +31,12 @@ func TestBuildMenuArgsInvokesShellWithOffset_REQ_EXTERNAL_COMMAND_CONFIG(t *test
 		calledCmd    string
 		calledOffset int
 	)
-	args := buildMenuArgs(specs, func(cmd string, offset ...int) {
+	args := buildMenuArgs(specs, invokers{shell: func(cmd string, offset ...int) {
 		calledCmd = cmd
 		if len(offset) > 0 {
 			calledOffset = offset[0]
 		}
-	}, nil)
+	}})
 
 	menuArgs := args[externalcmd.MenuName]
 	if len(menuArgs) != 3 {
@@ -56,11 +59,11 @@ func TestBuildMenuArgsInvokesMenuLauncher_REQ_EXTERNAL_COMMAND_CONFIG(t *testing
 		{Key: "A", Label: "archives", RunMenu: "archive"},
 	}
 	specs := ensureMenuSpecs(buildMenuSpecs(entries))
-	args := buildMenuArgs(specs, nil, func(name string) {
+	args := buildMenuArgs(specs, invokers{openMenu: func(name string) {
 		if name != "archive" {
 			t.Fatalf("unexpected menu name %q", name)
 		}
-	})
+	}})
 	menuArgs := args[externalcmd.MenuName]
 	if len(menuArgs) != 3 {
 		t.Fatalf("expected 3 values for menu entry, got %d", len(menuArgs))
@@ -68,3 +71,131 @@ func TestBuildMenuArgsInvokesMenuLauncher_REQ_EXTERNAL_COMMAND_CONFIG(t *testing
 	callback := menuArgs[2].(func())
 	callback() // should invoke menu opener without panic
 }
+
+func TestBuildMenuArgsPassesActionName_REQ_ACTION_REGISTRY(t *testing.T) {
+	// [REQ:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [IMPL:ACTION_REGISTRY]
+	entries := []externalcmd.Entry{
+		{Key: "c", Label: "copy", Action: "file.copy"},
+	}
+	args := buildMenuArgs(ensureMenuSpecs(buildMenuSpecs(entries)), invokers{})
+	menuArgs := args[externalcmd.MenuName]
+	if len(menuArgs) != 3 || menuArgs[2] != "file.copy" {
+		t.Fatalf("expected action name for menu.Add to resolve, got %v", menuArgs)
+	}
+}
+
+func TestBuildMenuArgsInvokesSpawn_REQ_CONFIG_MENUS(t *testing.T) {
+	// [REQ:CONFIG_MENUS] [ARCH:CONFIG_MENUS] [IMPL:CONFIG_MENUS]
+	entries := []externalcmd.Entry{
+		{Menu: "editor", Key: "h", Label: "helix", Spawn: "hx %f"},
+	}
+	var spawned string
+	args := buildMenuArgs(ensureMenuSpecs(buildMenuSpecs(entries)), invokers{spawn: func(cmd string) { spawned = cmd }})
+	menuArgs := args["editor"]
+	if len(menuArgs) != 3 {
+		t.Fatalf("expected 3 values for editor entry, got %v", menuArgs)
+	}
+	menuArgs[2].(func())()
+	if spawned != "hx %f" {
+		t.Fatalf("spawn invoker mismatch: %q", spawned)
+	}
+}
+
+func TestBuildMenuArgsPromptsForParams_REQ_COMMAND_PARAMS(t *testing.T) {
+	// [REQ:COMMAND_PARAMS] [ARCH:COMMAND_PARAMS] [IMPL:COMMAND_PARAMS]
+	entries := []externalcmd.Entry{
+		{Key: "d", Label: "deploy", Command: "deploy %{env} %f", Offset: -3,
+			Params: []externalcmd.Param{{Name: "env", Type: externalcmd.ParamChoice, Choices: []string{"dev", "prod"}}}},
+		{Key: "t", Label: "tag", Spawn: "tag %~{name} %&",
+			Params: []externalcmd.Param{{Name: "name"}}},
+	}
+	var (
+		prompted []string
+		shelled  string
+		offset   int
+		spawned  string
+	)
+	prompt := func(params []externalcmd.Param, run func(map[string]string)) {
+		prompted = append(prompted, params[0].Name)
+		run(map[string]string{params[0].Name: "prod"})
+	}
+	args := buildMenuArgs(ensureMenuSpecs(buildMenuSpecs(entries)), invokers{
+		shell:  func(cmd string, o ...int) { shelled, offset = cmd, o[0] },
+		spawn:  func(cmd string) { spawned = cmd },
+		prompt: prompt,
+	})
+	menuArgs := args[externalcmd.MenuName]
+	menuArgs[2].(func())()
+	menuArgs[5].(func())()
+	if strings.Join(prompted, ",") != "env,name" {
+		t.Fatalf("expected prompts for env and name, got %v", prompted)
+	}
+	if shelled != `deploy "prod" %f` || offset != -3 {
+		t.Fatalf("shell invoker mismatch: cmd=%q offset=%d", shelled, offset)
+	}
+	if spawned != "tag prod %&" {
+		t.Fatalf("spawn invoker mismatch: %q", spawned)
+	}
+}
+
+func TestBuildMenuArgsCapturesOutput_REQ_COMMAND_OUTPUT_CAPTURE(t *testing.T) {
+	// [REQ:COMMAND_OUTPUT_CAPTURE] [ARCH:COMMAND_OUTPUT_CAPTURE] [IMPL:COMMAND_OUTPUT_CAPTURE]
+	entries := []externalcmd.Entry{
+		{Key: "l", Label: "git log", Command: "git log -5 %f", Offset: -3, Output: externalcmd.OutputCapture},
+		{Key: "s", Label: "git status", Spawn: "git status", Output: externalcmd.OutputCapture, Reload: true},
+		{Key: "t", Label: "terminal", Spawn: "top"},
+	}
+	var calls []string
+	args := buildMenuArgs(ensureMenuSpecs(buildMenuSpecs(entries)), invokers{
+		shell: func(cmd string, offset ...int) { calls = append(calls, "shell "+cmd) },
+		spawn: func(cmd string) { calls = append(calls, "spawn "+cmd) },
+		captureShell: func(cmd string, reload bool, offset ...int) {
+			calls = append(calls, fmt.Sprintf("capture-shell %s %v %v", cmd, reload, offset))
+		},
+		capture: func(cmd string, reload bool) {
+			calls = append(calls, fmt.Sprintf("capture %s %v", cmd, reload))
+		},
+	})
+	menuArgs := args[externalcmd.MenuName]
+	for i := 2; i < len(menuArgs); i += 3 {
+		menuArgs[i].(func())()
+	}
+	want := "capture-shell git log -5 %f false [-3],capture git status true,spawn top"
+	if got := strings.Join(calls, ","); got != want {
+		t.Fatalf("calls = %q, want %q", got, want)
+	}
+}
+
+func TestBuildMenuArgsGuardsConditions_REQ_COMMAND_CONDITIONS(t *testing.T) {
+	// [REQ:COMMAND_CONDITIONS] [ARCH:COMMAND_CONDITIONS] [IMPL:COMMAND_CONDITIONS]
+	entries := []externalcmd.Entry{
+		{Key: "x", Label: "extract", Command: "tar xf %f", When: externalcmd.Condition{Ext: []string{".tar"}, Executable: []string{"tar"}, Show: externalcmd.ShowDisable}},
+		{Key: "l", Label: "list", Command: "ls"},
+	}
+	sel := externalcmd.Selection{Files: []string{"a.tar"}}
+	var calls []string
+	args := buildMenuArgs(buildMenuSpecs(entries), invokers{
+		shell:      func(cmd string, offset ...int) { calls = append(calls, cmd) },
+		selection:  func() externalcmd.Selection { return sel },
+		hasCommand: func(name string) bool { return name == "tar" },
+	})
+	menuArgs := args[externalcmd.MenuName]
+	guarded, ok := menuArgs[2].(menu.Guarded)
+	if !ok || !guarded.Disable {
+		t.Fatalf("conditional entry callback = %#v, want a disabling menu.Guarded", menuArgs[2])
+	}
+	if _, ok := menuArgs[5].(func()); !ok {
+		t.Errorf("unconditional entry should not be guarded: %#v", menuArgs[5])
+	}
+	if !guarded.Applies() {
+		t.Error("condition should apply to a.tar")
+	}
+	sel.Files = []string{"a.zip"}
+	if guarded.Applies() {
+		t.Error("condition is evaluated on every call and should not apply to a.zip")
+	}
+	guarded.Callback.(func())()
+	if strings.Join(calls, ",") != "tar xf %f" {
+		t.Errorf("calls = %q", calls)
+	}
+}
@@ -0,0 +1,195 @@
+// Package jobs tracks background processes started by goful and provides a
+// popup listing them.
+// [IMPL:BACKGROUND_JOBS] [ARCH:BACKGROUND_JOBS] [REQ:BACKGROUND_JOBS]
+package jobs
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fareedst/goful/util"
+)
+
+// tailSize is the number of bytes of output kept for each stream of a job.
+const tailSize = 4096
+
+// waitDelay bounds how long Wait blocks on the output pipes after a job
+// exits, in case a process it started keeps them open.
+const waitDelay = 2 * time.Second
+
+// maxFinished is the number of finished jobs a List keeps; Start drops the
+// oldest ones beyond it.
+const maxFinished = 50
+
+// Job is one background process.
+// [IMPL:BACKGROUND_JOBS] [ARCH:BACKGROUND_JOBS] [REQ:BACKGROUND_JOBS]
+type Job struct {
+	ID      int
+	PID     int
+	Command string
+	Start   time.Time
+	End     time.Time
+	Status  string // "running" until Finish
+	Failed  bool
+	cmd     *exec.Cmd
+	stdout  *Tail
+	stderr  *Tail
+}
+
+// Running reports whether the job has not finished yet.
+func (j *Job) Running() bool { return j.End.IsZero() }
+
+// Duration returns how long the job ran, or has been running.
+func (j *Job) Duration() time.Duration {
+	if j.Running() {
+		return time.Since(j.Start)
+	}
+	return j.End.Sub(j.Start)
+}
+
+// Stdout returns the last lines written to standard output.
+func (j *Job) Stdout(n int) []string { return j.stdout.Lines(n) }
+
+// Stderr returns the last lines written to standard error.
+func (j *Job) Stderr(n int) []string { return j.stderr.Lines(n) }
+
+// Kill stops a running job together with the processes it started.
+// [IMPL:BACKGROUND_JOBS] [ARCH:BACKGROUND_JOBS] [REQ:BACKGROUND_JOBS]
+func (j *Job) Kill() error {
+	if !j.Running() {
+		return fmt.Errorf("job %d has already finished", j.ID)
+	}
+	return util.KillProcessGroup(j.cmd)
+}
+
+// Finish records the end of the job with status such as "exit 0". failed
+// marks a non-zero exit or a wait error.
+// [IMPL:BACKGROUND_JOBS] [ARCH:BACKGROUND_JOBS] [REQ:BACKGROUND_JOBS]
+func (j *Job) Finish(status string, failed bool) {
+	j.End = time.Now()
+	j.Status = status
+	j.Failed = failed
+}
+
+// List holds the jobs of a session in start order. It is used from the
+// event loop only; the processes report back through Finish.
+// [IMPL:BACKGROUND_JOBS] [ARCH:BACKGROUND_JOBS] [REQ:BACKGROUND_JOBS]
+type List struct {
+	jobs   []*Job
+	lastID int
+}
+
+// Start starts cmd in a process group of its own, keeping the tail of its
+// output, and adds it to the list. command is the text shown for the job.
+// Finished jobs beyond maxFinished are dropped, oldest first.
+// [IMPL:BACKGROUND_JOBS] [ARCH:BACKGROUND_JOBS] [REQ:BACKGROUND_JOBS]
+func (l *List) Start(cmd *exec.Cmd, command string) (*Job, error) {
+	job := &Job{
+		Command: command,
+		Status:  "running",
+		cmd:     cmd,
+		stdout:  &Tail{},
+		stderr:  &Tail{},
+	}
+	cmd.Stdout = job.stdout
+	cmd.Stderr = job.stderr
+	cmd.WaitDelay = waitDelay
+	util.SetProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	l.lastID++
+	job.ID = l.lastID
+	job.PID = cmd.Process.Pid
+	job.Start = time.Now()
+	l.jobs = append(l.jobs, job)
+	l.trimFinished()
+	return job, nil
+}
+
+// trimFinished drops the oldest finished jobs beyond maxFinished.
+func (l *List) trimFinished() {
+	finished := len(l.jobs) - l.Running()
+	if finished <= maxFinished {
+		return
+	}
+	drop := finished - maxFinished
+	kept := l.jobs[:0]
+	for _, job := range l.jobs {
+		if drop > 0 && !job.Running() {
+			drop--
+			continue
+		}
+		kept = append(kept, job)
+	}
+	for i := len(kept); i < len(l.jobs); i++ {
+		l.jobs[i] = nil
+	}
+	l.jobs = kept
+}
+
+// Jobs returns the jobs in start order.
+func (l *List) Jobs() []*Job { return append([]*Job(nil), l.jobs...) }
+
+// Running returns the number of running jobs.
+func (l *List) Running() int {
+	n := 0
+	for _, job := range l.jobs {
+		if job.Running() {
+			n++
+		}
+	}
+	return n
+}
+
+// ClearFinished removes the finished jobs.
+// [IMPL:BACKGROUND_JOBS] [ARCH:BACKGROUND_JOBS] [REQ:BACKGROUND_JOBS]
+func (l *List) ClearFinished() {
+	running := l.jobs[:0]
+	for _, job := range l.jobs {
+		if job.Running() {
+			running = append(running, job)
+		}
+	}
+	for i := len(running); i < len(l.jobs); i++ {
+		l.jobs[i] = nil
+	}
+	l.jobs = running
+}
+
+// Tail is a writer keeping the last bytes written to it.
+type Tail struct {
+	mu  sync.Mutex
+	buf []byte
+}
+
+// Write implements io.Writer.
+func (t *Tail) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.buf = append(t.buf, p...)
+	if over := len(t.buf) - tailSize; over > 0 {
+		t.buf = append(t.buf[:0], t.buf[over:]...)
+	}
+	return len(p), nil
+}
+
+// Lines returns up to n last non-blank lines.
+func (t *Tail) Lines(n int) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var lines []string
+	for _, line := range bytes.Split(t.buf, []byte("\n")) {
+		if s := strings.TrimSpace(string(line)); s != "" {
+			lines = append(lines, s)
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
@@ -0,0 +1,117 @@
+package jobs
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestList_REQ_BACKGROUND_JOBS verifies starting, finishing, killing and
+// clearing jobs and the output tails.
+// [IMPL:BACKGROUND_JOBS] [ARCH:BACKGROUND_JOBS] [REQ:BACKGROUND_JOBS]
+func TestList_REQ_BACKGROUND_JOBS(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	l := &List{}
+	failing := exec.Command(sh, "-c", "echo out; echo one >&2; echo two >&2; exit 2")
+	job, err := l.Start(failing, "failing")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if job.ID != 1 || job.PID == 0 || !job.Running() || l.Running() != 1 {
+		t.Fatalf("job = %+v, running = %d", job, l.Running())
+	}
+	errWait := failing.Wait()
+	job.Finish("exit 2", errWait != nil)
+	if job.Running() || !job.Failed || job.Status != "exit 2" {
+		t.Errorf("finished job = %+v", job)
+	}
+	if got := strings.Join(job.Stderr(3), "|"); got != "one|two" {
+		t.Errorf("stderr = %q", got)
+	}
+	if got := strings.Join(job.Stderr(1), "|"); got != "two" {
+		t.Errorf("stderr tail = %q", got)
+	}
+	if got := strings.Join(job.Stdout(3), "|"); got != "out" {
+		t.Errorf("stdout = %q", got)
+	}
+	if err := job.Kill(); err == nil {
+		t.Error("killing a finished job should fail")
+	}
+
+	// The background sleep shares the output pipes; killing only the shell
+	// would leave Wait blocked until waitDelay.
+	sleeping := exec.Command(sh, "-c", "sleep 10 & sleep 10")
+	running, err := l.Start(sleeping, "sleep 10")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if running.ID != 2 {
+		t.Errorf("second job ID = %d, want 2", running.ID)
+	}
+	if err := running.Kill(); err != nil {
+		t.Fatalf("Kill: %v", err)
+	}
+	start := time.Now()
+	if err := sleeping.Wait(); err == nil {
+		t.Error("killed job should exit with an error")
+	}
+	if elapsed := time.Since(start); elapsed >= waitDelay {
+		t.Errorf("Wait took %v: the children of the job were not killed", elapsed)
+	}
+
+	l.ClearFinished()
+	if jobs := l.Jobs(); len(jobs) != 1 || jobs[0] != running {
+		t.Errorf("ClearFinished kept %d jobs, want only the running one", len(jobs))
+	}
+	if !strings.Contains(FormatJob(running), "sleep 10") {
+		t.Errorf("FormatJob = %q", FormatJob(running))
+	}
+}
+
+// TestListTrimFinished_REQ_BACKGROUND_JOBS verifies that Start keeps at most
+// maxFinished finished jobs, dropping the oldest, and keeps running ones.
+// [IMPL:BACKGROUND_JOBS] [ARCH:BACKGROUND_JOBS] [REQ:BACKGROUND_JOBS]
+func TestListTrimFinished_REQ_BACKGROUND_JOBS(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	l := &List{}
+	running := &Job{ID: 1, Status: "running"}
+	l.jobs = append(l.jobs, running)
+	for id := 2; id <= maxFinished+3; id++ {
+		l.jobs = append(l.jobs, &Job{ID: id, End: time.Now(), Status: "exit 0"})
+	}
+	l.lastID = maxFinished + 3
+	cmd := exec.Command(sh, "-c", "exit 0")
+	job, err := l.Start(cmd, "exit 0")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	cmd.Wait()
+	jobs := l.Jobs()
+	if len(jobs) != maxFinished+2 {
+		t.Fatalf("len(jobs) = %d, want %d", len(jobs), maxFinished+2)
+	}
+	if jobs[0] != running || jobs[1].ID != 4 || jobs[len(jobs)-1] != job {
+		t.Errorf("kept jobs %d, %d ... %d", jobs[0].ID, jobs[1].ID, jobs[len(jobs)-1].ID)
+	}
+}
+
+// TestTail_REQ_BACKGROUND_JOBS verifies that only the last bytes are kept.
+// [IMPL:BACKGROUND_JOBS] [ARCH:BACKGROUND_JOBS] [REQ:BACKGROUND_JOBS]
+func TestTail_REQ_BACKGROUND_JOBS(t *testing.T) {
+	tail := &Tail{}
+	tail.Write([]byte(strings.Repeat("x", tailSize) + "\n"))
+	tail.Write([]byte("\nlast line\n"))
+	if len(tail.buf) != tailSize {
+		t.Errorf("kept %d bytes, want %d", len(tail.buf), tailSize)
+	}
+	if got := tail.Lines(1); len(got) != 1 || got[0] != "last line" {
+		t.Errorf("Lines(1) = %q", got)
+	}
+}
@@ -0,0 +1,167 @@
+package jobs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fareedst/goful/look"
+	"github.com/fareedst/goful/message"
+	"github.com/fareedst/goful/widget"
+	"github.com/mattn/go-runewidth"
+)
+
+// Panel is a popup list box showing one row per job.
+// [IMPL:BACKGROUND_JOBS] [ARCH:BACKGROUND_JOBS] [REQ:BACKGROUND_JOBS]
+type Panel struct {
+	*widget.ListBox
+	filer    widget.Widget
+	list     *List
+	onSelect func(*Job)
+}
+
+// NewPanel creates a panel for the jobs of list based on the screen size.
+// onSelect is called with the job under the cursor by C-m.
+// [IMPL:BACKGROUND_JOBS] [ARCH:BACKGROUND_JOBS] [REQ:BACKGROUND_JOBS]
+func NewPanel(filer widget.Widget, list *List, onSelect func(*Job)) *Panel {
+	x, y, width, height := widget.PopupGeometry(len(list.jobs))
+	p := &Panel{
+		ListBox:  widget.NewListBox(x, y, width, height, ""),
+		filer:    filer,
+		list:     list,
+		onSelect: onSelect,
+	}
+	p.SetBorderStyle(widget.AllBorder)
+	p.reload()
+	return p
+}
+
+// Resize keeps the panel centered on the screen.
+func (p *Panel) Resize(x, y, width, height int) {
+	p.ListBox.Resize(widget.PopupGeometry(p.Upper()))
+}
+
+// reload rebuilds the rows from the list, keeping the cursor row.
+func (p *Panel) reload() {
+	cursor := p.Cursor()
+	p.ClearList()
+	for _, job := range p.list.jobs {
+		p.AppendList(&row{job})
+	}
+	p.SetCursor(cursor)
+	p.SetTitle(fmt.Sprintf("Jobs (%d running)", p.list.Running()))
+}
+
+// Input handles keyboard input for the jobs panel. "K" kills the job under
+// the cursor and "D" removes the finished jobs.
+// [IMPL:BACKGROUND_JOBS] [ARCH:BACKGROUND_JOBS] [REQ:BACKGROUND_JOBS]
+func (p *Panel) Input(key string) {
+	switch key {
+	case "q", "Q", "C-g", "C-[":
+		p.Exit()
+	case "C-m", "o":
+		p.Select()
+	case "K", "delete":
+		p.Kill()
+	case "D":
+		p.list.ClearFinished()
+		if len(p.list.jobs) == 0 {
+			p.Exit()
+			return
+		}
+		p.reload()
+	case "C-n", "down", "j":
+		p.MoveCursor(1)
+	case "C-p", "up", "k":
+		p.MoveCursor(-1)
+	case "C-v", "pgdn":
+		p.PageDown()
+	case "M-v", "pgup":
+		p.PageUp()
+	case "C-a", "home", "^":
+		p.MoveTop()
+	case "C-e", "end", "$":
+		p.MoveBottom()
+	}
+}
+
+// Job returns the job under the cursor.
+func (p *Panel) Job() (*Job, bool) {
+	if p.IsEmpty() {
+		return nil, false
+	}
+	r, ok := p.CurrentContent().(*row)
+	if !ok {
+		return nil, false
+	}
+	return r.job, true
+}
+
+// Kill stops the running job under the cursor.
+// [IMPL:BACKGROUND_JOBS] [ARCH:BACKGROUND_JOBS] [REQ:BACKGROUND_JOBS]
+func (p *Panel) Kill() {
+	job, ok := p.Job()
+	if !ok {
+		return
+	}
+	if err := job.Kill(); err != nil {
+		message.Error(err)
+	}
+}
+
+// Select calls onSelect with the job under the cursor.
+func (p *Panel) Select() {
+	if job, ok := p.Job(); ok && p.onSelect != nil {
+		p.onSelect(job)
+	}
+}
+
+// Draw refreshes the title before drawing, as jobs finish while it is open.
+func (p *Panel) Draw() {
+	p.SetTitle(fmt.Sprintf("Jobs (%d running)", p.list.Running()))
+	p.ListBox.Draw()
+}
+
+// Exit closes the panel and returns to the filer.
+func (p *Panel) Exit() {
+	p.filer.Disconnect()
+}
+
+// Next implements widget.Widget.
+func (p *Panel) Next() widget.Widget {
+	return widget.Nil()
+}
+
+// Disconnect implements widget.Widget.
+func (p *Panel) Disconnect() {}
+
+// row draws one job: running jobs use the executable style and failed jobs
+// the error style.
+type row struct {
+	job *Job
+}
+
+// Name returns the job command for ListBox compatibility.
+func (r *row) Name() string { return r.job.Command }
+
+// Draw renders the row.
+func (r *row) Draw(x, y, width int, focus bool) {
+	style := look.Default()
+	switch {
+	case r.job.Running():
+		style = look.Executable()
+	case r.job.Failed:
+		style = look.MessageError()
+	}
+	if focus {
+		style = style.Reverse(true)
+	}
+	s := runewidth.Truncate(FormatJob(r.job), width, "~")
+	widget.SetCells(x, y, runewidth.FillRight(s, width), style)
+}
+
+// FormatJob lays out a job as its number, status, PID, duration and command.
+// [IMPL:BACKGROUND_JOBS] [ARCH:BACKGROUND_JOBS] [REQ:BACKGROUND_JOBS]
+func FormatJob(j *Job) string {
+	return fmt.Sprintf("%3d  %-14s %7d %8s  %s",
+		j.ID, j.Status, j.PID, j.Duration().Round(time.Second), j.Command)
+}
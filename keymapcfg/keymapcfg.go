@@ -0,0 +1,163 @@
+// Package keymapcfg loads user key bindings that map key strings to named
+// actions for each input context.
+// [IMPL:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [REQ:USER_KEYMAPS]
+package keymapcfg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fareedst/goful/util"
+	"github.com/fareedst/goful/widget"
+	"gopkg.in/yaml.v3"
+)
+
+// Input contexts that accept user bindings.
+const (
+	Filer      = "filer"
+	Cmdline    = "cmdline"
+	Finder     = "finder"
+	Completion = "completion"
+	Menu       = "menu"
+)
+
+// Contexts lists the input contexts in file order.
+var Contexts = []string{Filer, Cmdline, Finder, Completion, Menu}
+
+// Unbind is the action that removes a key binding.
+const Unbind = "-"
+
+// MenuPrefix names an action opening a menu, as in "menu:sort".
+const MenuPrefix = "menu:"
+
+// Config maps each context to its key bindings (key string to action name).
+type Config map[string]map[string]string
+
+// Load reads the keymap file at path. A missing file yields an empty config.
+// [IMPL:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [REQ:USER_KEYMAPS]
+func Load(path string) (Config, error) {
+	path = util.ExpandPath(strings.TrimSpace(path))
+	if path == "" {
+		return Config{}, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Config{}, nil
+		}
+		return Config{}, fmt.Errorf("read keymaps %s: %w", path, err)
+	}
+	cfg, err := Parse(data)
+	if err != nil {
+		return Config{}, fmt.Errorf("parse keymaps %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Parse decodes YAML or JSON keymaps and rejects unknown contexts.
+// [IMPL:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [REQ:USER_KEYMAPS]
+func Parse(data []byte) (Config, error) {
+	cfg := Config{}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, err
+	}
+	for context := range cfg {
+		if !isContext(context) {
+			return Config{}, fmt.Errorf("unknown context %q (want one of %s)", context, strings.Join(Contexts, ", "))
+		}
+	}
+	return cfg, nil
+}
+
+func isContext(s string) bool {
+	for _, c := range Contexts {
+		if c == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate reports bindings with invalid key strings, unknown actions or
+// unknown menus, in context and key order. actions maps each context to its
+// named actions; menuExists checks "menu:" actions in the filer context.
+// [IMPL:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [REQ:USER_KEYMAPS]
+func (c Config) Validate(actions map[string]map[string]func(), menuExists func(string) bool) []error {
+	var errs []error
+	for _, context := range Contexts {
+		bindings := c[context]
+		keys := make([]string, 0, len(bindings))
+		for key := range bindings {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			action := bindings[key]
+			switch {
+			case !widget.IsKeyString(key):
+				errs = append(errs, fmt.Errorf("%s: invalid key %q", context, key))
+			case action == Unbind:
+			case context == Filer && strings.HasPrefix(action, MenuPrefix):
+				if name := strings.TrimPrefix(action, MenuPrefix); menuExists == nil || !menuExists(name) {
+					errs = append(errs, fmt.Errorf("%s: %s: unknown menu %q", context, key, name))
+				}
+			case actions[context][action] == nil:
+				errs = append(errs, fmt.Errorf("%s: %s: unknown action %q", context, key, action))
+			}
+		}
+	}
+	return errs
+}
+
+// Apply binds the configured keys of context in km to their actions and
+// deletes keys bound to Unbind. Invalid bindings are skipped; Validate
+// reports them. openMenu handles "menu:" actions and may be nil.
+// [IMPL:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [REQ:USER_KEYMAPS]
+func (c Config) Apply(context string, km widget.Keymap, actions map[string]func(), openMenu func(string)) widget.Keymap {
+	for key, action := range c[context] {
+		if !widget.IsKeyString(key) {
+			continue
+		}
+		if action == Unbind {
+			delete(km, key)
+			continue
+		}
+		if name := strings.TrimPrefix(action, MenuPrefix); name != action && openMenu != nil {
+			km[key] = func() { openMenu(name) }
+			continue
+		}
+		if callback := actions[action]; callback != nil {
+			km[key] = callback
+		}
+	}
+	return km
+}
+
+// Unbound returns the keys of context bound to Unbind.
+// [IMPL:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [REQ:USER_KEYMAPS]
+func (c Config) Unbound(context string) []string {
+	keys := []string{}
+	for key, action := range c[context] {
+		if action == Unbind {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Actions names the callbacks of a default keymap: each action resolves to
+// the callback bound to its default key.
+// [IMPL:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [REQ:USER_KEYMAPS]
+func Actions(defaults widget.Keymap, keys map[string]string) map[string]func() {
+	actions := make(map[string]func(), len(keys))
+	for name, key := range keys {
+		if callback, ok := defaults[key]; ok {
+			actions[name] = callback
+		}
+	}
+	return actions
+}
@@ -0,0 +1,119 @@
+package keymapcfg
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fareedst/goful/widget"
+)
+
+const sample = `
+filer:
+  "C-j": filer.cursor-down
+  "x": "menu:sort"
+  "Q": "-"
+cmdline:
+  "C-u": cmdline.kill-line
+`
+
+// TestLoad_REQ_USER_KEYMAPS tests loading YAML and JSON files, missing files and unknown contexts.
+// [REQ:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [IMPL:USER_KEYMAPS]
+func TestLoad_REQ_USER_KEYMAPS(t *testing.T) {
+	dir := t.TempDir()
+	cfg, err := Load(filepath.Join(dir, "missing.yaml"))
+	if err != nil || len(cfg) != 0 {
+		t.Fatalf("missing file: cfg=%v err=%v", cfg, err)
+	}
+
+	yamlPath := filepath.Join(dir, "keymaps.yaml")
+	os.WriteFile(yamlPath, []byte(sample), 0o644)
+	cfg, err = Load(yamlPath)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg[Filer]["C-j"] != "filer.cursor-down" || cfg[Cmdline]["C-u"] != "cmdline.kill-line" {
+		t.Errorf("loaded %v", cfg)
+	}
+
+	jsonPath := filepath.Join(dir, "keymaps.json")
+	os.WriteFile(jsonPath, []byte(`{"menu": {"j": "menu.cursor-down"}}`), 0o644)
+	if cfg, err := Load(jsonPath); err != nil || cfg[Menu]["j"] != "menu.cursor-down" {
+		t.Errorf("json: cfg=%v err=%v", cfg, err)
+	}
+
+	if _, err := Parse([]byte("dialog:\n  y: yes\n")); err == nil || !strings.Contains(err.Error(), "dialog") {
+		t.Errorf("unknown context should fail, got %v", err)
+	}
+	if _, err := Parse([]byte("filer: [j]\n")); err == nil {
+		t.Error("a list instead of bindings should fail")
+	}
+}
+
+// TestValidate_REQ_USER_KEYMAPS tests that invalid keys, actions and menus are reported.
+// [REQ:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [IMPL:USER_KEYMAPS]
+func TestValidate_REQ_USER_KEYMAPS(t *testing.T) {
+	noop := func() {}
+	actions := map[string]map[string]func(){
+		Filer:   {"filer.cursor-down": noop},
+		Cmdline: {"cmdline.kill-line": noop},
+	}
+	cfg := Config{
+		Filer: {
+			"C-j":    "filer.cursor-down",
+			"Ctrl-j": "filer.cursor-down",
+			"z":      "launch-rockets",
+			"x":      "menu:sort",
+			"y":      "menu:nope",
+			"Q":      Unbind,
+		},
+		Cmdline: {"C-u": "cmdline.kill-line", "C-y": "menu:sort"},
+	}
+	errs := cfg.Validate(actions, func(name string) bool { return name == "sort" })
+	var got []string
+	for _, err := range errs {
+		got = append(got, err.Error())
+	}
+	want := []string{
+		`filer: invalid key "Ctrl-j"`,
+		`filer: y: unknown menu "nope"`,
+		`filer: z: unknown action "launch-rockets"`,
+		`cmdline: C-y: unknown action "menu:sort"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Validate =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// TestApply_REQ_USER_KEYMAPS tests binding, unbinding and menu actions.
+// [REQ:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [IMPL:USER_KEYMAPS]
+func TestApply_REQ_USER_KEYMAPS(t *testing.T) {
+	calls := []string{}
+	defaults := widget.Keymap{
+		"j": func() { calls = append(calls, "down") },
+		"Q": func() { calls = append(calls, "quit") },
+	}
+	cfg, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actions := Actions(defaults, map[string]string{"filer.cursor-down": "j", "missing": "none"})
+	if _, ok := actions["missing"]; ok {
+		t.Error("an action whose default key is unbound should not resolve")
+	}
+
+	km := cfg.Apply(Filer, defaults, actions, func(name string) { calls = append(calls, "menu "+name) })
+	if _, ok := km["Q"]; ok {
+		t.Error("Q should be unbound")
+	}
+	km["C-j"]()
+	km["x"]()
+	km["j"]()
+	if strings.Join(calls, ",") != "down,menu sort,down" {
+		t.Errorf("calls = %v", calls)
+	}
+	if got := cfg.Unbound(Filer); len(got) != 1 || got[0] != "Q" {
+		t.Errorf("Unbound = %v", got)
+	}
+}
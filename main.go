@@ -7,19 +7,24 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/fareedst/goful/action"
 	"github.com/fareedst/goful/app"
+	"github.com/fareedst/goful/assoc"
 	"github.com/fareedst/goful/cmdline"
+	"github.com/fareedst/goful/configfile"
 	"github.com/fareedst/goful/configpaths"
 	"github.com/fareedst/goful/diffstatus"
 	"github.com/fareedst/goful/externalcmd"
 	"github.com/fareedst/goful/filer"
 	"github.com/fareedst/goful/filer/comparecolors"
 	"github.com/fareedst/goful/internal/externalmenu"
+	"github.com/fareedst/goful/keymapcfg"
 	"github.com/fareedst/goful/look"
 	"github.com/fareedst/goful/menu"
 	"github.com/fareedst/goful/message"
@@ -35,15 +40,21 @@ const debugWorkspaceEnv = "GOFUL_DEBUG_WORKSPACE"
 const version = "1.0.0"
 
 var (
+	// [IMPL:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [REQ:UNIFIED_CONFIG]
+	configFlag = flag.String(
+		"config",
+		"",
+		"Override path to the unified config file (default $"+configpaths.EnvXDGConfigHome+"/goful/config.yaml, then "+configpaths.DefaultConfigPath+", or "+configpaths.EnvConfigKey+")",
+	)
 	stateFlag = flag.String(
 		"state",
 		"",
-		"Override path to state.json (default "+configpaths.DefaultStatePath+" or "+configpaths.EnvStateKey+")",
+		"Override path to state.json (default $"+configpaths.EnvXDGStateHome+"/goful/state.json, "+configpaths.DefaultStatePath+" if it exists, or "+configpaths.EnvStateKey+")",
 	)
 	historyFlag = flag.String(
 		"history",
 		"",
-		"Override path to cmdline history (default "+configpaths.DefaultHistoryPath+" or "+configpaths.EnvHistoryKey+")",
+		"Override path to cmdline history (default $"+configpaths.EnvXDGStateHome+"/goful/history/shell, "+configpaths.DefaultHistoryPath+" if it exists, or "+configpaths.EnvHistoryKey+")",
 	)
 	commandsFlag = flag.String(
 		"commands",
@@ -61,11 +72,35 @@ var (
 		"",
 		"Override path to comparison colors config (default "+configpaths.DefaultCompareColorsPath+" or "+configpaths.EnvCompareColorsKey+")",
 	)
+	// [IMPL:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [REQ:USER_KEYMAPS]
+	keymapsFlag = flag.String(
+		"keymaps",
+		"",
+		"Override path to user key bindings (default "+configpaths.DefaultKeymapsPath+" or "+configpaths.EnvKeymapsKey+")",
+	)
+	// [IMPL:FILE_ASSOCIATIONS] [ARCH:FILE_ASSOCIATIONS] [REQ:FILE_ASSOCIATIONS]
+	associationsFlag = flag.String(
+		"associations",
+		"",
+		"Override path to file-type associations (default "+configpaths.DefaultAssociationsPath+" or "+configpaths.EnvAssociationsKey+")",
+	)
 	// [IMPL:BATCH_DIFF_REPORT] [ARCH:BATCH_DIFF_REPORT] [REQ:BATCH_DIFF_REPORT]
 	diffReportFlag = flag.Bool(
 		"diff-report",
 		false,
-		"Run batch diff report on provided directories and exit (outputs YAML to stdout)",
+		"Run batch diff report on provided directories or snapshot manifests and exit (outputs YAML to stdout)",
+	)
+	// [IMPL:DIFF_SNAPSHOT] [ARCH:DIFF_SNAPSHOT] [REQ:DIFF_SNAPSHOT]
+	snapshotFlag = flag.String(
+		"snapshot",
+		"",
+		"Write a YAML manifest of `DIR` (paths, sizes, mtimes, modes, digests) to stdout and exit",
+	)
+	// [IMPL:DIFF_PARALLEL] [ARCH:DIFF_PARALLEL] [REQ:DIFF_PARALLEL]
+	jobsFlag = flag.Int(
+		"jobs",
+		1,
+		"Number of directories read and subtrees walked concurrently (only with --diff-report)",
 	)
 	quietFlag = flag.Bool(
 		"quiet",
@@ -101,6 +136,12 @@ func main() {
 		os.Exit(0)
 	}
 
+	// [IMPL:DIFF_SNAPSHOT] [ARCH:DIFF_SNAPSHOT] [REQ:DIFF_SNAPSHOT]
+	if *snapshotFlag != "" {
+		runSnapshot(*snapshotFlag)
+		return
+	}
+
 	// [IMPL:BATCH_DIFF_REPORT] [ARCH:BATCH_DIFF_REPORT] [REQ:BATCH_DIFF_REPORT]
 	// Handle batch diff report mode before any TUI initialization
 	if *diffReportFlag {
@@ -109,11 +150,34 @@ func main() {
 	}
 
 	pathsResolver := configpaths.Resolver{}
-	runtimePaths := pathsResolver.Resolve(*stateFlag, *historyFlag, *commandsFlag, *excludeNamesFlag, *compareColorsFlag)
+	// [IMPL:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [REQ:UNIFIED_CONFIG]
+	// Paths from the unified config file rank below flags and environment variables.
+	configPath, configSource := pathsResolver.ConfigFile(*configFlag)
+	settings, settingsErr := configfile.Load(configPath, runtime.GOOS)
+	pathsResolver.Configured = settings.Paths
+	runtimePaths := pathsResolver.Resolve(configpaths.Flags{
+		State:         *stateFlag,
+		History:       *historyFlag,
+		Commands:      *commandsFlag,
+		Excludes:      *excludeNamesFlag,
+		CompareColors: *compareColorsFlag,
+		Keymaps:       *keymapsFlag,
+		Associations:  *associationsFlag,
+	})
+	runtimePaths.Config, runtimePaths.ConfigSource = configPath, configSource
 	emitPathDebug(runtimePaths)
-	loadExcludedNames(runtimePaths.Excludes)
+	sources := configSources{paths: runtimePaths, settings: settings}
+	if _, inline := sources.excludesFile(); inline {
+		filer.ConfigureExcludedNames(settings.Excludes, len(settings.Excludes) > 0)
+	} else {
+		loadExcludedNames(runtimePaths.Excludes)
+	}
 	// [IMPL:COMPARE_COLOR_CONFIG] [ARCH:FILE_COMPARISON_ENGINE] [REQ:FILE_COMPARISON_COLORS]
-	loadCompareColors(runtimePaths.CompareColors)
+	if _, inline := sources.compareColorsFile(); inline {
+		look.ConfigureComparisonColors(settings.Colors.Parse())
+	} else {
+		loadCompareColors(runtimePaths.CompareColors)
+	}
 
 	is_tmux := false
 	widget.Init()
@@ -125,14 +189,23 @@ func main() {
 		is_tmux = strings.Contains(os.Getenv("TERM"), "screen")
 	}
 	// Change a terminal title.
-	if is_tmux {
-		os.Stdout.WriteString("\033kgoful\033") // for tmux
-	} else {
-		os.Stdout.WriteString("\033]0;goful\007") // for otherwise
+	// [IMPL:UNIFIED_CONFIG] terminal.title in the config file turns this off.
+	if settings.Terminal.SetsTitle() {
+		if is_tmux {
+			os.Stdout.WriteString("\033kgoful\033") // for tmux
+		} else {
+			os.Stdout.WriteString("\033]0;goful\007") // for otherwise
+		}
 	}
 
 	goful := app.NewGoful(runtimePaths.State)
-	config(goful, is_tmux, runtimePaths)
+	config(goful, is_tmux, runtimePaths, settings)
+	if settingsErr != nil {
+		message.Errorf("[REQ:UNIFIED_CONFIG] %v", settingsErr)
+	}
+	// [IMPL:WORKSPACE_SETTINGS] [ARCH:WORKSPACE_SETTINGS] [REQ:WORKSPACE_SETTINGS]
+	// Saved per-workspace settings override the defaults set by config.
+	goful.RestoreSettings()
 	// [IMPL:HISTORY_ERROR_HANDLING] [ARCH:DEBT_MANAGEMENT] [REQ:DEBT_TRIAGE]
 	// LoadHistory returns nil for first-run (missing file) but returns structured errors
 	// for actual IO failures that users need to know about.
@@ -159,12 +232,17 @@ func main() {
 	}
 }
 
-func config(g *app.Goful, is_tmux bool, paths configpaths.Paths) {
-	look.Set("default") // default, midnight, black, white
+func config(g *app.Goful, is_tmux bool, paths configpaths.Paths, settings *configfile.File) {
+	sources := configSources{paths: paths, settings: settings}
+	// [IMPL:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [REQ:UNIFIED_CONFIG]
+	// Theme, border and message duration come from the config file.
+	applyAppearance(settings)
 
 	// [IMPL:LINKED_NAVIGATION] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_NAVIGATION]
 	// Wire linked navigation indicator to filer header
 	filer.SetLinkedNavIndicator(g.IsLinkedNav)
+	// [IMPL:WORKSPACE_SETTINGS] [ARCH:WORKSPACE_SETTINGS] [REQ:WORKSPACE_SETTINGS]
+	filer.SetLinkedNavSetter(g.SetLinkedNav)
 
 	// [IMPL:TOOLBAR_PARENT_BUTTON] [ARCH:TOOLBAR_LAYOUT] [REQ:TOOLBAR_PARENT_BUTTON]
 	// Wire toolbar parent button action to navigate to parent directory
@@ -283,28 +361,23 @@ func config(g *app.Goful, is_tmux bool, paths configpaths.Paths) {
 	diffstatus.SetStatusFn(g.DiffSearchStatus)
 	diffstatus.SetActiveFn(g.IsDiffSearchActive)
 
-	if runewidth.EastAsianWidth {
-		// Because layout collapsing for ambiguous runes if LANG=ja_JP.
-		widget.SetBorder('|', '-', '+', '+', '+', '+')
-	} else {
-		// Look good if environment variable RUNEWIDTH_EASTASIAN=0 and
-		// ambiguous char setting is half-width for gnome-terminal.
-		widget.SetBorder('│', '─', '┌', '┐', '└', '┘') // 0x2502, 0x2500, 0x250c, 0x2510, 0x2514, 0x2518
-	}
 	g.SetBorderStyle(widget.AllBorder) // AllBorder, ULBorder, NoBorder
 
-	message.SetInfoLog("~/.goful/log/info.log")   // "" is not logging
-	message.SetErrorLog("~/.goful/log/error.log") // "" is not logging
-	message.Sec(5)                                // display second for a message
+	// [IMPL:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [REQ:UNIFIED_CONFIG]
+	message.SetInfoLog(filepath.Join(paths.Log, "info.log"))   // "" is not logging
+	message.SetErrorLog(filepath.Join(paths.Log, "error.log")) // "" is not logging
 
 	// Setup widget keymaps.
-	g.ConfigFiler(filerKeymap)
-	filer.ConfigFinder(finderKeymap)
-	cmdline.Config(cmdlineKeymap)
-	cmdline.ConfigCompletion(completionKeymap)
-	menu.Config(menuKeymap)
+	// [IMPL:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [REQ:USER_KEYMAPS]
+	// Bindings from the keymaps file override the defaults of each context.
+	keymaps, keymapsFile, keymapErr := sources.keymaps()
+	if keymapErr != nil {
+		message.Errorf("[REQ:USER_KEYMAPS] %v", keymapErr)
+	}
+	registerActions(g)
+	g.MergeKeymap(action.Keymap(filerBindings))
 
-	filer.SetStatView(true, false, true)  // size, permission and time
+	filer.SetStatView(true, false, true)  // default size, permission and time
 	filer.SetTimeFormat("06-01-02 15:04") // ex: "Jan _2 15:04"
 
 	toggleExcludedNames := func() {
@@ -322,6 +395,12 @@ func config(g *app.Goful, is_tmux bool, paths configpaths.Paths) {
 		g.Workspace().ReloadAll()
 	}
 
+	// [IMPL:PANE_VIEW_SETTINGS] [ARCH:PANE_VIEW_SETTINGS] [REQ:PANE_VIEW_SETTINGS]
+	// View settings belong to the focused pane and reach its linked panes in linked mode.
+	changeView := func(fn func(v *filer.DirectoryView)) {
+		g.Workspace().ChangeView(g.IsLinkedNav(), fn)
+	}
+
 	// [IMPL:COMPARISON_DRAW] [ARCH:FILE_COMPARISON_ENGINE] [REQ:FILE_COMPARISON_COLORS]
 	toggleComparisonColors := func() {
 		enabled := look.ToggleComparisonEnabled()
@@ -352,7 +431,15 @@ func config(g *app.Goful, is_tmux bool, paths configpaths.Paths) {
 		}
 	}
 
-	// Setup open command for C-m (when the enter key is pressed)
+	// [IMPL:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [REQ:ACTION_REGISTRY]
+	action.Add(
+		"view.toggle-excludes", "Toggle filename excludes", toggleExcludedNames,
+		"view.compare-colors", "Toggle comparison colors", toggleComparisonColors, // [REQ:FILE_COMPARISON_COLORS]
+		"file.digest", "Calculate file digest", calculateDigest, // [REQ:FILE_COMPARISON_COLORS] [IMPL:DIGEST_COMPARISON]
+	)
+
+	// Setup open command for C-m (when the enter key is pressed), used by
+	// file.open below for files without an association
 	// The macro %f means expanded to a file name, for more see (spawn.go)
 	opener := "xdg-open %f %&"
 	switch runtime.GOOS {
@@ -361,10 +448,6 @@ func config(g *app.Goful, is_tmux bool, paths configpaths.Paths) {
 	case "darwin":
 		opener = "open %f %&"
 	}
-	g.MergeKeymap(widget.Keymap{
-		"C-m": func() { g.Spawn(opener) },
-		"o":   func() { g.Spawn(opener) },
-	})
 
 	// Setup pager by $PAGER
 	pager := os.Getenv("PAGER")
@@ -380,7 +463,9 @@ func config(g *app.Goful, is_tmux bool, paths configpaths.Paths) {
 	} else {
 		pager += " %f"
 	}
-	g.AddKeymap("i", func() { g.Spawn(pager) })
+	// [IMPL:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [REQ:ACTION_REGISTRY]
+	action.Add("app.pager", "View file with $PAGER", func() { g.Spawn(pager) })
+	g.AddKeymap("i", "app.pager")
 
 	// Setup a shell and a terminal to execute external commands.
 	// The shell is called when execute on background by the macro %&.
@@ -420,64 +505,78 @@ func config(g *app.Goful, is_tmux bool, paths configpaths.Paths) {
 	// Setup menus and add to keymap.
 	// [IMPL:LINKED_NAVIGATION] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_NAVIGATION]
 	// Sort menu with linked mode support - when linked, sort applies to all windows
-	menu.Add("sort",
-		"n", "sort name          ", func() {
-			if g.IsLinkedNav() {
-				g.Workspace().SortAllBy(filer.SortName)
-			} else {
-				g.Dir().SortName()
-			}
-		},
-		"N", "sort name decending", func() {
-			if g.IsLinkedNav() {
-				g.Workspace().SortAllBy(filer.SortNameRev)
-			} else {
-				g.Dir().SortNameDec()
-			}
-		},
-		"s", "sort size          ", func() {
-			if g.IsLinkedNav() {
-				g.Workspace().SortAllBy(filer.SortSize)
-			} else {
-				g.Dir().SortSize()
-			}
-		},
-		"S", "sort size decending", func() {
-			if g.IsLinkedNav() {
-				g.Workspace().SortAllBy(filer.SortSizeRev)
-			} else {
-				g.Dir().SortSizeDec()
-			}
-		},
-		"t", "sort time          ", func() {
-			if g.IsLinkedNav() {
-				g.Workspace().SortAllBy(filer.SortMtime)
-			} else {
-				g.Dir().SortMtime()
-			}
-		},
-		"T", "sort time decending", func() {
-			if g.IsLinkedNav() {
-				g.Workspace().SortAllBy(filer.SortMtimeRev)
-			} else {
-				g.Dir().SortMtimeDec()
-			}
-		},
-		"e", "sort ext           ", func() {
-			if g.IsLinkedNav() {
-				g.Workspace().SortAllBy(filer.SortExt)
-			} else {
-				g.Dir().SortExt()
-			}
+	sortBy := func(typ filer.SortType) {
+		if g.IsLinkedNav() {
+			g.Workspace().SortAllBy(typ)
+		} else {
+			g.Dir().SortBy(typ)
+		}
+	}
+	// [IMPL:CONFIG_MENUS] [ARCH:CONFIG_MENUS] [REQ:CONFIG_MENUS]
+	// Named so that menus from the commands file can sort, toggle views and lay out panes.
+	action.Add(
+		"sort.name", "Sort by name", func() { sortBy(filer.SortName) },
+		"sort.name-desc", "Sort by name descending", func() { sortBy(filer.SortNameRev) },
+		"sort.size", "Sort by size", func() { sortBy(filer.SortSize) },
+		"sort.size-desc", "Sort by size descending", func() { sortBy(filer.SortSizeRev) },
+		"sort.time", "Sort by time", func() { sortBy(filer.SortMtime) },
+		"sort.time-desc", "Sort by time descending", func() { sortBy(filer.SortMtimeRev) },
+		"sort.ext", "Sort by extension", func() { sortBy(filer.SortExt) },
+		"sort.ext-desc", "Sort by extension descending", func() { sortBy(filer.SortExtRev) },
+		// [IMPL:SORT_ORDERS] [ARCH:SORT_ORDERS] [REQ:SORT_ORDERS]
+		"sort.natural", "Sort naturally", func() { sortBy(filer.SortNatural) },
+		"sort.natural-desc", "Sort naturally descending", func() { sortBy(filer.SortNaturalRev) },
+		"sort.version", "Sort by version", func() { sortBy(filer.SortVersion) },
+		"sort.version-desc", "Sort by version descending", func() { sortBy(filer.SortVersionRev) },
+		"sort.nocase", "Sort by name ignoring case", func() { sortBy(filer.SortNameFold) },
+		"sort.nocase-desc", "Sort by name ignoring case descending", func() { sortBy(filer.SortNameFoldRev) },
+		"sort.perm", "Sort by permission", func() { sortBy(filer.SortPerm) },
+		"sort.perm-desc", "Sort by permission descending", func() { sortBy(filer.SortPermRev) },
+		"sort.owner", "Sort by owner", func() { sortBy(filer.SortOwner) },
+		"sort.owner-desc", "Sort by owner descending", func() { sortBy(filer.SortOwnerRev) },
+		"sort.dirs-first", "Toggle directories first", func() { changeView(func(v *filer.DirectoryView) { v.PriorityDir = !v.PriorityDir }) },
+
+		"view.hidden", "Toggle hidden files", func() { changeView(func(v *filer.DirectoryView) { v.ShowHiddens = !v.ShowHiddens }) },
+		"view.stat-size", "Toggle size column", func() { changeView(func(v *filer.DirectoryView) { v.StatSize = !v.StatSize }) },
+		"view.stat-perm", "Toggle permission column", func() { changeView(func(v *filer.DirectoryView) { v.StatPermission = !v.StatPermission }) },
+		"view.stat-time", "Toggle time column", func() { changeView(func(v *filer.DirectoryView) { v.StatTime = !v.StatTime }) },
+		"view.stat-all", "Show all stat columns", func() {
+			changeView(func(v *filer.DirectoryView) { v.StatSize, v.StatPermission, v.StatTime = true, true, true })
 		},
-		"E", "sort ext decending ", func() {
-			if g.IsLinkedNav() {
-				g.Workspace().SortAllBy(filer.SortExtRev)
-			} else {
-				g.Dir().SortExtDec()
-			}
+		"view.stat-none", "Hide all stat columns", func() {
+			changeView(func(v *filer.DirectoryView) { v.StatSize, v.StatPermission, v.StatTime = false, false, false })
 		},
-		".", "toggle priority    ", func() { filer.TogglePriority(); g.Workspace().ReloadAll() },
+		"view.to-all-panes", "Apply pane view to all panes", func() { g.Workspace().ApplyViewToAll() }, // [REQ:PANE_VIEW_SETTINGS] [IMPL:PANE_VIEW_SETTINGS]
+
+		"layout.tile", "Tile layout", func() { g.Workspace().LayoutTile() },
+		"layout.tile-top", "Tile-top layout", func() { g.Workspace().LayoutTileTop() },
+		"layout.tile-bottom", "Tile-bottom layout", func() { g.Workspace().LayoutTileBottom() },
+		"layout.one-row", "One-row layout", func() { g.Workspace().LayoutOnerow() },
+		"layout.one-column", "One-column layout", func() { g.Workspace().LayoutOnecolumn() },
+		"layout.fullscreen", "Fullscreen layout", func() { g.Workspace().LayoutFullscreen() },
+	)
+
+	menu.Add("sort",
+		"n", "sort name                 ", "sort.name",
+		"N", "sort name decending       ", "sort.name-desc",
+		"s", "sort size                 ", "sort.size",
+		"S", "sort size decending       ", "sort.size-desc",
+		"t", "sort time                 ", "sort.time",
+		"T", "sort time decending       ", "sort.time-desc",
+		"e", "sort ext                  ", "sort.ext",
+		"E", "sort ext decending        ", "sort.ext-desc",
+		// [IMPL:SORT_ORDERS] [ARCH:SORT_ORDERS] [REQ:SORT_ORDERS]
+		"a", "sort natural              ", "sort.natural",
+		"A", "sort natural decending    ", "sort.natural-desc",
+		"v", "sort version              ", "sort.version",
+		"V", "sort version decending    ", "sort.version-desc",
+		"i", "sort name nocase          ", "sort.nocase",
+		"I", "sort name nocase decending", "sort.nocase-desc",
+		"p", "sort permission           ", "sort.perm",
+		"P", "sort permission decending ", "sort.perm-desc",
+		"o", "sort owner                ", "sort.owner",
+		"O", "sort owner decending      ", "sort.owner-desc",
+		".", "toggle dirs first         ", "sort.dirs-first",
 	)
 	g.AddKeymap("s", func() { g.Menu("sort") })
 
@@ -485,142 +584,211 @@ func config(g *app.Goful, is_tmux bool, paths configpaths.Paths) {
 		"s", "stat menu    ", func() { g.Menu("stat") },
 		"l", "layout menu  ", func() { g.Menu("layout") },
 		"L", "look menu    ", func() { g.Menu("look") },
-		"n", "toggle filename excludes", func() { toggleExcludedNames() },
-		".", "toggle show hidden files", func() { filer.ToggleShowHiddens(); g.Workspace().ReloadAll() },
-		"`", "toggle comparison colors", func() { toggleComparisonColors() }, // [REQ:FILE_COMPARISON_COLORS]
-		"=", "calculate file digest   ", func() { calculateDigest() }, // [REQ:FILE_COMPARISON_COLORS] [IMPL:DIGEST_COMPARISON]
-		"[", "start diff search       ", func() { g.StartDiffSearch() }, // [REQ:DIFF_SEARCH] [IMPL:DIFF_SEARCH]
-		"]", "continue diff search    ", func() { g.ContinueDiffSearch() }, // [REQ:DIFF_SEARCH] [IMPL:DIFF_SEARCH]
+		"n", "toggle filename excludes", "view.toggle-excludes",
+		".", "toggle show hidden files", "view.hidden",
+		"`", "toggle comparison colors", "view.compare-colors", // [REQ:FILE_COMPARISON_COLORS]
+		"=", "calculate file digest   ", "file.digest", // [REQ:FILE_COMPARISON_COLORS] [IMPL:DIGEST_COMPARISON]
+		"[", "start diff search       ", "diff.start", // [REQ:DIFF_SEARCH] [IMPL:DIFF_SEARCH]
+		"]", "continue diff search    ", "diff.continue", // [REQ:DIFF_SEARCH] [IMPL:DIFF_SEARCH]
+		"{", "previous diff search    ", "diff.previous", // [REQ:DIFF_SEARCH_REVERSE] [IMPL:DIFF_SEARCH_REVERSE]
+		"}", "list all differences    ", "diff.results", // [REQ:DIFF_RESULTS_PANEL] [IMPL:DIFF_RESULTS_PANEL]
+		"|", "reconcile panes         ", "diff.reconcile", // [REQ:DIFF_RECONCILE] [IMPL:DIFF_RECONCILE]
+		"m", "linked path map         ", "pane.path-map", // [REQ:LINKED_PATH_MAP] [IMPL:LINKED_PATH_MAP]
+		"g", "link group of pane      ", "pane.link-group", // [REQ:LINK_GROUPS] [IMPL:LINK_GROUPS]
 	)
 	g.AddKeymap("v", func() { g.Menu("view") })
-	g.AddKeymap("E", toggleExcludedNames)
-	g.AddKeymap("`", toggleComparisonColors) // [REQ:FILE_COMPARISON_COLORS]
-	g.AddKeymap("=", calculateDigest)        // [REQ:FILE_COMPARISON_COLORS] [IMPL:DIGEST_COMPARISON]
+	g.AddKeymap("E", "view.toggle-excludes")
+	g.AddKeymap("`", "view.compare-colors") // [REQ:FILE_COMPARISON_COLORS]
+	g.AddKeymap("=", "file.digest")         // [REQ:FILE_COMPARISON_COLORS] [IMPL:DIGEST_COMPARISON]
 
 	menu.Add("layout",
-		"t", "tile       ", func() { g.Workspace().LayoutTile() },
-		"T", "tile-top   ", func() { g.Workspace().LayoutTileTop() },
-		"b", "tile-bottom", func() { g.Workspace().LayoutTileBottom() },
-		"r", "one-row    ", func() { g.Workspace().LayoutOnerow() },
-		"c", "one-column ", func() { g.Workspace().LayoutOnecolumn() },
-		"f", "fullscreen ", func() { g.Workspace().LayoutFullscreen() },
+		"t", "tile       ", "layout.tile",
+		"T", "tile-top   ", "layout.tile-top",
+		"b", "tile-bottom", "layout.tile-bottom",
+		"r", "one-row    ", "layout.one-row",
+		"c", "one-column ", "layout.one-column",
+		"f", "fullscreen ", "layout.fullscreen",
 	)
 
 	menu.Add("stat",
-		"s", "toggle size  ", func() { filer.ToggleSizeView() },
-		"p", "toggle perm  ", func() { filer.TogglePermView() },
-		"t", "toggle time  ", func() { filer.ToggleTimeView() },
-		"1", "all stat     ", func() { filer.SetStatView(true, true, true) },
-		"0", "no stat      ", func() { filer.SetStatView(false, false, false) },
+		"s", "toggle size  ", "view.stat-size",
+		"p", "toggle perm  ", "view.stat-perm",
+		"t", "toggle time  ", "view.stat-time",
+		"1", "all stat     ", "view.stat-all",
+		"0", "no stat      ", "view.stat-none",
+		"a", "view to all  ", "view.to-all-panes", // [REQ:PANE_VIEW_SETTINGS] [IMPL:PANE_VIEW_SETTINGS]
 	)
 
+	// [IMPL:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [REQ:ACTION_REGISTRY]
+	action.Add(
+		"look.theme-default", "Default theme", func() { look.Set("default") },
+		"look.theme-midnight", "Midnight theme", func() { look.Set("midnight") },
+		"look.theme-black", "Black theme", func() { look.Set("black") },
+		"look.theme-white", "White theme", func() { look.Set("white") },
+		"look.border-all", "Border on every side", func() { g.SetBorderStyle(widget.AllBorder) },
+		"look.border-ul", "Border on the top and left", func() { g.SetBorderStyle(widget.ULBorder) },
+		"look.border-none", "No border", func() { g.SetBorderStyle(widget.NoBorder) },
+	)
 	menu.Add("look",
-		"d", "default      ", func() { look.Set("default") },
-		"n", "midnight     ", func() { look.Set("midnight") },
-		"b", "black        ", func() { look.Set("black") },
-		"w", "white        ", func() { look.Set("white") },
-		"a", "all border   ", func() { g.SetBorderStyle(widget.AllBorder) },
-		"u", "ul border    ", func() { g.SetBorderStyle(widget.ULBorder) },
-		"0", "no border    ", func() { g.SetBorderStyle(widget.NoBorder) },
+		"d", "default      ", "look.theme-default",
+		"n", "midnight     ", "look.theme-midnight",
+		"b", "black        ", "look.theme-black",
+		"w", "white        ", "look.theme-white",
+		"a", "all border   ", "look.border-all",
+		"u", "ul border    ", "look.border-ul",
+		"0", "no border    ", "look.border-none",
 	)
 
 	menu.Add("command",
-		"c", "copy              ", func() { g.Copy() },
-		"C", "copy all (multi)  ", func() { g.CopyAll() }, // [REQ:NSYNC_MULTI_TARGET] [IMPL:NSYNC_COPY_MOVE]
-		"m", "move              ", func() { g.Move() },
-		"M", "move all (multi)  ", func() { g.MoveAll() }, // [REQ:NSYNC_MULTI_TARGET] [IMPL:NSYNC_COPY_MOVE]
-		"D", "delete            ", func() { g.Remove() },
-		"k", "mkdir             ", func() { g.Mkdir() },
-		"n", "newfile           ", func() { g.Touch() },
-		"H", "chmod             ", func() { g.Chmod() },
-		"r", "rename            ", func() { g.Rename() },
-		"R", "bulk rename       ", func() { g.BulkRename() },
-		"d", "chdir             ", func() { g.Chdir() },
-		"g", "glob              ", func() { g.Glob() },
-		"G", "globdir           ", func() { g.Globdir() },
+		"c", "copy              ", "file.copy",
+		"C", "copy all (multi)  ", "file.copy-all", // [REQ:NSYNC_MULTI_TARGET] [IMPL:NSYNC_COPY_MOVE]
+		"m", "move              ", "file.move",
+		"M", "move all (multi)  ", "file.move-all", // [REQ:NSYNC_MULTI_TARGET] [IMPL:NSYNC_COPY_MOVE]
+		"D", "delete            ", "file.remove",
+		"k", "mkdir             ", "file.mkdir",
+		"n", "newfile           ", "file.touch",
+		"H", "chmod             ", "file.chmod",
+		"r", "rename            ", "file.rename",
+		"R", "bulk rename       ", "file.bulk-rename",
+		"d", "chdir             ", "filer.chdir",
+		"g", "glob              ", "filer.glob",
+		"G", "globdir           ", "filer.globdir",
 	)
 	g.AddKeymap("x", func() { g.Menu("command") })
 
-	// [IMPL:EXTERNAL_COMMAND_LOADER] [IMPL:EXTERNAL_COMMAND_BINDER] [ARCH:EXTERNAL_COMMAND_REGISTRY] [REQ:EXTERNAL_COMMAND_CONFIG]
-	commandEntries, loadErr := externalcmd.Load(externalcmd.Options{
-		Path:  paths.Commands,
-		GOOS:  runtime.GOOS,
-		Debug: os.Getenv(externalcmd.EnvDebugCommands) != "",
-		Logf: func(format string, args ...interface{}) {
-			message.Infof("DEBUG: [IMPL:EXTERNAL_COMMAND_LOADER] "+format, args...)
-		},
-	})
-	if loadErr != nil {
-		message.Errorf("[REQ:EXTERNAL_COMMAND_CONFIG] failed to load %s: %v", paths.Commands, loadErr)
-	}
-	externalmenu.Register(g, commandEntries)
-	g.AddKeymap("X", func() { g.Menu(externalcmd.MenuName) })
-
+	// [IMPL:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [REQ:ACTION_REGISTRY]
+	action.Add(
+		"archive.zip", "Archive marked files as zip", func() { g.Shell(`zip -roD %x.zip %m`, -7) },
+		"archive.tar", "Archive marked files as tar", func() { g.Shell(`tar cvf %x.tar %m`, -7) },
+		"archive.tgz", "Archive marked files as tar.gz", func() { g.Shell(`tar cvfz %x.tgz %m`, -7) },
+		"archive.bz2", "Archive marked files as tar.bz2", func() { g.Shell(`tar cvfj %x.bz2 %m`, -7) },
+		"archive.txz", "Archive marked files as tar.xz", func() { g.Shell(`tar cvfJ %x.txz %m`, -7) },
+		"archive.rar", "Archive marked files as rar", func() { g.Shell(`rar u %x.rar %m`, -7) },
+
+		"archive.extract-zip", "Extract marked zip files", func() { g.Shell(`for i in %m; do unzip "$i" -d ./; done`, -6) },
+		"archive.extract-tar", "Extract marked tar files", func() { g.Shell(`for i in %m; do tar xvf "$i" -C ./; done`, -6) },
+		"archive.extract-tgz", "Extract marked tgz files", func() { g.Shell(`for i in %m; do tar xvfz "$i" -C ./; done`, -6) },
+		"archive.extract-bz2", "Extract marked bz2 files", func() { g.Shell(`for i in %m; do tar xvfj "$i" -C ./; done`, -6) },
+		"archive.extract-txz", "Extract marked txz files", func() { g.Shell(`for i in %m; do tar xvfJ "$i" -C ./; done`, -6) },
+		"archive.extract-rar", "Extract marked rar files", func() { g.Shell(`for i in %m; do unrar x "$i" -C ./; done`, -6) },
+
+		"archive.find-extract-zip", "Find and extract zip files", func() { g.Shell(`find . -name "*.zip" -type f -prune -print0 | xargs -n1 -0 unzip -d ./`) },
+		"archive.find-extract-tar", "Find and extract tar files", func() { g.Shell(`find . -name "*.tar" -type f -prune -print0 | xargs -n1 -0 tar xvf -C ./`) },
+		"archive.find-extract-tgz", "Find and extract tgz files", func() { g.Shell(`find . -name "*.tgz" -type f -prune -print0 | xargs -n1 -0 tar xvfz -C ./`) },
+		"archive.find-extract-bz2", "Find and extract bz2 files", func() { g.Shell(`find . -name "*.bz2" -type f -prune -print0 | xargs -n1 -0 tar xvfj -C ./`) },
+		"archive.find-extract-txz", "Find and extract txz files", func() { g.Shell(`find . -name "*.txz" -type f -prune -print0 | xargs -n1 -0 tar xvfJ -C ./`) },
+		"archive.find-extract-rar", "Find and extract rar files", func() { g.Shell(`find . -name "*.rar" -type f -prune -print0 | xargs -n1 -0 unrar x -C ./`) },
+	)
 	menu.Add("archive",
-		"z", "zip     ", func() { g.Shell(`zip -roD %x.zip %m`, -7) },
-		"t", "tar     ", func() { g.Shell(`tar cvf %x.tar %m`, -7) },
-		"g", "tar.gz  ", func() { g.Shell(`tar cvfz %x.tgz %m`, -7) },
-		"b", "tar.bz2 ", func() { g.Shell(`tar cvfj %x.bz2 %m`, -7) },
-		"x", "tar.xz  ", func() { g.Shell(`tar cvfJ %x.txz %m`, -7) },
-		"r", "rar     ", func() { g.Shell(`rar u %x.rar %m`, -7) },
-
-		"Z", "extract zip for %m", func() { g.Shell(`for i in %m; do unzip "$i" -d ./; done`, -6) },
-		"T", "extract tar for %m", func() { g.Shell(`for i in %m; do tar xvf "$i" -C ./; done`, -6) },
-		"G", "extract tgz for %m", func() { g.Shell(`for i in %m; do tar xvfz "$i" -C ./; done`, -6) },
-		"B", "extract bz2 for %m", func() { g.Shell(`for i in %m; do tar xvfj "$i" -C ./; done`, -6) },
-		"X", "extract txz for %m", func() { g.Shell(`for i in %m; do tar xvfJ "$i" -C ./; done`, -6) },
-		"R", "extract rar for %m", func() { g.Shell(`for i in %m; do unrar x "$i" -C ./; done`, -6) },
-
-		"1", "find . *.zip extract", func() { g.Shell(`find . -name "*.zip" -type f -prune -print0 | xargs -n1 -0 unzip -d ./`) },
-		"2", "find . *.tar extract", func() { g.Shell(`find . -name "*.tar" -type f -prune -print0 | xargs -n1 -0 tar xvf -C ./`) },
-		"3", "find . *.tgz extract", func() { g.Shell(`find . -name "*.tgz" -type f -prune -print0 | xargs -n1 -0 tar xvfz -C ./`) },
-		"4", "find . *.bz2 extract", func() { g.Shell(`find . -name "*.bz2" -type f -prune -print0 | xargs -n1 -0 tar xvfj -C ./`) },
-		"5", "find . *.txz extract", func() { g.Shell(`find . -name "*.txz" -type f -prune -print0 | xargs -n1 -0 tar xvfJ -C ./`) },
-		"6", "find . *.rar extract", func() { g.Shell(`find . -name "*.rar" -type f -prune -print0 | xargs -n1 -0 unrar x -C ./`) },
+		"z", "zip     ", "archive.zip",
+		"t", "tar     ", "archive.tar",
+		"g", "tar.gz  ", "archive.tgz",
+		"b", "tar.bz2 ", "archive.bz2",
+		"x", "tar.xz  ", "archive.txz",
+		"r", "rar     ", "archive.rar",
+
+		"Z", "extract zip for %m", "archive.extract-zip",
+		"T", "extract tar for %m", "archive.extract-tar",
+		"G", "extract tgz for %m", "archive.extract-tgz",
+		"B", "extract bz2 for %m", "archive.extract-bz2",
+		"X", "extract txz for %m", "archive.extract-txz",
+		"R", "extract rar for %m", "archive.extract-rar",
+
+		"1", "find . *.zip extract", "archive.find-extract-zip",
+		"2", "find . *.tar extract", "archive.find-extract-tar",
+		"3", "find . *.tgz extract", "archive.find-extract-tgz",
+		"4", "find . *.bz2 extract", "archive.find-extract-bz2",
+		"5", "find . *.txz extract", "archive.find-extract-txz",
+		"6", "find . *.rar extract", "archive.find-extract-rar",
 	)
 
+	// [IMPL:LINKED_CHDIR] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_CHDIR] bookmarks move linked panes by the same relative path
+	// [IMPL:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [REQ:ACTION_REGISTRY]
+	action.Add(
+		"bookmark.desktop", "Go to ~/Desktop", func() { g.ChdirLinked("~/Desktop") },
+		"bookmark.documents", "Go to ~/Documents", func() { g.ChdirLinked("~/Documents") },
+		"bookmark.downloads", "Go to ~/Downloads", func() { g.ChdirLinked("~/Downloads") },
+		"bookmark.music", "Go to ~/Music", func() { g.ChdirLinked("~/Music") },
+		"bookmark.pictures", "Go to ~/Pictures", func() { g.ChdirLinked("~/Pictures") },
+		"bookmark.videos", "Go to ~/Videos", func() { g.ChdirLinked("~/Videos") },
+	)
 	menu.Add("bookmark",
-		"t", "~/Desktop  ", func() { g.Dir().Chdir("~/Desktop") },
-		"c", "~/Documents", func() { g.Dir().Chdir("~/Documents") },
-		"d", "~/Downloads", func() { g.Dir().Chdir("~/Downloads") },
-		"m", "~/Music    ", func() { g.Dir().Chdir("~/Music") },
-		"p", "~/Pictures ", func() { g.Dir().Chdir("~/Pictures") },
-		"v", "~/Videos   ", func() { g.Dir().Chdir("~/Videos") },
+		"t", "~/Desktop  ", "bookmark.desktop",
+		"c", "~/Documents", "bookmark.documents",
+		"d", "~/Downloads", "bookmark.downloads",
+		"m", "~/Music    ", "bookmark.music",
+		"p", "~/Pictures ", "bookmark.pictures",
+		"v", "~/Videos   ", "bookmark.videos",
 	)
 	if runtime.GOOS == "windows" {
+		action.Add(
+			"bookmark.drive-c", "Go to C:/", func() { g.ChdirLinked("C:/") },
+			"bookmark.drive-d", "Go to D:/", func() { g.ChdirLinked("D:/") },
+			"bookmark.drive-e", "Go to E:/", func() { g.ChdirLinked("E:/") },
+		)
 		menu.Add("bookmark",
-			"C", "C:/", func() { g.Dir().Chdir("C:/") },
-			"D", "D:/", func() { g.Dir().Chdir("D:/") },
-			"E", "E:/", func() { g.Dir().Chdir("E:/") },
+			"C", "C:/", "bookmark.drive-c",
+			"D", "D:/", "bookmark.drive-d",
+			"E", "E:/", "bookmark.drive-e",
 		)
 	} else {
+		action.Add(
+			"bookmark.etc", "Go to /etc", func() { g.ChdirLinked("/etc") },
+			"bookmark.usr", "Go to /usr", func() { g.ChdirLinked("/usr") },
+			"bookmark.media", "Go to /media", func() { g.ChdirLinked("/media") },
+		)
 		menu.Add("bookmark",
-			"e", "/etc   ", func() { g.Dir().Chdir("/etc") },
-			"u", "/usr   ", func() { g.Dir().Chdir("/usr") },
-			"x", "/media ", func() { g.Dir().Chdir("/media") },
+			"e", "/etc   ", "bookmark.etc",
+			"u", "/usr   ", "bookmark.usr",
+			"x", "/media ", "bookmark.media",
 		)
 	}
 	g.AddKeymap("b", func() { g.Menu("bookmark") })
 
+	// [IMPL:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [REQ:ACTION_REGISTRY]
+	action.Add(
+		"editor.vscode", "Edit with VS Code", func() { g.Spawn("code %f %&") },
+		"editor.emacs", "Edit with emacsclient", func() { g.Spawn("emacsclient -n %f %&") },
+		"editor.vim", "Edit with vim", func() { g.Spawn("vim %f") },
+		"file.open-default", "Open with the system opener", func() { g.Spawn(opener) },
+		"image.eog", "View image with eog", func() { g.Spawn("eog %f %&") },
+		"image.gimp", "Edit images with gimp", func() { g.Spawn("gimp %m %&") },
+		"media.mpv", "Play with mpv", func() { g.Spawn("mpv %f") },
+		"media.vlc", "Play with vlc", func() { g.Spawn("vlc %f %&") },
+	)
 	menu.Add("editor",
-		"c", "vscode        ", func() { g.Spawn("code %f %&") },
-		"e", "emacs client  ", func() { g.Spawn("emacsclient -n %f %&") },
-		"v", "vim           ", func() { g.Spawn("vim %f") },
+		"c", "vscode        ", "editor.vscode",
+		"e", "emacs client  ", "editor.emacs",
+		"v", "vim           ", "editor.vim",
 	)
 	g.AddKeymap("e", func() { g.Menu("editor") })
 
 	menu.Add("image",
-		"x", "default    ", func() { g.Spawn(opener) },
-		"e", "eog        ", func() { g.Spawn("eog %f %&") },
-		"g", "gimp       ", func() { g.Spawn("gimp %m %&") },
+		"x", "default    ", "file.open-default",
+		"e", "eog        ", "image.eog",
+		"g", "gimp       ", "image.gimp",
 	)
 
 	menu.Add("media",
-		"x", "default ", func() { g.Spawn(opener) },
-		"m", "mpv     ", func() { g.Spawn("mpv %f") },
-		"v", "vlc     ", func() { g.Spawn("vlc %f %&") },
+		"x", "default ", "file.open-default",
+		"m", "mpv     ", "media.mpv",
+		"v", "vlc     ", "media.vlc",
 	)
 
+	// [IMPL:EXTERNAL_COMMAND_LOADER] [IMPL:EXTERNAL_COMMAND_BINDER] [ARCH:EXTERNAL_COMMAND_REGISTRY] [REQ:EXTERNAL_COMMAND_CONFIG]
+	// [IMPL:CONFIG_MENUS] [ARCH:CONFIG_MENUS] [REQ:CONFIG_MENUS]
+	// Loaded after the built-in menus so that the file can extend or replace any of them.
+	// [IMPL:CONFIG_RELOAD] The built-in menus are saved so that reloading replaces the file's entries.
+	builtinMenus := menu.Save()
+	commandConfig, loadErr := sources.commands()
+	if loadErr != nil {
+		message.Errorf("[REQ:EXTERNAL_COMMAND_CONFIG] %v", loadErr)
+	}
+	for _, err := range externalmenu.Register(g, commandConfig) {
+		message.Errorf("[REQ:ACTION_REGISTRY] %v", err)
+	}
+	g.AddKeymap("X", func() { g.Menu(externalcmd.MenuName) })
+
 	// [IMPL:LINKED_NAVIGATION] [IMPL:LINKED_NAVIGATION_AUTO_DISABLE] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_NAVIGATION]
 	// Helper for linked directory entry with auto-disable on partial failure
 	linkedEnterDir := func() {
@@ -628,10 +796,9 @@ func config(g *app.Goful, is_tmux bool, paths configpaths.Paths) {
 			name := g.File().Name()
 			// Navigate other directories but DON'T rebuild index yet
 			navigated, skipped := g.Workspace().ChdirAllToSubdirNoRebuild(name)
-			// [IMPL:LINKED_NAVIGATION_AUTO_DISABLE] Auto-disable if any window couldn't navigate
+			// [IMPL:LINKED_NAVIGATION_AUTO_DISABLE] Auto-disable the group if any window couldn't navigate
 			if skipped > 0 {
-				g.SetLinkedNav(false)
-				message.Infof("linked navigation disabled: %d window(s) missing '%s'", skipped, name)
+				g.UnlinkDiverged(skipped, name)
 			}
 			_ = navigated // Used for documentation; skipped > 0 implies divergence
 		}
@@ -687,23 +854,298 @@ func config(g *app.Goful, is_tmux bool, paths configpaths.Paths) {
 		}
 	}
 
-	g.MergeExtmap(widget.Extmap{
-		"C-m": associate,
-		"o":   associate,
+	// [IMPL:FILE_ASSOCIATIONS] [ARCH:FILE_ASSOCIATIONS] [REQ:FILE_ASSOCIATIONS]
+	// Only file.open consults the associations: configured rules run after
+	// ".dir" and ".exec" but before the other extensions above, and files
+	// that match neither fall back to the opener.
+	action.Add("file.open", "Open file", func() {
+		if !g.Open(associate) {
+			g.Spawn(opener)
+		}
 	})
+	g.AddKeymap(
+		"C-m", "file.open",
+		"o", "file.open",
+	)
+	associations, associationsFile, assocErr := sources.associations()
+	if assocErr != nil {
+		message.Errorf("[REQ:FILE_ASSOCIATIONS] %v", assocErr)
+	}
+	for _, err := range applyAssociations(g, associations) {
+		message.Errorf("[REQ:FILE_ASSOCIATIONS] %s: %v", associationsFile, err)
+	}
+
+	// [IMPL:CONFIG_RELOAD] [ARCH:CONFIG_RELOAD] [REQ:CONFIG_RELOAD]
+	// The filer keys are saved here, before the keymaps file is applied.
+	var reloader *configReloader
+	action.Add("app.reload-config", "Reload configuration files", func() {
+		errs := reloader.Reload()
+		for _, err := range errs {
+			message.Errorf("[REQ:CONFIG_RELOAD] %v", err)
+		}
+		if len(errs) == 0 {
+			message.Info("[REQ:CONFIG_RELOAD] reloaded config, commands, excludes, compare colors, associations and keymaps")
+		}
+	})
+	g.AddKeymap("M-r", "app.reload-config")
+	reloader = &configReloader{g: g, paths: paths, settings: settings, menus: builtinMenus, filerKeys: g.Keymap()}
+
+	// [IMPL:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [REQ:USER_KEYMAPS]
+	// Filer bindings go last so they override the menu keys bound above.
+	for _, err := range applyKeymaps(g, keymaps) {
+		message.Errorf("[REQ:USER_KEYMAPS] %s: %v", keymapsFile, err)
+	}
 }
 
-func loadExcludedNames(path string) {
-	if path == "" {
-		filer.ConfigureExcludedNames(nil, false)
-		return
+// loadCommands loads the commands file at path.
+// [IMPL:EXTERNAL_COMMAND_LOADER] [ARCH:EXTERNAL_COMMAND_REGISTRY] [REQ:EXTERNAL_COMMAND_CONFIG]
+func loadCommands(path string) (externalcmd.Config, error) {
+	cfg, err := externalcmd.LoadConfig(externalcmd.Options{
+		Path:  path,
+		GOOS:  runtime.GOOS,
+		Debug: os.Getenv(externalcmd.EnvDebugCommands) != "",
+		Logf: func(format string, args ...interface{}) {
+			message.Infof("DEBUG: [IMPL:EXTERNAL_COMMAND_LOADER] "+format, args...)
+		},
+	})
+	if err != nil {
+		return cfg, fmt.Errorf("failed to load %s: %w", path, err)
 	}
-	names, err := parseExcludeFile(path)
+	return cfg, nil
+}
+
+// applyAssociations opens files with the rules of table and returns the
+// rules naming unknown menus or actions.
+// [IMPL:FILE_ASSOCIATIONS] [ARCH:FILE_ASSOCIATIONS] [REQ:FILE_ASSOCIATIONS]
+func applyAssociations(g *app.Goful, table *assoc.Table) []error {
+	var errs []error
+	for _, rule := range table.Rules() {
+		if rule.Menu != "" && !menu.Exists(rule.Menu) {
+			errs = append(errs, fmt.Errorf("unknown menu %q", rule.Menu))
+		} else if rule.Action != "" && !action.Exists(rule.Action) {
+			errs = append(errs, fmt.Errorf("unknown action %q", rule.Action))
+		}
+	}
+	g.SetAssociation(func(file *filer.FileStat) bool {
+		rule, ok := table.Match(file.Name(), file.Path())
+		if !ok {
+			return false
+		}
+		openAssociation(g, rule)
+		return true
+	})
+	return errs
+}
+
+// applyKeymaps applies the keymaps file to every context and returns the
+// bindings it could not resolve. The filer keymap is changed in place, so
+// reloading resets it first.
+// [IMPL:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [REQ:USER_KEYMAPS]
+func applyKeymaps(g *app.Goful, keymaps keymapcfg.Config) []error {
+	filer.ConfigFinder(userKeymap(keymaps, keymapcfg.Finder, finderKeymap, finderActionKeys))
+	cmdline.Config(userKeymap(keymaps, keymapcfg.Cmdline, cmdlineKeymap, cmdlineActionKeys))
+	cmdline.ConfigCompletion(userKeymap(keymaps, keymapcfg.Completion, completionKeymap, completionActionKeys))
+	menu.Config(userKeymap(keymaps, keymapcfg.Menu, menuKeymap, menuActionKeys))
+
+	// [IMPL:ACTION_REGISTRY] Filer actions are the registered action names.
+	filerActions := action.Funcs()
+	g.MergeKeymap(keymaps.Apply(keymapcfg.Filer, widget.Keymap{}, filerActions, g.Menu))
+	g.RemoveKeymap(keymaps.Unbound(keymapcfg.Filer)...)
+	return keymaps.Validate(contextActions(filerActions), menu.Exists)
+}
+
+// configReloader re-reads the files resolved by configpaths while goful
+// runs. Every file is parsed before its settings are replaced, so a file
+// with errors is reported and its previous settings stay in effect.
+// [IMPL:CONFIG_RELOAD] [ARCH:CONFIG_RELOAD] [REQ:CONFIG_RELOAD]
+type configReloader struct {
+	g         *app.Goful
+	paths     configpaths.Paths
+	settings  *configfile.File
+	menus     menu.Snapshot // menus defined before the commands file
+	filerKeys widget.Keymap // filer keys before the keymaps file
+}
+
+// Reload re-reads the unified config file and then the commands, excludes,
+// compare colors, associations and keymaps, in that order so that
+// associations and keymaps can refer to menus of the new commands, and
+// returns the errors found.
+// [IMPL:CONFIG_RELOAD] [ARCH:CONFIG_RELOAD] [REQ:CONFIG_RELOAD]
+func (r *configReloader) Reload() []error {
+	var errs []error
+
+	// [IMPL:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [REQ:UNIFIED_CONFIG]
+	// Paths stay as resolved at startup; the sections and appearance reload.
+	if settings, err := configfile.Load(r.paths.Config, runtime.GOOS); err != nil {
+		errs = append(errs, err)
+	} else {
+		r.settings = settings
+		applyAppearance(settings)
+	}
+	sources := configSources{paths: r.paths, settings: r.settings}
+
+	if cfg, err := sources.commands(); err != nil {
+		errs = append(errs, err)
+	} else {
+		menu.Restore(r.menus)
+		errs = append(errs, externalmenu.Register(r.g, cfg)...)
+	}
+
+	if names, err := sources.excludedNames(); err != nil {
+		errs = append(errs, err)
+	} else {
+		filer.ReloadExcludedNames(names)
+	}
+
+	if cfg, err := sources.compareColors(); err != nil {
+		errs = append(errs, err)
+	} else {
+		look.ConfigureComparisonColors(cfg.Parse())
+	}
+
+	if table, file, err := sources.associations(); err != nil {
+		errs = append(errs, err)
+	} else {
+		for _, err := range applyAssociations(r.g, table) {
+			errs = append(errs, fmt.Errorf("%s: %w", file, err))
+		}
+	}
+
+	if keymaps, file, err := sources.keymaps(); err != nil {
+		errs = append(errs, err)
+	} else {
+		r.g.SetKeymap(r.filerKeys)
+		for _, err := range applyKeymaps(r.g, keymaps) {
+			errs = append(errs, fmt.Errorf("%s: %w", file, err))
+		}
+	}
+
+	r.g.Workspace().ReloadAll()
+	return errs
+}
+
+// configSources reads each setting from its section in the unified config
+// file, unless a flag or environment variable names a file for it, and
+// otherwise from its own file.
+// [IMPL:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [REQ:UNIFIED_CONFIG]
+type configSources struct {
+	paths    configpaths.Paths
+	settings *configfile.File
+}
+
+// file returns where section is read from and whether it is inline.
+func (s configSources) file(section, path, source string) (string, bool) {
+	if s.settings.Has(section) && source == configpaths.DefaultSource {
+		return fmt.Sprintf("%s (%s)", s.settings.Path, section), true
+	}
+	return path, false
+}
+
+func (s configSources) excludesFile() (string, bool) {
+	return s.file(configfile.Excludes, s.paths.Excludes, s.paths.ExcludesSource)
+}
+
+func (s configSources) compareColorsFile() (string, bool) {
+	return s.file(configfile.Colors, s.paths.CompareColors, s.paths.CompareColorsSource)
+}
+
+func (s configSources) commands() (externalcmd.Config, error) {
+	if _, inline := s.file(configfile.Commands, s.paths.Commands, s.paths.CommandsSource); inline {
+		return s.settings.Commands, nil
+	}
+	return loadCommands(s.paths.Commands)
+}
+
+func (s configSources) excludedNames() ([]string, error) {
+	if _, inline := s.excludesFile(); inline {
+		return s.settings.Excludes, nil
+	}
+	names, err := readExcludedNames(s.paths.Excludes)
 	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			filer.ConfigureExcludedNames(nil, false)
-			return
+		return nil, fmt.Errorf("failed to read exclude list %s: %w", s.paths.Excludes, err)
+	}
+	return names, nil
+}
+
+func (s configSources) compareColors() (*comparecolors.Config, error) {
+	if _, inline := s.compareColorsFile(); inline {
+		return s.settings.Colors, nil
+	}
+	cfg, err := comparecolors.Load(s.paths.CompareColors)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.paths.CompareColors, err)
+	}
+	return cfg, nil
+}
+
+func (s configSources) associations() (*assoc.Table, string, error) {
+	file, inline := s.file(configfile.Associations, s.paths.Associations, s.paths.AssociationsSource)
+	if inline {
+		return s.settings.Associations, file, nil
+	}
+	table, err := assoc.Load(s.paths.Associations, runtime.GOOS)
+	return table, file, err
+}
+
+func (s configSources) keymaps() (keymapcfg.Config, string, error) {
+	file, inline := s.file(configfile.Keymaps, s.paths.Keymaps, s.paths.KeymapsSource)
+	if inline {
+		return s.settings.Keymaps, file, nil
+	}
+	cfg, err := keymapcfg.Load(s.paths.Keymaps)
+	return cfg, file, err
+}
+
+// applyAppearance sets the theme, border characters and message duration
+// from the config file.
+// [IMPL:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [REQ:UNIFIED_CONFIG]
+func applyAppearance(settings *configfile.File) {
+	theme := settings.Theme
+	if theme == "" {
+		theme = "default"
+	}
+	look.Set(theme) // default, midnight, black, white
+
+	border := settings.Terminal.Border
+	if border == "" && runewidth.EastAsianWidth {
+		// Because layout collapsing for ambiguous runes if LANG=ja_JP.
+		border = configfile.BorderASCII
+	}
+	if border == configfile.BorderASCII {
+		widget.SetBorder('|', '-', '+', '+', '+', '+')
+	} else {
+		// Look good if environment variable RUNEWIDTH_EASTASIAN=0 and
+		// ambiguous char setting is half-width for gnome-terminal.
+		widget.SetBorder('│', '─', '┌', '┐', '└', '┘') // 0x2502, 0x2500, 0x250c, 0x2510, 0x2514, 0x2518
+	}
+
+	sec := settings.Terminal.MessageSeconds
+	if sec == 0 {
+		sec = 5
+	}
+	message.Sec(time.Duration(sec)) // display second for a message
+}
+
+// openAssociation runs the command, menu or action of an association rule.
+// [IMPL:FILE_ASSOCIATIONS] [ARCH:FILE_ASSOCIATIONS] [REQ:FILE_ASSOCIATIONS]
+func openAssociation(g *app.Goful, rule assoc.Rule) {
+	switch {
+	case rule.Command != "":
+		g.Shell(rule.Command)
+	case rule.Spawn != "":
+		g.Spawn(rule.Spawn)
+	case rule.Menu != "":
+		g.Menu(rule.Menu)
+	default:
+		if err := action.Run(rule.Action); err != nil {
+			message.Errorf("[REQ:FILE_ASSOCIATIONS] %v", err)
 		}
+	}
+}
+
+func loadExcludedNames(path string) {
+	names, err := readExcludedNames(path)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "WARN: [REQ:FILER_EXCLUDE_NAMES] failed to read exclude list %s: %v\n", path, err)
 		filer.ConfigureExcludedNames(nil, false)
 		return
@@ -718,6 +1160,19 @@ func loadExcludedNames(path string) {
 	}
 }
 
+// readExcludedNames returns the names listed in the exclude file at path.
+// An empty path or a missing file lists none.
+func readExcludedNames(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	names, err := parseExcludeFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return names, err
+}
+
 func parseExcludeFile(path string) ([]string, error) {
 	file, err := os.Open(path)
 	if err != nil {
@@ -749,7 +1204,9 @@ func emitPathDebug(paths configpaths.Paths) {
 	}
 	fmt.Fprintf(
 		os.Stderr,
-		"DEBUG: [IMPL:STATE_PATH_RESOLVER] [ARCH:STATE_PATH_SELECTION] [REQ:CONFIGURABLE_STATE_PATHS] [REQ:EXTERNAL_COMMAND_CONFIG] [REQ:FILER_EXCLUDE_NAMES] [REQ:FILE_COMPARISON_COLORS] state=%s (%s) history=%s (%s) commands=%s (%s) excludes=%s (%s) compare_colors=%s (%s)\n",
+		"DEBUG: [IMPL:STATE_PATH_RESOLVER] [ARCH:STATE_PATH_SELECTION] [REQ:CONFIGURABLE_STATE_PATHS] [REQ:EXTERNAL_COMMAND_CONFIG] [REQ:FILER_EXCLUDE_NAMES] [REQ:FILE_COMPARISON_COLORS] [REQ:USER_KEYMAPS] [REQ:FILE_ASSOCIATIONS] [REQ:UNIFIED_CONFIG] config=%s (%s) state=%s (%s) history=%s (%s) commands=%s (%s) excludes=%s (%s) compare_colors=%s (%s) keymaps=%s (%s) associations=%s (%s) log=%s (%s)\n",
+		paths.Config,
+		paths.ConfigSource,
 		paths.State,
 		paths.StateSource,
 		paths.History,
@@ -760,6 +1217,12 @@ func emitPathDebug(paths configpaths.Paths) {
 		paths.ExcludesSource,
 		paths.CompareColors,
 		paths.CompareColorsSource,
+		paths.Keymaps,
+		paths.KeymapsSource,
+		paths.Associations,
+		paths.AssociationsSource,
+		paths.Log,
+		paths.LogSource,
 	)
 }
 
@@ -781,7 +1244,9 @@ func loadCompareColors(path string) {
 
 // Widget keymap functions.
 
-func filerKeymap(g *app.Goful) widget.Keymap {
+// registerActions adds the named actions bound by filerBindings and the menus.
+// [IMPL:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [REQ:ACTION_REGISTRY]
+func registerActions(g *app.Goful) {
 	// [IMPL:LINKED_NAVIGATION] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_NAVIGATION]
 	// Helper for linked parent navigation
 	linkedParentNav := func() {
@@ -790,102 +1255,166 @@ func filerKeymap(g *app.Goful) widget.Keymap {
 		}
 		g.Dir().Chdir("..")
 	}
+	// [IMPL:LINKED_NAVIGATION] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_NAVIGATION]
+	toggleLinkedNav := func() {
+		enabled := g.ToggleLinkedNav()
+		state := "disabled"
+		if enabled {
+			state = "enabled"
+		}
+		message.Infof("[REQ:LINKED_NAVIGATION] linked navigation %s", state)
+	}
+
+	action.Add(
+		"workspace.create", "Create workspace", func() { g.CreateWorkspace() },
+		"workspace.close", "Close workspace", func() { g.CloseWorkspace() },
+		"workspace.next", "Next workspace", func() { g.MoveWorkspace(1) },
+		"workspace.prev", "Previous workspace", func() { g.MoveWorkspace(-1) },
+		"workspace.rename", "Change workspace title", func() { g.ChangeWorkspaceTitle() },
+		"workspace.reload", "Reload all panes", func() { g.Workspace().ReloadAll() },
+
+		"pane.create", "Create pane", func() { g.Workspace().CreateDir() },
+		"pane.close", "Close pane", func() { g.Workspace().CloseDir() },
+		"pane.focus-next", "Focus next pane", func() { g.Workspace().MoveFocus(1) },
+		"pane.focus-prev", "Focus previous pane", func() { g.Workspace().MoveFocus(-1) },
+		"pane.swap-next", "Swap pane with next", func() { g.Workspace().SwapNextDir() },
+		"pane.swap-prev", "Swap pane with previous", func() { g.Workspace().SwapPrevDir() },
+		"pane.link-group", "Link group of pane", func() { g.SetLinkGroup() }, // [REQ:LINK_GROUPS] [IMPL:LINK_GROUPS]
+		"pane.path-map", "Linked path mapping rules", func() { g.EditPathMap() }, // [REQ:LINKED_PATH_MAP] [IMPL:LINKED_PATH_MAP]
 
-	return widget.Keymap{
-		"M-C-o":     func() { g.CreateWorkspace() },
-		"M-C-w":     func() { g.CloseWorkspace() },
-		"M-f":       func() { g.MoveWorkspace(1) },
-		"M-b":       func() { g.MoveWorkspace(-1) },
-		"C-o":       func() { g.Workspace().CreateDir() },
-		"C-w":       func() { g.Workspace().CloseDir() },
-		"C-l":       func() { g.Workspace().ReloadAll() },
-		"C-f":       func() { g.Workspace().MoveFocus(1) },
-		"C-b":       func() { g.Workspace().MoveFocus(-1) },
-		"right":     func() { g.Workspace().MoveFocus(1) },
-		"left":      func() { g.Workspace().MoveFocus(-1) },
-		"C-i":       func() { g.Workspace().MoveFocus(1) },
-		"l":         func() { g.Workspace().MoveFocus(1) },
-		"h":         func() { g.Workspace().MoveFocus(-1) },
-		"F":         func() { g.Workspace().SwapNextDir() },
-		"B":         func() { g.Workspace().SwapPrevDir() },
-		"w":         func() { g.Workspace().ChdirNeighbor() },
-		"C-h":       linkedParentNav, // [IMPL:LINKED_NAVIGATION]
-		"backspace": linkedParentNav, // [IMPL:LINKED_NAVIGATION]
-		"u":         linkedParentNav, // [IMPL:LINKED_NAVIGATION]
-		// [IMPL:LINKED_NAVIGATION] [ARCH:LINKED_NAVIGATION] [REQ:LINKED_NAVIGATION]
-		// Toggle linked navigation mode with Alt+l or L (uppercase for macOS compatibility)
-		"M-l": func() {
-			enabled := g.ToggleLinkedNav()
-			state := "disabled"
-			if enabled {
-				state = "enabled"
-			}
-			message.Infof("[REQ:LINKED_NAVIGATION] linked navigation %s", state)
-		},
-		// [IMPL:LINKED_NAVIGATION] macOS-friendly alternative (Option key often produces special chars)
-		"L": func() {
-			enabled := g.ToggleLinkedNav()
-			state := "disabled"
-			if enabled {
-				state = "enabled"
-			}
-			message.Infof("[REQ:LINKED_NAVIGATION] linked navigation %s", state)
-		},
-		"~": func() { g.Dir().Chdir("~") },
 		// [IMPL:LINKED_CURSOR_SYNC] Cursor movement syncs to other windows when linked mode is ON
-		"C-n":  func() { g.MoveCursorLinked(1) },
-		"C-p":  func() { g.MoveCursorLinked(-1) },
-		"down": func() { g.MoveCursorLinked(1) },
-		"up":   func() { g.MoveCursorLinked(-1) },
-		"j":    func() { g.MoveCursorLinked(1) },
-		"k":    func() { g.MoveCursorLinked(-1) },
-		"C-d":  func() { g.MoveCursorLinked(5) },
-		"C-u":  func() { g.MoveCursorLinked(-5) },
-		"C-a":  func() { g.MoveTopLinked() },
-		"C-e":  func() { g.MoveBottomLinked() },
-		"home": func() { g.MoveTopLinked() },
-		"end":  func() { g.MoveBottomLinked() },
-		"^":    func() { g.MoveTopLinked() },
-		"$":    func() { g.MoveBottomLinked() },
-		"M-n":  func() { g.Dir().Scroll(1) },
-		"M-p":  func() { g.Dir().Scroll(-1) },
-		"C-v":  func() { g.PageDownLinked() },
-		"M-v":  func() { g.PageUpLinked() },
-		"pgdn": func() { g.PageDownLinked() },
-		"pgup": func() { g.PageUpLinked() },
-		" ":    func() { g.Dir().ToggleMark() },
-		"M-=":  func() { g.Dir().InvertMark() },
-		"C-g":  func() { g.Dir().Reset() },
-		"C-[":  func() { g.Dir().Reset() }, // C-[ means ESC
-		"f":    func() { g.Dir().Finder() },
-		"/":    func() { g.Dir().Finder() },
-		"q":    func() { g.Quit() },
-		"Q":    func() { g.Quit() },
-		"?":    func() { g.Help() }, // [IMPL:HELP_POPUP] [REQ:HELP_POPUP]
-		";":    func() { g.Shell("") },
-		":":    func() { g.ShellSuspend("") },
-		"M-W":  func() { g.ChangeWorkspaceTitle() },
-		"n":    func() { g.Touch() },
-		"K":    func() { g.Mkdir() },
-		"c":    func() { g.Copy() },
-		"C":    func() { g.CopyAll() }, // [REQ:NSYNC_MULTI_TARGET] [IMPL:NSYNC_COPY_MOVE] Copy to all panes
-		"m":    func() { g.Move() },
-		"M":    func() { g.MoveAll() }, // [REQ:NSYNC_MULTI_TARGET] [IMPL:NSYNC_COPY_MOVE] Move to all panes
-		"r":    func() { g.Rename() },
-		"R":    func() { g.BulkRename() },
-		"D":    func() { g.Remove() },
-		"d":    func() { g.Chdir() },
-		"g":    func() { g.Glob() },
-		"G":    func() { g.Globdir() },
+		"filer.cursor-down", "Move cursor down", func() { g.MoveCursorLinked(1) },
+		"filer.cursor-up", "Move cursor up", func() { g.MoveCursorLinked(-1) },
+		"filer.more-down", "More move cursor down", func() { g.MoveCursorLinked(5) },
+		"filer.more-up", "More move cursor up", func() { g.MoveCursorLinked(-5) },
+		"filer.cursor-top", "Move cursor top", func() { g.MoveTopLinked() },
+		"filer.cursor-bottom", "Move cursor bottom", func() { g.MoveBottomLinked() },
+		"filer.scroll-down", "Scroll down", func() { g.Dir().Scroll(1) },
+		"filer.scroll-up", "Scroll up", func() { g.Dir().Scroll(-1) },
+		"filer.page-down", "Page down", func() { g.PageDownLinked() },
+		"filer.page-up", "Page up", func() { g.PageUpLinked() },
+		"filer.mark-toggle", "Toggle mark", func() { g.Dir().ToggleMark() },
+		"filer.mark-invert", "Invert marks", func() { g.Dir().InvertMark() },
+		"filer.reset", "Reset marks and finder", func() { g.Dir().Reset() },
+		"filer.finder", "Finder (filter files)", func() { g.Dir().Finder() },
+		"filer.parent", "Change to parent directory", linkedParentNav, // [IMPL:LINKED_NAVIGATION]
+		"filer.home", "Change to home directory", func() { g.Dir().Chdir("~") },
+		"filer.chdir", "Change directory", func() { g.Chdir() },
+		"filer.chdir-neighbor", "Change to neighbor pane directory", func() { g.Workspace().ChdirNeighbor() },
+		"filer.glob", "Glob pattern", func() { g.Glob() },
+		"filer.globdir", "Glob directory recursively", func() { g.Globdir() },
+		"filer.linked-toggle", "Toggle linked navigation", toggleLinkedNav, // [IMPL:LINKED_NAVIGATION]
+
+		"file.touch", "Create new file", func() { g.Touch() },
+		"file.mkdir", "Make directory", func() { g.Mkdir() },
+		"file.copy", "Copy", func() { g.Copy() },
+		"file.copy-all", "Copy to all panes", func() { g.CopyAll() }, // [REQ:NSYNC_MULTI_TARGET] [IMPL:NSYNC_COPY_MOVE]
+		"file.move", "Move", func() { g.Move() },
+		"file.move-all", "Move to all panes", func() { g.MoveAll() }, // [REQ:NSYNC_MULTI_TARGET] [IMPL:NSYNC_COPY_MOVE]
+		"file.rename", "Rename", func() { g.Rename() },
+		"file.bulk-rename", "Bulk rename by regexp", func() { g.BulkRename() },
+		"file.remove", "Delete", func() { g.Remove() },
+		"file.chmod", "Change mode", func() { g.Chmod() },
+		"file.sync", "Sync command mode across panes", func() { g.SyncMode(false) }, // [IMPL:SYNC_EXECUTE] [REQ:SYNC_COMMANDS]
+
 		// [IMPL:DIFF_SEARCH] [ARCH:DIFF_SEARCH] [REQ:DIFF_SEARCH]
-		// Difference search commands
-		"[": func() { g.StartDiffSearch() },    // Start difference search
-		"]": func() { g.ContinueDiffSearch() }, // Continue to next difference
-		// [IMPL:SYNC_EXECUTE] [REQ:SYNC_COMMANDS]
-		// Sync command mode - synchronized operations across all panes
-		// After pressing S, use c/d/r for operations. Press ! first to enable ignore-failures mode.
-		"S": func() { g.SyncMode(false) },
-	}
+		"diff.start", "Start difference search", func() { g.StartDiffSearch() },
+		"diff.continue", "Continue difference search", func() { g.ContinueDiffSearch() },
+		"diff.previous", "Previous difference", func() { g.PreviousDiffSearch() }, // [IMPL:DIFF_SEARCH_REVERSE] [REQ:DIFF_SEARCH_REVERSE]
+		"diff.results", "List all differences", func() { g.DiffResults() }, // [IMPL:DIFF_RESULTS_PANEL] [REQ:DIFF_RESULTS_PANEL]
+		"diff.reconcile", "Reconcile panes", func() { g.Reconcile() }, // [IMPL:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+
+		"app.quit", "Quit", func() { g.Quit() },
+		"app.help", "Help", func() { g.Help() }, // [IMPL:HELP_POPUP] [REQ:HELP_POPUP]
+		"app.shell", "Shell", func() { g.Shell("") },
+		"app.shell-suspend", "Shell suspend", func() { g.ShellSuspend("") },
+		"app.shell-capture", "Shell with output in a popup", func() { g.ShellCapture("", false) }, // [REQ:COMMAND_OUTPUT_CAPTURE] [IMPL:COMMAND_OUTPUT_CAPTURE]
+		"app.jobs", "Background jobs", func() { g.Jobs() }, // [REQ:BACKGROUND_JOBS] [IMPL:BACKGROUND_JOBS]
+	)
+}
+
+// filerBindings maps the default filer keys to action names; action.Keymap
+// turns it into the filer keymap.
+// [IMPL:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [REQ:ACTION_REGISTRY]
+var filerBindings = map[string]string{
+	"M-C-o":     "workspace.create",
+	"M-C-w":     "workspace.close",
+	"M-f":       "workspace.next",
+	"M-b":       "workspace.prev",
+	"M-W":       "workspace.rename",
+	"C-l":       "workspace.reload",
+	"C-o":       "pane.create",
+	"C-w":       "pane.close",
+	"C-f":       "pane.focus-next",
+	"C-b":       "pane.focus-prev",
+	"right":     "pane.focus-next",
+	"left":      "pane.focus-prev",
+	"C-i":       "pane.focus-next",
+	"l":         "pane.focus-next",
+	"h":         "pane.focus-prev",
+	"F":         "pane.swap-next",
+	"B":         "pane.swap-prev",
+	"w":         "filer.chdir-neighbor",
+	"C-h":       "filer.parent",
+	"backspace": "filer.parent",
+	"u":         "filer.parent",
+	// [IMPL:LINKED_NAVIGATION] Toggle linked navigation with Alt+l or L
+	// (uppercase for macOS compatibility; Option key often produces special chars)
+	"M-l":  "filer.linked-toggle",
+	"L":    "filer.linked-toggle",
+	"~":    "filer.home",
+	"C-n":  "filer.cursor-down",
+	"C-p":  "filer.cursor-up",
+	"down": "filer.cursor-down",
+	"up":   "filer.cursor-up",
+	"j":    "filer.cursor-down",
+	"k":    "filer.cursor-up",
+	"C-d":  "filer.more-down",
+	"C-u":  "filer.more-up",
+	"C-a":  "filer.cursor-top",
+	"C-e":  "filer.cursor-bottom",
+	"home": "filer.cursor-top",
+	"end":  "filer.cursor-bottom",
+	"^":    "filer.cursor-top",
+	"$":    "filer.cursor-bottom",
+	"M-n":  "filer.scroll-down",
+	"M-p":  "filer.scroll-up",
+	"C-v":  "filer.page-down",
+	"M-v":  "filer.page-up",
+	"pgdn": "filer.page-down",
+	"pgup": "filer.page-up",
+	" ":    "filer.mark-toggle",
+	"M-=":  "filer.mark-invert",
+	"C-g":  "filer.reset",
+	"C-[":  "filer.reset", // C-[ means ESC
+	"f":    "filer.finder",
+	"/":    "filer.finder",
+	"q":    "app.quit",
+	"Q":    "app.quit",
+	"?":    "app.help",
+	";":    "app.shell",
+	":":    "app.shell-suspend",
+	"!":    "app.shell-capture", // [REQ:COMMAND_OUTPUT_CAPTURE]
+	"&":    "app.jobs",          // [REQ:BACKGROUND_JOBS]
+	"n":    "file.touch",
+	"K":    "file.mkdir",
+	"c":    "file.copy",
+	"C":    "file.copy-all",
+	"m":    "file.move",
+	"M":    "file.move-all",
+	"r":    "file.rename",
+	"R":    "file.bulk-rename",
+	"D":    "file.remove",
+	"d":    "filer.chdir",
+	"g":    "filer.glob",
+	"G":    "filer.globdir",
+	"[":    "diff.start",
+	"]":    "diff.continue",
+	"{":    "diff.previous",
+	"}":    "diff.results",
+	"|":    "diff.reconcile",
+	// After pressing S, use c/d/r for operations. Press ! first to enable ignore-failures mode.
+	"S": "file.sync",
 }
 
 func finderKeymap(w *filer.Finder) widget.Keymap {
@@ -981,25 +1510,111 @@ func menuKeymap(w *menu.Menu) widget.Keymap {
 	}
 }
 
+// Action names of the widget contexts for the keymaps file, each resolved to
+// the callback of its default key in the keymap functions above. Like the
+// registered filer actions, each name starts with its context. The filer
+// context uses the action registry instead.
+// [IMPL:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [REQ:USER_KEYMAPS]
+var (
+	finderActionKeys = map[string]string{
+		"finder.delete-backward-char": "backspace",
+		"finder.history-prev":         "M-p",
+		"finder.history-next":         "M-n",
+		"finder.exit":                 "C-g",
+	}
+	cmdlineActionKeys = map[string]string{
+		"cmdline.move-top":             "C-a",
+		"cmdline.move-bottom":          "C-e",
+		"cmdline.forward-char":         "C-f",
+		"cmdline.backward-char":        "C-b",
+		"cmdline.forward-word":         "M-f",
+		"cmdline.backward-word":        "M-b",
+		"cmdline.delete-char":          "C-d",
+		"cmdline.delete-backward-char": "C-h",
+		"cmdline.delete-forward-word":  "M-d",
+		"cmdline.delete-backward-word": "M-h",
+		"cmdline.kill-line":            "C-k",
+		"cmdline.complete":             "C-i",
+		"cmdline.run":                  "C-m",
+		"cmdline.exit":                 "C-g",
+		"cmdline.history-down":         "C-n",
+		"cmdline.history-up":           "C-p",
+		"cmdline.history-page-down":    "C-v",
+		"cmdline.history-page-up":      "M-v",
+		"cmdline.history-top":          "M-<",
+		"cmdline.history-bottom":       "M->",
+		"cmdline.history-scroll-down":  "M-n",
+		"cmdline.history-scroll-up":    "M-p",
+		"cmdline.history-delete":       "C-x",
+	}
+	completionActionKeys = map[string]string{
+		"completion.cursor-down":  "C-n",
+		"completion.cursor-up":    "C-p",
+		"completion.cursor-right": "C-f",
+		"completion.cursor-left":  "C-b",
+		"completion.page-down":    "C-v",
+		"completion.page-up":      "M-v",
+		"completion.top":          "M-<",
+		"completion.bottom":       "M->",
+		"completion.scroll-down":  "M-n",
+		"completion.scroll-up":    "M-p",
+		"completion.insert":       "C-m",
+		"completion.exit":         "C-g",
+	}
+	menuActionKeys = map[string]string{
+		"menu.cursor-down": "C-n",
+		"menu.cursor-up":   "C-p",
+		"menu.page-down":   "C-v",
+		"menu.page-up":     "M-v",
+		"menu.top":         "M-<",
+		"menu.bottom":      "M->",
+		"menu.exec":        "C-m",
+		"menu.exit":        "C-g",
+	}
+)
+
+// userKeymap wraps a keymap function so that it applies the user bindings of context.
+// [IMPL:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [REQ:USER_KEYMAPS]
+func userKeymap[T any](keymaps keymapcfg.Config, context string, defaults func(T) widget.Keymap, actionKeys map[string]string) func(T) widget.Keymap {
+	return func(w T) widget.Keymap {
+		km := defaults(w)
+		return keymaps.Apply(context, km, keymapcfg.Actions(km, actionKeys), nil)
+	}
+}
+
+// contextActions returns the named actions of every context for validation.
+// The callbacks of the widget contexts are never called.
+// [IMPL:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [REQ:USER_KEYMAPS]
+func contextActions(filerActions map[string]func()) map[string]map[string]func() {
+	return map[string]map[string]func(){
+		keymapcfg.Filer:      filerActions,
+		keymapcfg.Finder:     keymapcfg.Actions(finderKeymap(nil), finderActionKeys),
+		keymapcfg.Cmdline:    keymapcfg.Actions(cmdlineKeymap(nil), cmdlineActionKeys),
+		keymapcfg.Completion: keymapcfg.Actions(completionKeymap(nil), completionActionKeys),
+		keymapcfg.Menu:       keymapcfg.Actions(menuKeymap(nil), menuActionKeys),
+	}
+}
+
 // runBatchDiffReport runs the batch diff report mode and exits.
 // [IMPL:BATCH_DIFF_REPORT] [ARCH:BATCH_DIFF_REPORT] [REQ:BATCH_DIFF_REPORT]
 func runBatchDiffReport() {
 	dirs := flag.Args()
 	if len(dirs) < 2 {
 		fmt.Fprintln(os.Stderr, "Error: --diff-report requires at least 2 directories")
-		fmt.Fprintln(os.Stderr, "Usage: goful --diff-report dir1 dir2 [dir3 ...]")
+		fmt.Fprintln(os.Stderr, "Usage: goful --diff-report dir1|manifest.yaml dir2 [dir3 ...]")
 		os.Exit(1)
 	}
 
-	// Validate directories exist
+	// Validate directories exist; regular files are loaded as snapshot manifests
+	// [IMPL:DIFF_SNAPSHOT] [ARCH:DIFF_SNAPSHOT] [REQ:DIFF_SNAPSHOT]
 	for _, dir := range dirs {
 		info, err := os.Stat(dir)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: cannot access directory %q: %v\n", dir, err)
 			os.Exit(1)
 		}
-		if !info.IsDir() {
-			fmt.Fprintf(os.Stderr, "Error: %q is not a directory\n", dir)
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			fmt.Fprintf(os.Stderr, "Error: %q is not a directory or manifest\n", dir)
 			os.Exit(1)
 		}
 	}
@@ -1047,8 +1662,9 @@ func runBatchDiffReport() {
 		}()
 	}
 
-	// Run the batch diff search
-	report, err := filer.RunBatchDiffSearch(dirs, progressFn)
+	// Run the batch diff search; the report is identical for any --jobs value
+	// [IMPL:DIFF_PARALLEL] [ARCH:DIFF_PARALLEL] [REQ:DIFF_PARALLEL]
+	report, err := filer.RunParallelDiffSearch(dirs, *jobsFlag, progressFn)
 
 	// Stop progress reporter
 	if progressQuit != nil {
@@ -1076,3 +1692,22 @@ func runBatchDiffReport() {
 	}
 	os.Exit(0)
 }
+
+// runSnapshot writes a manifest of dir to stdout and exits.
+// [IMPL:DIFF_SNAPSHOT] [ARCH:DIFF_SNAPSHOT] [REQ:DIFF_SNAPSHOT]
+func runSnapshot(dir string) {
+	manifest, err := filer.BuildManifest(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(manifest); err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding YAML: %v\n", err)
+		os.Exit(1)
+	}
+	encoder.Close()
+	os.Exit(0)
+}
@@ -3,9 +3,11 @@ package main
 import (
 	"testing"
 
+	"github.com/fareedst/goful/action"
 	"github.com/fareedst/goful/app"
 	"github.com/fareedst/goful/cmdline"
 	"github.com/fareedst/goful/filer"
+	"github.com/fareedst/goful/keymapcfg"
 	"github.com/fareedst/goful/menu"
 	"github.com/fareedst/goful/widget"
 )
@@ -31,8 +33,11 @@ func TestFilerKeymapBaseline_REQ_BEHAVIOR_BASELINE(t *testing.T) {
 		"j", "k", "h", "l", " ", "C-n", "C-p", "C-d", "C-u", "C-a", "C-e",
 		"q", "Q", ":", ";", "f", "/", "n", "K", "c", "m", "r", "R", "D", "d", "g", "G",
 		"[", "]", // [REQ:DIFF_SEARCH] diff search start/continue
+		"{", // [REQ:DIFF_SEARCH_REVERSE] previous difference
+		"}", // [REQ:DIFF_RESULTS_PANEL] diff results panel
+		"|", // [REQ:DIFF_RECONCILE] reconcile panes
 	}
-	km := filerKeymap((*app.Goful)(nil))
+	km := action.Keymap(filerBindings)
 	assertKeysPresent(t, "filer", km, required)
 }
 
@@ -57,3 +62,92 @@ func TestAuxKeymapsBaseline_REQ_BEHAVIOR_BASELINE(t *testing.T) {
 	assertKeysPresent(t, "completion", completionKeymap((*cmdline.Completion)(nil)), completionRequired)
 	assertKeysPresent(t, "menu", menuKeymap((*menu.Menu)(nil)), menuRequired)
 }
+
+// TestActionKeysResolve_REQ_USER_KEYMAPS asserts every named widget action resolves to a default binding.
+// [REQ:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [IMPL:USER_KEYMAPS] [TEST:KEYMAP_BASELINE]
+func TestActionKeysResolve_REQ_USER_KEYMAPS(t *testing.T) {
+	for _, tt := range []struct {
+		label string
+		km    widget.Keymap
+		keys  map[string]string
+	}{
+		{"finder", finderKeymap((*filer.Finder)(nil)), finderActionKeys},
+		{"cmdline", cmdlineKeymap((*cmdline.Cmdline)(nil)), cmdlineActionKeys},
+		{"completion", completionKeymap((*cmdline.Completion)(nil)), completionActionKeys},
+		{"menu", menuKeymap((*menu.Menu)(nil)), menuActionKeys},
+	} {
+		keys := make([]string, 0, len(tt.keys))
+		for _, key := range tt.keys {
+			keys = append(keys, key)
+		}
+		assertKeysPresent(t, tt.label, tt.km, keys)
+	}
+}
+
+// TestLoadedKeymaps_REQ_USER_KEYMAPS asserts a loaded keymaps file overrides and removes
+// bindings in every context while the baseline keys stay bound.
+// [REQ:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [IMPL:USER_KEYMAPS] [TEST:KEYMAP_BASELINE]
+func TestLoadedKeymaps_REQ_USER_KEYMAPS(t *testing.T) {
+	keymaps, err := keymapcfg.Parse([]byte(`
+filer:
+  "C-j": filer.cursor-down
+  "x": "menu:sort"
+  "Q": "-"
+finder:
+  "C-h": "-"
+cmdline:
+  "C-u": cmdline.kill-line
+completion:
+  "j": completion.cursor-down
+menu:
+  "j": menu.cursor-down
+  "q": menu.exit
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	registerActions((*app.Goful)(nil))
+	filerActions := action.Funcs()
+	if errs := keymaps.Validate(contextActions(filerActions), func(name string) bool { return name == "sort" }); len(errs) > 0 {
+		t.Fatalf("Validate: %v", errs)
+	}
+
+	fk := keymaps.Apply(keymapcfg.Filer, action.Keymap(filerBindings), filerActions, func(string) {})
+	assertKeysPresent(t, "user filer", fk, []string{"C-j", "x", "j", "k"})
+	if _, ok := fk["Q"]; ok {
+		t.Error("filer Q should be unbound")
+	}
+	finder := userKeymap(keymaps, keymapcfg.Finder, finderKeymap, finderActionKeys)((*filer.Finder)(nil))
+	if _, ok := finder["C-h"]; ok {
+		t.Error("finder C-h should be unbound")
+	}
+	assertKeysPresent(t, "user finder", finder, []string{"backspace", "C-g"})
+	assertKeysPresent(t, "user cmdline",
+		userKeymap(keymaps, keymapcfg.Cmdline, cmdlineKeymap, cmdlineActionKeys)(&cmdline.Cmdline{}), []string{"C-u", "C-k", "C-m"})
+	assertKeysPresent(t, "user completion",
+		userKeymap(keymaps, keymapcfg.Completion, completionKeymap, completionActionKeys)((*cmdline.Completion)(nil)), []string{"j", "C-n"})
+	assertKeysPresent(t, "user menu",
+		userKeymap(keymaps, keymapcfg.Menu, menuKeymap, menuActionKeys)((*menu.Menu)(nil)), []string{"j", "q", "C-m"})
+}
+
+// TestFilerBindingsRegistered_REQ_ACTION_REGISTRY asserts every default filer key names a
+// registered, documented action.
+// [REQ:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [IMPL:ACTION_REGISTRY] [TEST:KEYMAP_BASELINE]
+func TestFilerBindingsRegistered_REQ_ACTION_REGISTRY(t *testing.T) {
+	registerActions((*app.Goful)(nil))
+	for key, name := range filerBindings {
+		if !action.Exists(name) {
+			t.Errorf("filer key %q names unregistered action %q", key, name)
+		}
+	}
+	for _, a := range action.List() {
+		if a.Doc == "" {
+			t.Errorf("action %q has no description", a.Name)
+		}
+	}
+	for _, name := range []string{"filer.cursor-down", "file.copy", "workspace.create"} {
+		if !action.Exists(name) {
+			t.Errorf("action %q not registered", name)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fareedst/goful/app"
+	"github.com/fareedst/goful/configfile"
+	"github.com/fareedst/goful/configpaths"
+	"github.com/fareedst/goful/filer"
+	"github.com/fareedst/goful/menu"
+)
+
+func TestConfigReload_REQ_CONFIG_RELOAD(t *testing.T) {
+	// [REQ:CONFIG_RELOAD] [ARCH:CONFIG_RELOAD] [IMPL:CONFIG_RELOAD]
+	defer filer.ConfigureExcludedNames(nil, false)
+	dir := t.TempDir()
+	paths := configpaths.Paths{
+		Commands: filepath.Join(dir, "commands.yaml"),
+		Excludes: filepath.Join(dir, "excludes"),
+		Keymaps:  filepath.Join(dir, "keymaps.yaml"),
+	}
+	write := func(path, data string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	g := app.NewGoful("")
+	registerActions(g)
+	menu.Add("test-reload", "a", "built-in", func() {})
+	defer menu.Remove("test-reload")
+	defer menu.Remove("test-reload-extra")
+	r := &configReloader{g: g, paths: paths, menus: menu.Save(), filerKeys: g.Keymap()}
+
+	write(paths.Commands, `
+inheritDefaults: false
+commands:
+  - {menu: test-reload, key: b, label: added, command: "echo b"}
+  - {menu: test-reload-extra, key: x, label: extra, command: "echo x"}
+`)
+	write(paths.Excludes, ".DS_Store\n")
+	write(paths.Keymaps, "filer:\n  M-x: app.help\n")
+	if errs := r.Reload(); len(errs) != 0 {
+		t.Fatalf("Reload errors: %v", errs)
+	}
+	if !menu.Exists("test-reload-extra") || !filer.ExcludedNamesEnabled() {
+		t.Fatalf("reload should add the extra menu and enable excludes")
+	}
+	if _, ok := g.Keymap()["M-x"]; !ok {
+		t.Fatalf("reload should bind M-x from the keymaps file")
+	}
+
+	// Reloading replaces the previous entries instead of appending to them.
+	write(paths.Commands, `
+inheritDefaults: false
+commands:
+  - {menu: test-reload, key: c, label: changed, command: "echo c"}
+`)
+	write(paths.Keymaps, "filer:\n  M-y: app.help\n")
+	if errs := r.Reload(); len(errs) != 0 {
+		t.Fatalf("Reload errors: %v", errs)
+	}
+	if menu.Exists("test-reload-extra") {
+		t.Error("menu removed from the commands file should be gone after reload")
+	}
+	if _, ok := g.Keymap()["M-x"]; ok {
+		t.Error("key removed from the keymaps file should be unbound after reload")
+	}
+	saved := menu.Save()
+
+	// Files with errors are reported and leave the previous settings alone.
+	write(paths.Commands, "commands: [{key: z}]\n")
+	write(paths.Keymaps, "nowhere:\n  M-z: app.help\n")
+	if errs := r.Reload(); len(errs) != 2 {
+		t.Fatalf("Reload errors = %v, want the commands and keymaps errors", errs)
+	}
+	if got := len(menu.Save()["test-reload"]); got != len(saved["test-reload"]) || got != 2 {
+		t.Errorf("test-reload has %d items after a failed reload, want the 2 from before", got)
+	}
+	if _, ok := g.Keymap()["M-y"]; !ok {
+		t.Error("a failed keymaps reload should keep the previous bindings")
+	}
+
+	// Unknown actions are returned with the other errors.
+	write(paths.Commands, `
+inheritDefaults: false
+commands:
+  - {menu: test-reload, key: d, label: unknown, action: no.such-action}
+`)
+	write(paths.Keymaps, "filer:\n  M-y: app.help\n")
+	if errs := r.Reload(); len(errs) != 1 {
+		t.Fatalf("Reload errors = %v, want the unknown action", errs)
+	}
+}
+
+func TestConfigReloadUnifiedConfig_REQ_UNIFIED_CONFIG(t *testing.T) {
+	// [REQ:UNIFIED_CONFIG] [ARCH:UNIFIED_CONFIG] [IMPL:UNIFIED_CONFIG]
+	defer filer.ConfigureExcludedNames(nil, false)
+	dir := t.TempDir()
+	paths := configpaths.Paths{
+		Config:         filepath.Join(dir, "config.yaml"),
+		Excludes:       filepath.Join(dir, "excludes"),
+		ExcludesSource: configpaths.DefaultSource,
+		Keymaps:        filepath.Join(dir, "keymaps.yaml"),
+		KeymapsSource:  "flag:-keymaps",
+	}
+	write := func(path, data string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	g := app.NewGoful("")
+	registerActions(g)
+	r := &configReloader{g: g, paths: paths, menus: menu.Save(), filerKeys: g.Keymap()}
+
+	// Inline sections replace default files; files named by flags win.
+	write(paths.Config, "excludes: [.DS_Store]\nkeymaps: {filer: {M-x: app.help}}\n")
+	write(paths.Keymaps, "filer:\n  M-y: app.help\n")
+	if errs := r.Reload(); len(errs) != 0 {
+		t.Fatalf("Reload errors: %v", errs)
+	}
+	if !filer.ExcludedNamesEnabled() {
+		t.Error("inline excludes should enable the filter")
+	}
+	if _, ok := g.Keymap()["M-y"]; !ok {
+		t.Error("keymaps file named by a flag should be used")
+	}
+	if _, ok := g.Keymap()["M-x"]; ok {
+		t.Error("inline keymaps should not override a flag")
+	}
+
+	// A broken config file is reported and its previous sections stay.
+	write(paths.Config, "theme: neon\n")
+	if errs := r.Reload(); len(errs) != 1 {
+		t.Fatalf("Reload errors = %v, want the config file error", errs)
+	}
+	if !r.settings.Has(configfile.Excludes) || !filer.ExcludedNamesEnabled() {
+		t.Error("a failed config reload should keep the previous sections")
+	}
+}
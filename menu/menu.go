@@ -4,27 +4,113 @@ package menu
 import (
 	"fmt"
 
+	"github.com/fareedst/goful/action"
+	"github.com/fareedst/goful/look"
 	"github.com/fareedst/goful/widget"
+	"github.com/mattn/go-runewidth"
 )
 
 var menusMap = map[string][]*menuItem{}
 
 // Add menu items as label, acceleration key and callback function and
-// the number of arguments `a' must be a multiple of three.
+// the number of arguments `a' must be a multiple of three. The callback may
+// also be the name of a registered action or a Guarded callback.
+// [IMPL:ACTION_REGISTRY] [ARCH:ACTION_REGISTRY] [REQ:ACTION_REGISTRY]
 func Add(name string, a ...interface{}) {
 	if len(a)%3 != 0 {
 		panic("items must be a multiple of three")
 	}
 	items := menusMap[name]
 	for i := 0; i < len(a); i += 3 {
-		accel := a[i].(string)
-		label := a[i+1].(string)
-		callback := a[i+2].(func())
-		items = append(items, &menuItem{accel, label, callback})
+		items = append(items, newItem(a[i].(string), a[i+1].(string), a[i+2]))
 	}
 	menusMap[name] = items
 }
 
+// Guarded is a menu item callback that only applies while Applies reports
+// true, evaluated each time the menu opens. Inapplicable items are hidden,
+// or shown dimmed and not runnable when Disable is set.
+// [IMPL:COMMAND_CONDITIONS] [ARCH:COMMAND_CONDITIONS] [REQ:COMMAND_CONDITIONS]
+type Guarded struct {
+	Callback interface{}
+	Applies  func() bool
+	Disable  bool
+}
+
+func newItem(accel, label string, callback interface{}) *menuItem {
+	item := &menuItem{accel: accel, label: label}
+	if guarded, ok := callback.(Guarded); ok {
+		item.applies = guarded.Applies
+		item.disable = guarded.Disable
+		callback = guarded.Callback
+	}
+	item.callback = action.Callback(callback)
+	return item
+}
+
+// Merge adds menu items like Add, except that an item replaces the item of
+// the menu with the same acceleration key.
+// [IMPL:CONFIG_MENUS] [ARCH:CONFIG_MENUS] [REQ:CONFIG_MENUS]
+func Merge(name string, a ...interface{}) {
+	if len(a)%3 != 0 {
+		panic("items must be a multiple of three")
+	}
+	items := menusMap[name]
+	for i := 0; i < len(a); i += 3 {
+		item := newItem(a[i].(string), a[i+1].(string), a[i+2])
+		replaced := false
+		for j := range items {
+			if items[j].accel == item.accel {
+				items[j] = item
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			items = append(items, item)
+		}
+	}
+	menusMap[name] = items
+}
+
+// Remove deletes the menu and its items.
+// [IMPL:CONFIG_MENUS] [ARCH:CONFIG_MENUS] [REQ:CONFIG_MENUS]
+func Remove(name string) {
+	delete(menusMap, name)
+}
+
+// Exists reports whether a menu has been added under name.
+// [IMPL:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [REQ:USER_KEYMAPS]
+func Exists(name string) bool {
+	_, ok := menusMap[name]
+	return ok
+}
+
+// Snapshot is a saved copy of the defined menus.
+// [IMPL:CONFIG_RELOAD] [ARCH:CONFIG_RELOAD] [REQ:CONFIG_RELOAD]
+type Snapshot map[string][]*menuItem
+
+// Save returns a copy of the defined menus.
+// [IMPL:CONFIG_RELOAD] [ARCH:CONFIG_RELOAD] [REQ:CONFIG_RELOAD]
+func Save() Snapshot {
+	return Snapshot(copyMenus(menusMap))
+}
+
+// Restore replaces the defined menus with those of s.
+// [IMPL:CONFIG_RELOAD] [ARCH:CONFIG_RELOAD] [REQ:CONFIG_RELOAD]
+func Restore(s Snapshot) {
+	menusMap = copyMenus(s)
+}
+
+// copyMenus copies the item lists; items themselves are never modified.
+func copyMenus(menus map[string][]*menuItem) map[string][]*menuItem {
+	copied := make(map[string][]*menuItem, len(menus))
+	for name, items := range menus {
+		copied[name] = append([]*menuItem(nil), items...)
+	}
+	return copied
+}
+
 var keymap func(*Menu) widget.Keymap
 
 // Config the keymap function for a menu.
@@ -36,20 +122,66 @@ type menuItem struct {
 	accel    string
 	label    string
 	callback func()
+	applies  func() bool // [IMPL:COMMAND_CONDITIONS] nil always applies
+	disable  bool        // [IMPL:COMMAND_CONDITIONS] dim instead of hiding
 }
 
 // Menu is a list box to execute for a acceleration key.
 type Menu struct {
 	*widget.ListBox
 	filer widget.Widget
+	items []*row
+}
+
+// row is a menu item as shown when the menu opened.
+// [IMPL:COMMAND_CONDITIONS] [ARCH:COMMAND_CONDITIONS] [REQ:COMMAND_CONDITIONS]
+type row struct {
+	*menuItem
+	enabled bool
+}
+
+// Name returns the row text for ListBox compatibility.
+func (r *row) Name() string { return fmt.Sprintf("%-3s %s", r.accel, r.label) }
+
+// Draw renders the row, dimmed when the item does not apply.
+func (r *row) Draw(x, y, width int, focus bool) {
+	s := runewidth.Truncate(r.Name(), width, "~")
+	s = runewidth.FillRight(s, width)
+	style := look.Default()
+	if !r.enabled {
+		style = style.Dim(true)
+	}
+	if focus {
+		style = style.Reverse(true)
+	}
+	widget.SetCells(x, y, s, style)
+}
+
+// applicableRows evaluates the conditions of items, dropping the
+// inapplicable items that hide.
+// [IMPL:COMMAND_CONDITIONS] [ARCH:COMMAND_CONDITIONS] [REQ:COMMAND_CONDITIONS]
+func applicableRows(items []*menuItem) []*row {
+	rows := make([]*row, 0, len(items))
+	for _, item := range items {
+		enabled := item.applies == nil || item.applies()
+		if !enabled && !item.disable {
+			continue
+		}
+		rows = append(rows, &row{item, enabled})
+	}
+	return rows
 }
 
 // New creates a new menu based on filer widget sizes.
 func New(name string, filer widget.Widget) (*Menu, error) {
-	items, ok := menusMap[name]
+	all, ok := menusMap[name]
 	if !ok {
 		return nil, fmt.Errorf("not found menu `%s'", name)
 	}
+	items := applicableRows(all)
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no applicable items in menu `%s'", name)
+	}
 	x, y := filer.LeftBottom()
 	width := filer.Width()
 	height := len(items) + 2
@@ -59,27 +191,32 @@ func New(name string, filer widget.Widget) (*Menu, error) {
 	menu := &Menu{
 		ListBox: widget.NewListBox(x, y-height+1, width, height, name),
 		filer:   filer,
+		items:   items,
 	}
 	for _, item := range items {
-		s := fmt.Sprintf("%-3s %s", item.accel, item.label)
-		menu.AppendString(s)
+		menu.AppendList(item)
 	}
 	return menu, nil
 }
 
 // Resize the menu window.
 func (w *Menu) Resize(x, y, width, height int) {
-	h := len(menusMap[w.Title()]) + 2
+	h := len(w.items) + 2
 	if max := height / 2; h > max {
 		h = max
 	}
 	w.ListBox.Resize(x, height-h, width, h)
 }
 
-// Exec executes a menu item on the cursor and exits the menu.
+// Exec executes a menu item on the cursor and exits the menu. Dimmed items
+// do nothing.
 func (w *Menu) Exec() {
+	item := w.items[w.Cursor()]
+	if !item.enabled {
+		return
+	}
 	w.Exit()
-	menusMap[w.Title()][w.Cursor()].callback()
+	item.callback()
 }
 
 // Input to the list box or execute a menu item with the acceleration key.
@@ -88,8 +225,8 @@ func (w *Menu) Input(key string) {
 	if callback, ok := keymap[key]; ok {
 		callback()
 	} else {
-		for _, item := range menusMap[w.Title()] {
-			if item.accel == key {
+		for _, item := range w.items {
+			if item.accel == key && item.enabled {
 				w.Exit()
 				item.callback()
 			}
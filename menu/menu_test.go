@@ -0,0 +1,59 @@
+package menu
+
+import "testing"
+
+// TestMergeAndRemove_REQ_CONFIG_MENUS tests that merged items replace items with the same
+// key and that removed menus no longer exist.
+// [REQ:CONFIG_MENUS] [ARCH:CONFIG_MENUS] [IMPL:CONFIG_MENUS]
+func TestMergeAndRemove_REQ_CONFIG_MENUS(t *testing.T) {
+	calls := ""
+	Add("test-sort",
+		"n", "sort name", func() { calls += "name," },
+		"s", "sort size", func() { calls += "size," },
+	)
+	Merge("test-sort",
+		"n", "sort natural", func() { calls += "natural," },
+		"x", "sort extra", func() { calls += "extra," },
+	)
+	items := menusMap["test-sort"]
+	if len(items) != 3 {
+		t.Fatalf("expected 3 items, got %d", len(items))
+	}
+	for _, item := range items {
+		item.callback()
+	}
+	if items[0].label != "sort natural" || calls != "natural,size,extra," {
+		t.Errorf("merged items = %q, calls %q", items[0].label, calls)
+	}
+
+	Remove("test-sort")
+	if Exists("test-sort") {
+		t.Error("removed menu should not exist")
+	}
+}
+
+// TestApplicableRows_REQ_COMMAND_CONDITIONS tests that inapplicable guarded items are
+// hidden or kept disabled.
+// [REQ:COMMAND_CONDITIONS] [ARCH:COMMAND_CONDITIONS] [IMPL:COMMAND_CONDITIONS]
+func TestApplicableRows_REQ_COMMAND_CONDITIONS(t *testing.T) {
+	applies := false
+	Add("test-guarded",
+		"a", "always", func() {},
+		"h", "hidden", Guarded{Callback: func() {}, Applies: func() bool { return applies }},
+		"d", "dimmed", Guarded{Callback: func() {}, Applies: func() bool { return applies }, Disable: true},
+	)
+	defer Remove("test-guarded")
+
+	rows := applicableRows(menusMap["test-guarded"])
+	if len(rows) != 2 || rows[0].accel != "a" || !rows[0].enabled || rows[1].accel != "d" || rows[1].enabled {
+		t.Fatalf("rows while inapplicable = %+v", rows)
+	}
+	applies = true
+	rows = applicableRows(menusMap["test-guarded"])
+	if len(rows) != 3 || !rows[1].enabled || !rows[2].enabled {
+		t.Errorf("rows while applicable = %+v", rows)
+	}
+	if rows[2].Name() != "d   dimmed" {
+		t.Errorf("row name = %q", rows[2].Name())
+	}
+}
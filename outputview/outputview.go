@@ -0,0 +1,286 @@
+// Package outputview provides a popup that shows the output of a command
+// while it runs, with scrolling, search and its exit status.
+// [IMPL:COMMAND_OUTPUT_CAPTURE] [ARCH:COMMAND_OUTPUT_CAPTURE] [REQ:COMMAND_OUTPUT_CAPTURE]
+package outputview
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fareedst/goful/look"
+	"github.com/fareedst/goful/util"
+	"github.com/fareedst/goful/widget"
+	"github.com/mattn/go-runewidth"
+)
+
+// Output limits: the viewer keeps the last MaxLines lines, and a line longer
+// than MaxLineBytes is broken.
+const (
+	MaxLines     = 10000
+	MaxLineBytes = 64 * 1024
+)
+
+// Viewer is a popup list box with one row per output line.
+// [IMPL:COMMAND_OUTPUT_CAPTURE] [ARCH:COMMAND_OUTPUT_CAPTURE] [REQ:COMMAND_OUTPUT_CAPTURE]
+type Viewer struct {
+	*widget.ListBox
+	filer     widget.Widget
+	command   string
+	status    string
+	partial   string // output after the last newline
+	query     string
+	searching bool
+	notFound  bool
+	cancel    func()
+
+	dropped int // lines dropped beyond MaxLines
+}
+
+// New creates a viewer for command based on the screen size. cancel is
+// called by C-c or closing the viewer while the command runs and may be nil.
+// [IMPL:COMMAND_OUTPUT_CAPTURE] [ARCH:COMMAND_OUTPUT_CAPTURE] [REQ:COMMAND_OUTPUT_CAPTURE]
+func New(filer widget.Widget, command string, cancel func()) *Viewer {
+	x, y, width, height := widget.PopupGeometry(-1)
+	v := &Viewer{
+		ListBox: widget.NewListBox(x, y, width, height, ""),
+		filer:   filer,
+		command: command,
+		status:  "running",
+		cancel:  cancel,
+	}
+	v.SetBorderStyle(widget.AllBorder)
+	v.updateTitle()
+	return v
+}
+
+// Resize keeps the viewer centered on the screen.
+func (v *Viewer) Resize(x, y, width, height int) {
+	v.ListBox.Resize(widget.PopupGeometry(-1))
+}
+
+// Append adds output text. Complete lines become rows; the viewer follows
+// new rows while the cursor is on the last one. Only the last MaxLines rows
+// are kept.
+// [IMPL:COMMAND_OUTPUT_CAPTURE] [ARCH:COMMAND_OUTPUT_CAPTURE] [REQ:COMMAND_OUTPUT_CAPTURE]
+func (v *Viewer) Append(text string) {
+	lines := strings.Split(v.partial+text, "\n")
+	v.partial = lines[len(lines)-1]
+	lines = lines[:len(lines)-1]
+	for len(v.partial) > MaxLineBytes {
+		lines = append(lines, v.partial[:MaxLineBytes])
+		v.partial = v.partial[MaxLineBytes:]
+	}
+	v.appendLines(lines)
+}
+
+func (v *Viewer) appendLines(lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	follow := v.Cursor() >= v.Upper()-1
+	for _, s := range lines {
+		s = strings.TrimRight(s, "\r")
+		if i := strings.LastIndexByte(s, '\r'); i >= 0 {
+			s = s[i+1:] // keep the last state of progress lines
+		}
+		v.AppendList(&line{strings.ReplaceAll(s, "\t", "    "), v})
+	}
+	if over := v.Upper() - MaxLines; over > 0 {
+		v.SetList(append([]widget.Drawer(nil), v.List()[over:]...))
+		v.SetCursor(v.Cursor() - over)
+		v.dropped += over
+		v.updateTitle()
+	}
+	if follow {
+		v.MoveBottom()
+	}
+}
+
+// Finish flushes the last partial line and shows status, such as
+// "exit 0", in the title.
+// [IMPL:COMMAND_OUTPUT_CAPTURE] [ARCH:COMMAND_OUTPUT_CAPTURE] [REQ:COMMAND_OUTPUT_CAPTURE]
+func (v *Viewer) Finish(status string) {
+	if v.partial != "" {
+		v.appendLines([]string{v.partial})
+		v.partial = ""
+	}
+	v.status = status
+	v.cancel = nil
+	v.updateTitle()
+}
+
+// Status returns "running" or the status passed to Finish.
+func (v *Viewer) Status() string { return v.status }
+
+// Lines returns the complete output lines that are kept.
+func (v *Viewer) Lines() []string {
+	lines := make([]string, v.Upper())
+	for i, d := range v.List() {
+		lines[i] = d.Name()
+	}
+	return lines
+}
+
+func (v *Viewer) updateTitle() {
+	title := fmt.Sprintf("$ %s (%s)", v.command, v.status)
+	if v.dropped > 0 {
+		title += fmt.Sprintf(" [%d earlier lines dropped]", v.dropped)
+	}
+	switch {
+	case v.searching:
+		title += " /" + v.query
+	case v.notFound:
+		title += " not found: " + v.query
+	}
+	v.SetTitle(title)
+}
+
+// Draw the viewer, including its frame before any output arrives.
+func (v *Viewer) Draw() {
+	if v.Upper() > 0 {
+		v.ListBox.Draw()
+		return
+	}
+	v.Clear()
+	v.Border()
+	x, y := v.LeftTop()
+	widget.SetCells(x, y, v.Title(), look.Title())
+}
+
+// Input handles scrolling, searching and closing. "/" starts a search,
+// "n"/"N" repeat it forwards/backwards and C-c stops a running command
+// while keeping its output on screen.
+// [IMPL:COMMAND_OUTPUT_CAPTURE] [ARCH:COMMAND_OUTPUT_CAPTURE] [REQ:COMMAND_OUTPUT_CAPTURE]
+func (v *Viewer) Input(key string) {
+	if v.searching {
+		v.inputSearch(key)
+		return
+	}
+	switch key {
+	case "q", "Q", "C-g", "C-[":
+		v.Exit()
+	case "C-c":
+		if v.cancel != nil {
+			v.cancel()
+		}
+	case "/":
+		v.searching, v.notFound, v.query = true, false, ""
+		v.updateTitle()
+	case "n":
+		v.Find(1)
+	case "N":
+		v.Find(-1)
+	case "C-n", "down", "j", "C-m":
+		v.MoveCursor(1)
+	case "C-p", "up", "k":
+		v.MoveCursor(-1)
+	case "C-v", "pgdn", " ":
+		v.PageDown()
+	case "M-v", "pgup", "b":
+		v.PageUp()
+	case "C-a", "home", "^", "g":
+		v.MoveTop()
+	case "C-e", "end", "$", "G":
+		v.MoveBottom()
+	case "M-n":
+		v.Scroll(1)
+	case "M-p":
+		v.Scroll(-1)
+	}
+}
+
+func (v *Viewer) inputSearch(key string) {
+	switch key {
+	case "C-m":
+		v.searching = false
+	case "C-g", "C-[":
+		v.searching, v.query = false, ""
+	case "backspace", "C-h":
+		if v.query != "" {
+			_, size := utf8.DecodeLastRuneInString(v.query)
+			v.query = v.query[:len(v.query)-size]
+		}
+	default:
+		if utf8.RuneCountInString(key) == 1 {
+			v.query += key
+			v.Find(0) // incremental: the cursor row may already match
+		}
+	}
+	v.updateTitle()
+}
+
+// Find moves the cursor to the next row containing the search query in
+// direction dir (1 forwards, -1 backwards, 0 from the cursor row),
+// wrapping around.
+// [IMPL:COMMAND_OUTPUT_CAPTURE] [ARCH:COMMAND_OUTPUT_CAPTURE] [REQ:COMMAND_OUTPUT_CAPTURE]
+func (v *Viewer) Find(dir int) bool {
+	n := v.Upper()
+	if v.query == "" || n == 0 {
+		return false
+	}
+	step := dir
+	if step == 0 {
+		step = 1
+	}
+	start := v.Cursor() + dir
+	for i := 0; i < n; i++ {
+		idx := ((start+i*step)%n + n) % n
+		if strings.Contains(v.List()[idx].Name(), v.query) {
+			v.SetCursor(idx)
+			v.notFound = false
+			v.updateTitle()
+			return true
+		}
+	}
+	v.notFound = true
+	v.updateTitle()
+	return false
+}
+
+// Exit closes the viewer and returns to the filer. A running command is
+// cancelled, since nothing else can reach it once the viewer is gone.
+func (v *Viewer) Exit() {
+	if v.cancel != nil {
+		v.cancel()
+	}
+	v.filer.Disconnect()
+}
+
+// Next implements widget.Widget.
+func (v *Viewer) Next() widget.Widget {
+	return widget.Nil()
+}
+
+// Disconnect implements widget.Widget.
+func (v *Viewer) Disconnect() {}
+
+// line draws one output row with search matches highlighted.
+type line struct {
+	text   string
+	viewer *Viewer
+}
+
+// Name returns the row text for ListBox compatibility.
+func (l *line) Name() string { return l.text }
+
+// Draw renders the row.
+func (l *line) Draw(x, y, width int, focus bool) {
+	style, match := look.Default(), look.Highlight()
+	if focus {
+		style, match = style.Reverse(true), match.Reverse(true)
+	}
+	s := runewidth.FillRight(runewidth.Truncate(l.text, width, "~"), width)
+	query := l.viewer.query
+	if query == "" {
+		widget.SetCells(x, y, s, style)
+		return
+	}
+	for _, part := range util.SplitWithSep(s, query) {
+		if part == query {
+			x = widget.SetCells(x, y, part, match)
+		} else {
+			x = widget.SetCells(x, y, part, style)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package outputview
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fareedst/goful/widget"
+)
+
+func newTestViewer() *Viewer {
+	v := &Viewer{ListBox: widget.NewListBox(0, 0, 80, 10, ""), command: "make", status: "running"}
+	v.updateTitle()
+	return v
+}
+
+// TestAppend_REQ_COMMAND_OUTPUT_CAPTURE verifies line splitting, partial lines,
+// progress lines, following the output and the final status.
+// [IMPL:COMMAND_OUTPUT_CAPTURE] [ARCH:COMMAND_OUTPUT_CAPTURE] [REQ:COMMAND_OUTPUT_CAPTURE]
+func TestAppend_REQ_COMMAND_OUTPUT_CAPTURE(t *testing.T) {
+	v := newTestViewer()
+	v.Append("one\ntw")
+	v.Append("o\r\n10%\r50%\r100%\n\tindented\nno newline")
+	want := []string{"one", "two", "100%", "    indented"}
+	if got := v.Lines(); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("lines = %q, want %q", got, want)
+	}
+	if v.Cursor() != 3 {
+		t.Errorf("cursor = %d, want the last line while following", v.Cursor())
+	}
+
+	v.MoveTop()
+	v.Finish("exit 2")
+	if got := v.Lines(); got[len(got)-1] != "no newline" {
+		t.Errorf("Finish should flush the partial line, got %q", got)
+	}
+	if v.Cursor() != 0 {
+		t.Errorf("cursor = %d, want it to stay put after scrolling up", v.Cursor())
+	}
+	if v.Status() != "exit 2" || v.Title() != "$ make (exit 2)" {
+		t.Errorf("status = %q, title = %q", v.Status(), v.Title())
+	}
+}
+
+// TestAppendLimits_REQ_COMMAND_OUTPUT_CAPTURE verifies that only the last MaxLines
+// lines are kept and that overlong lines are broken.
+// [IMPL:COMMAND_OUTPUT_CAPTURE] [ARCH:COMMAND_OUTPUT_CAPTURE] [REQ:COMMAND_OUTPUT_CAPTURE]
+func TestAppendLimits_REQ_COMMAND_OUTPUT_CAPTURE(t *testing.T) {
+	v := newTestViewer()
+	v.Append(strings.Repeat("x\n", MaxLines) + "last\n")
+	lines := v.Lines()
+	if len(lines) != MaxLines || lines[len(lines)-1] != "last" {
+		t.Fatalf("kept %d lines ending in %q, want %d ending in last", len(lines), lines[len(lines)-1], MaxLines)
+	}
+	if v.Cursor() != MaxLines-1 {
+		t.Errorf("cursor = %d, want the last line", v.Cursor())
+	}
+	if !strings.Contains(v.Title(), "[1 earlier lines dropped]") {
+		t.Errorf("title = %q", v.Title())
+	}
+
+	v = newTestViewer()
+	v.Append(strings.Repeat("y", MaxLineBytes+5))
+	if got := v.Lines(); len(got) != 1 || len(got[0]) != MaxLineBytes || v.partial != "yyyyy" {
+		t.Errorf("long line split into %d rows, partial %q", len(got), v.partial)
+	}
+}
+
+// TestSearch_REQ_COMMAND_OUTPUT_CAPTURE verifies incremental search, n/N and cancel keys.
+// [IMPL:COMMAND_OUTPUT_CAPTURE] [ARCH:COMMAND_OUTPUT_CAPTURE] [REQ:COMMAND_OUTPUT_CAPTURE]
+func TestSearch_REQ_COMMAND_OUTPUT_CAPTURE(t *testing.T) {
+	v := newTestViewer()
+	v.Append("alpha\nerror: one\nbeta\nerror: two\n")
+	cancelled := false
+	v.cancel = func() { cancelled = true }
+	v.MoveTop()
+
+	for _, key := range []string{"/", "e", "r", "r", "C-m"} {
+		v.Input(key)
+	}
+	if v.Cursor() != 1 || v.searching {
+		t.Fatalf("cursor = %d searching = %v, want first match and search done", v.Cursor(), v.searching)
+	}
+	v.Input("n")
+	if v.Cursor() != 3 {
+		t.Errorf("n: cursor = %d, want 3", v.Cursor())
+	}
+	v.Input("n")
+	if v.Cursor() != 1 {
+		t.Errorf("n should wrap: cursor = %d, want 1", v.Cursor())
+	}
+	v.Input("N")
+	if v.Cursor() != 3 {
+		t.Errorf("N: cursor = %d, want 3", v.Cursor())
+	}
+
+	for _, key := range []string{"/", "z", "C-m"} {
+		v.Input(key)
+	}
+	if v.Cursor() != 3 || !strings.HasSuffix(v.Title(), "not found: z") {
+		t.Errorf("missing match: cursor = %d title = %q", v.Cursor(), v.Title())
+	}
+
+	v.Input("C-c")
+	if !cancelled {
+		t.Error("C-c should cancel a running command")
+	}
+}
+
+// TestExitCancelsRunning_REQ_COMMAND_OUTPUT_CAPTURE verifies closing the viewer
+// cancels a running command but not a finished one.
+// [IMPL:COMMAND_OUTPUT_CAPTURE] [ARCH:COMMAND_OUTPUT_CAPTURE] [REQ:COMMAND_OUTPUT_CAPTURE]
+func TestExitCancelsRunning_REQ_COMMAND_OUTPUT_CAPTURE(t *testing.T) {
+	cancelled := 0
+	v := newTestViewer()
+	v.filer = widget.Nil()
+	v.cancel = func() { cancelled++ }
+	v.Input("q")
+	if cancelled != 1 {
+		t.Errorf("cancelled %d times on closing a running command, want 1", cancelled)
+	}
+
+	v = newTestViewer()
+	v.filer = widget.Nil()
+	v.cancel = func() { cancelled++ }
+	v.Finish("exit 0")
+	v.Input("q")
+	if cancelled != 1 {
+		t.Errorf("closing a finished command cancelled it")
+	}
+}
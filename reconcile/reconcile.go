@@ -0,0 +1,182 @@
+// Package reconcile provides a popup previewing a reconcile plan before it
+// is executed as a file job.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+package reconcile
+
+import (
+	"fmt"
+
+	"github.com/fareedst/goful/filer"
+	"github.com/fareedst/goful/look"
+	"github.com/fareedst/goful/message"
+	"github.com/fareedst/goful/util"
+	"github.com/fareedst/goful/widget"
+	"github.com/mattn/go-runewidth"
+)
+
+// BuildFunc rebuilds the plan when the preview options change.
+type BuildFunc func(opts filer.ReconcileOptions) (*filer.ReconcilePlan, error)
+
+// Preview is a popup list box showing one row per planned action.
+// s cycles the source policy, x toggles deleting extras, Enter runs the plan.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+type Preview struct {
+	*widget.ListBox
+	filer   widget.Widget
+	plan    *filer.ReconcilePlan
+	build   BuildFunc
+	onApply func(*filer.ReconcilePlan)
+}
+
+// New creates a preview of plan based on filer widget sizes.
+// onApply is called with the plan after the popup closes on confirmation.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+func New(filer widget.Widget, plan *filer.ReconcilePlan, build BuildFunc, onApply func(*filer.ReconcilePlan)) *Preview {
+	p := &Preview{
+		ListBox: widget.NewListBox(0, 0, 1, 1, ""),
+		filer:   filer,
+		build:   build,
+		onApply: onApply,
+	}
+	p.SetBorderStyle(widget.AllBorder)
+	p.setPlan(plan)
+	return p
+}
+
+// setPlan replaces the rows and title with those of plan.
+func (p *Preview) setPlan(plan *filer.ReconcilePlan) {
+	p.plan = plan
+	p.ClearList()
+	lines := Lines(plan)
+	for i, line := range lines {
+		p.AppendList(&row{line, i < len(plan.Actions) && plan.Actions[i].Kind == filer.ReconcileDelete})
+	}
+	p.SetTitle(Title(plan))
+	p.ListBox.Resize(widget.PopupGeometry(p.Upper()))
+	p.MoveTop()
+}
+
+// Resize keeps the popup centered on the screen.
+func (p *Preview) Resize(x, y, width, height int) {
+	p.ListBox.Resize(widget.PopupGeometry(p.Upper()))
+}
+
+// Input handles keyboard input for the preview.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+func (p *Preview) Input(key string) {
+	switch key {
+	case "q", "Q", "n", "C-g", "C-[":
+		p.Exit()
+	case "C-m", "y":
+		p.Apply()
+	case "s":
+		opts := p.plan.Options
+		opts.Source = opts.Source.Next()
+		p.rebuild(opts)
+	case "x":
+		opts := p.plan.Options
+		opts.DeleteExtras = !opts.DeleteExtras
+		p.rebuild(opts)
+	case "C-n", "down", "j":
+		p.MoveCursor(1)
+	case "C-p", "up", "k":
+		p.MoveCursor(-1)
+	case "C-v", "pgdn":
+		p.PageDown()
+	case "M-v", "pgup":
+		p.PageUp()
+	case "C-a", "home", "^":
+		p.MoveTop()
+	case "C-e", "end", "$":
+		p.MoveBottom()
+	}
+}
+
+// rebuild replans with opts, keeping the current plan when that fails.
+func (p *Preview) rebuild(opts filer.ReconcileOptions) {
+	plan, err := p.build(opts)
+	if err != nil {
+		message.Error(err)
+		return
+	}
+	p.setPlan(plan)
+}
+
+// Apply closes the preview and runs the plan when it has any actions.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+func (p *Preview) Apply() {
+	p.Exit()
+	if len(p.plan.Actions) == 0 {
+		message.Infof("Nothing to reconcile for %s", p.plan.Name)
+		return
+	}
+	if p.onApply != nil {
+		p.onApply(p.plan)
+	}
+}
+
+// Exit closes the preview and returns to the filer.
+func (p *Preview) Exit() {
+	p.filer.Disconnect()
+}
+
+// Next implements widget.Widget.
+func (p *Preview) Next() widget.Widget {
+	return widget.Nil()
+}
+
+// Disconnect implements widget.Widget.
+func (p *Preview) Disconnect() {}
+
+// row draws one plan line; deletions use the message error style.
+type row struct {
+	text   string
+	delete bool
+}
+
+// Name returns the row text for ListBox compatibility.
+func (r *row) Name() string { return r.text }
+
+// Draw renders the row.
+func (r *row) Draw(x, y, width int, focus bool) {
+	style := look.Default()
+	if r.delete {
+		style = look.MessageError()
+	}
+	if focus {
+		style = style.Reverse(true)
+	}
+	s := runewidth.FillRight(runewidth.Truncate(r.text, width, "~"), width)
+	widget.SetCells(x, y, s, style)
+}
+
+// Title summarizes the plan source, policy and keys.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+func Title(plan *filer.ReconcilePlan) string {
+	extras := "keep"
+	if plan.Options.DeleteExtras {
+		extras = "delete"
+	}
+	return fmt.Sprintf("Reconcile %s from window %d (%s) - [s]ource [x]extras:%s [Enter]run [q]uit",
+		plan.Name, plan.Source+1, plan.Options.Source, extras)
+}
+
+// Lines renders one row per action, then one per skipped target.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+func Lines(plan *filer.ReconcilePlan) []string {
+	lines := make([]string, 0, len(plan.Actions)+len(plan.Skipped)+1)
+	for _, a := range plan.Actions {
+		path := util.AbbrPath(a.Dst)
+		if a.IsDir {
+			path += "/"
+		}
+		lines = append(lines, fmt.Sprintf("%-6s [%d] %s", a.Kind, a.Window+1, path))
+	}
+	for _, s := range plan.Skipped {
+		lines = append(lines, fmt.Sprintf("%-6s [%d] %s (%s)", "skip", s.Window+1, util.AbbrPath(s.Path), s.Reason))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "Nothing to do: every window already matches the source")
+	}
+	return lines
+}
@@ -0,0 +1,43 @@
+package reconcile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fareedst/goful/filer"
+)
+
+// TestLines_REQ_DIFF_RECONCILE verifies rows list actions, then skipped targets.
+// [IMPL:DIFF_RECONCILE] [ARCH:DIFF_RECONCILE] [REQ:DIFF_RECONCILE]
+func TestLines_REQ_DIFF_RECONCILE(t *testing.T) {
+	plan := &filer.ReconcilePlan{
+		Name: "d",
+		Actions: []filer.ReconcileAction{
+			{Kind: filer.ReconcileCopy, Window: 1, Dst: "/b/d/sub", IsDir: true},
+			{Kind: filer.ReconcileDelete, Window: 1, Dst: "/b/d/extra.txt"},
+		},
+		Skipped: []filer.ReconcileSkip{{Window: 2, Path: "/c/d", Reason: "file/directory conflict"}},
+	}
+	got := Lines(plan)
+	want := []string{
+		"copy   [2] /b/d/sub/",
+		"delete [2] /b/d/extra.txt",
+		"skip   [3] /c/d (file/directory conflict)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Lines() = %q, want %q", got, want)
+	}
+
+	if got := Lines(&filer.ReconcilePlan{}); len(got) != 1 || !strings.HasPrefix(got[0], "Nothing to do") {
+		t.Errorf("expected a placeholder row for an empty plan, got %q", got)
+	}
+}
+
+// TestTitle_REQ_DIFF_RECONCILE verifies the title shows source window, policy and extras mode.
+func TestTitle_REQ_DIFF_RECONCILE(t *testing.T) {
+	plan := &filer.ReconcilePlan{Name: "a.txt", Source: 2, Options: filer.ReconcileOptions{Source: filer.ReconcileLargest, DeleteExtras: true}}
+	got := Title(plan)
+	if !strings.Contains(got, "a.txt from window 3 (largest)") || !strings.Contains(got, "extras:delete") {
+		t.Errorf("unexpected title %q", got)
+	}
+}
@@ -0,0 +1,35 @@
+//go:build !windows
+// +build !windows
+
+package util
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestKillProcessGroup_REQ_COMMAND_OUTPUT_CAPTURE verifies that killing the group
+// also stops the children of the shell, so Wait returns once the pipes close.
+// [IMPL:COMMAND_OUTPUT_CAPTURE] [ARCH:COMMAND_OUTPUT_CAPTURE] [REQ:COMMAND_OUTPUT_CAPTURE]
+func TestKillProcessGroup_REQ_COMMAND_OUTPUT_CAPTURE(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "sleep 30 & sleep 30")
+	var out strings.Builder
+	cmd.Stdout = &out // a pipe the background sleep inherits
+	SetProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	time.Sleep(100 * time.Millisecond)
+	if err := KillProcessGroup(cmd); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Wait still blocked: a child kept the output pipe open")
+	}
+}
@@ -0,0 +1,17 @@
+//go:build windows
+// +build windows
+
+package util
+
+import "os/exec"
+
+// SetProcessGroup does nothing on Windows.
+func SetProcessGroup(cmd *exec.Cmd) {}
+
+// KillProcessGroup kills the process of cmd on Windows.
+func KillProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	return cmd.Process.Kill()
+}
@@ -2,6 +2,9 @@
 package widget
 
 import (
+	"strings"
+	"unicode/utf8"
+
 	"github.com/fareedst/goful/look"
 	"github.com/gdamore/tcell/v2"
 	"github.com/mattn/go-runewidth"
@@ -280,6 +283,25 @@ func EventToString(ev *tcell.EventKey) string {
 	return string(ev.Rune())
 }
 
+// IsKeyString reports whether s is a key string EventToString can return:
+// a single character, a named key such as "C-a" or "pgdn", or either of
+// those prefixed with "M-".
+// [IMPL:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [REQ:USER_KEYMAPS]
+func IsKeyString(s string) bool {
+	if rest := strings.TrimPrefix(s, "M-"); rest != s && rest != "" {
+		s = rest
+	}
+	if utf8.RuneCountInString(s) == 1 {
+		return true
+	}
+	for _, key := range keyToSting {
+		if key == s {
+			return true
+		}
+	}
+	return false
+}
+
 var screen tcell.Screen
 
 // Init initializes the tcell screen with mouse support enabled.
@@ -330,6 +352,25 @@ func Size() (width, height int) {
 	return screen.Size()
 }
 
+// PopupGeometry returns the position and size of a popup centered on the
+// screen: about 80% of it, and no taller than rows lines plus the border.
+// A negative rows leaves the height at 80%.
+func PopupGeometry(rows int) (x, y, width, height int) {
+	screenWidth, screenHeight := Size()
+	width = screenWidth * 80 / 100
+	height = screenHeight * 80 / 100
+	if width < 40 {
+		width = screenWidth - 4
+	}
+	if height < 10 {
+		height = screenHeight - 4
+	}
+	if max := rows + 2; rows >= 0 && height > max {
+		height = max
+	}
+	return (screenWidth - width) / 2, (screenHeight - height) / 2, width, height
+}
+
 // ShowCursor shows the cursor at x, y.
 func ShowCursor(x, y int) {
 	screen.ShowCursor(x, y)
@@ -142,3 +142,18 @@ func TestWindowContains_REQ_MOUSE_FILE_SELECT(t *testing.T) {
 		})
 	}
 }
+
+// TestIsKeyString_REQ_USER_KEYMAPS tests key strings accepted in the keymaps file.
+// [REQ:USER_KEYMAPS] [ARCH:USER_KEYMAPS] [IMPL:USER_KEYMAPS]
+func TestIsKeyString_REQ_USER_KEYMAPS(t *testing.T) {
+	for _, s := range []string{"j", " ", "$", "C-a", "C-[", "M-f", "M-C-o", "M-=", "pgdn", "backspace", "f12", "あ", "M--"} {
+		if !IsKeyString(s) {
+			t.Errorf("IsKeyString(%q) = false, want true", s)
+		}
+	}
+	for _, s := range []string{"", "jj", "C-", "Ctrl-a", "M-jj", "page-down"} {
+		if IsKeyString(s) {
+			t.Errorf("IsKeyString(%q) = true, want false", s)
+		}
+	}
+}